@@ -1,86 +1,71 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	_ "time/tzdata" // society time zones must load without the host zoneinfo
 
 	"github.com/VinVorteX/flashtrack/config"
-	"github.com/VinVorteX/flashtrack/internal/controllers"
-	"github.com/VinVorteX/flashtrack/internal/middleware"
-	"github.com/VinVorteX/flashtrack/pkg/database"
-	"github.com/gin-contrib/cors"
+	"github.com/VinVorteX/flashtrack/internal/app"
+	"github.com/VinVorteX/flashtrack/internal/logging"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/spf13/pflag"
 )
 
 func main() {
+	// Log JSON from the start; the configured level applies once the configuration is loaded
+	slog.SetDefault(logging.New(os.Stdout, slog.LevelInfo))
+
 	// Load .env file
 	if err := godotenv.Load(); err != nil {
-		log.Println("Warning: .env file not found, using system environment variables")
+		slog.Warn(".env file not found, using system environment variables")
 	}
 
-	cfg := config.LoadConfig()
-	database.Connect(*cfg)
-
-	r := gin.Default()
-
-	// CORS middleware
-	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:3000", "http://localhost:5173", "http://16.171.235.183:3000"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-	}))
-
-	auth := r.Group("/auth")
-	{
-		auth.POST("/register", controllers.Register)
-		auth.POST("/login", controllers.Login)
+	cfg, err := config.Load(os.Args[1:], os.LookupEnv)
+	if errors.Is(err, pflag.ErrHelp) {
+		os.Exit(0)
 	}
+	if err != nil {
+		fatal("failed to load configuration", err)
+	}
+	slog.SetDefault(logging.New(os.Stdout, cfg.Log.SlogLevel()))
 
-	api := r.Group("/api")
-	api.Use(middleware.AuthMiddleware(), middleware.TenantMiddleware())
-
-	// WebSocket endpoint for real-time notifications
-	api.GET("/ws/notifications", controllers.WebSocketHandler)
-
-	// Notification routes
-	api.GET("/notifications", controllers.GetNotifications)
-	api.POST("/notifications/read", controllers.MarkNotificationRead)
-
-	// Complaint routes - accessible to all authenticated users
-	api.GET("/complaints", controllers.GetComplaints)
-
-	// User-only routes
-	userRoutes := api.Group("")
-	userRoutes.Use(middleware.RoleMiddleware("user"))
-	{
-		userRoutes.POST("/complaints", controllers.CreateComplaint)
+	// Keep gin's debug-mode output in the JSON log too
+	gin.DebugPrintFunc = func(format string, values ...any) {
+		slog.Debug(strings.TrimSpace(fmt.Sprintf(format, values...)))
+	}
+	gin.DebugPrintRouteFunc = func(method, path, handler string, handlers int) {
+		slog.Debug("route registered", "method", method, "path", path, "handler", handler)
 	}
 
-	// Staff routes
-	staffRoutes := api.Group("/staff")
-	staffRoutes.Use(middleware.RoleMiddleware("staff"))
-	{
-		staffRoutes.PUT("/resolve/:id", controllers.ResolveComplaint)
-		staffRoutes.GET("/points", controllers.GetStaffPoints)
+	a, err := app.New(*cfg)
+	if err != nil {
+		fatal("failed to start", err)
 	}
 
-	// Feedback routes
-	api.POST("/feedback", controllers.SubmitFeedback)
-	api.POST("/feedback/check", controllers.CheckPendingFeedback)
-	api.GET("/feedback", middleware.RoleMiddleware("admin"), controllers.GetFeedbackForAdmin)
+	// Stop on Ctrl-C or the orchestrator's SIGTERM, after draining in-flight requests
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// Admin/Staff routes
-	adminRoutes := api.Group("/admin")
-	adminRoutes.Use(middleware.RoleMiddleware("admin", "staff"))
-	{
-		adminRoutes.PUT("/assign", controllers.AssignStaff)
-		adminRoutes.GET("/stats", controllers.GetSocietyStats)
+	err = a.Run(ctx)
+	if closeErr := a.Close(); closeErr != nil {
+		slog.Error("failed to close", "error", closeErr)
 	}
+	if err != nil {
+		fatal("server stopped", err)
+	}
+	slog.Info("server stopped")
+}
 
-	// Staff list endpoint for admins
-	api.GET("/staff", controllers.GetStaffMembers)
-
-	r.Run(":8080")
+// fatal logs err and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
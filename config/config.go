@@ -1,17 +1,134 @@
+// Package config loads the server configuration once at startup.
+//
+// Settings are layered, each layer overriding the one before it:
+//
+//  1. the defaults in Default
+//  2. YAML (.yaml, .yml) or TOML (.toml) files, in the order given by --config or the
+//     comma-separated FLASHTRACK_CONFIG
+//  3. environment variables, such as DB_URL and JWT_SECRET
+//  4. command-line flags, such as --port
+//
+// Load validates the result, so a misconfigured server refuses to start instead of failing on
+// the first request. The Config is then passed to whatever needs it; nothing reads the
+// environment later.
 package config
 
-import "os"
+import (
+	"log/slog"
+	"time"
+)
 
+// MinJWTSecretLength is the shortest JWT signing secret Validate accepts
+const MinJWTSecretLength = 32
+
+// Config is the complete server configuration. Every leaf field carries its file key (yaml and
+// toml tags), its environment variable (env) and its flag (flag, "-" for secrets that must not
+// appear in the process list).
 type Config struct {
-	DBUrl string
-	Port string
-	JWTSecret string
+	// AppURL is the frontend address used in emailed links
+	AppURL string `yaml:"app_url" toml:"app_url" env:"APP_URL" flag:"app-url" usage:"frontend URL used in invite links"`
+
+	Server     ServerConfig     `yaml:"server" toml:"server"`
+	Database   DatabaseConfig   `yaml:"database" toml:"database"`
+	Auth       AuthConfig       `yaml:"auth" toml:"auth"`
+	Mail       MailConfig       `yaml:"mail" toml:"mail"`
+	Push       PushConfig       `yaml:"push" toml:"push"`
+	Uploads    UploadConfig     `yaml:"uploads" toml:"uploads"`
+	Scheduler  SchedulerConfig  `yaml:"scheduler" toml:"scheduler"`
+	SuperAdmin SuperAdminConfig `yaml:"superadmin" toml:"superadmin"`
+	Log        LogConfig        `yaml:"log" toml:"log"`
+	Tracing    TracingConfig    `yaml:"tracing" toml:"tracing"`
+}
+
+// ServerConfig covers the HTTP listener and the browser origins it accepts
+type ServerConfig struct {
+	Port             string        `yaml:"port" toml:"port" env:"PORT" flag:"port" usage:"HTTP port"`
+	CORSOrigins      []string      `yaml:"cors_origins" toml:"cors_origins" env:"CORS_ORIGINS" flag:"cors-origins" usage:"comma-separated origins allowed by CORS"`
+	WebSocketOrigins []string      `yaml:"websocket_origins" toml:"websocket_origins" env:"WEBSOCKET_ORIGINS" flag:"websocket-origins" usage:"comma-separated origins allowed to open notification WebSockets"`
+	ShutdownTimeout  time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" flag:"shutdown-timeout" usage:"how long in-flight requests may take to finish on shutdown"`
+}
+
+// DatabaseConfig locates the Postgres database and sizes the connection pool. Every API request
+// holds one connection for its tenant session, so MaxOpenConns also caps concurrent requests.
+type DatabaseConfig struct {
+	URL             string        `yaml:"url" toml:"url" env:"DB_URL" flag:"-"`
+	MaxOpenConns    int64         `yaml:"max_open_conns" toml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" flag:"db-max-open-conns" usage:"most database connections open at once"`
+	MaxIdleConns    int64         `yaml:"max_idle_conns" toml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" flag:"db-max-idle-conns" usage:"most idle database connections kept for reuse"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" flag:"db-conn-max-lifetime" usage:"how long a database connection is reused before it is replaced; 0 keeps it forever"`
+}
+
+// AuthConfig covers session tokens
+type AuthConfig struct {
+	JWTSecret string        `yaml:"jwt_secret" toml:"jwt_secret" env:"JWT_SECRET" flag:"-"`
+	TokenTTL  time.Duration `yaml:"token_ttl" toml:"token_ttl" env:"TOKEN_TTL" flag:"token-ttl" usage:"how long a login token stays valid"`
+}
+
+// MailConfig is the SMTP relay. Without a host, email is written to the log instead.
+type MailConfig struct {
+	SMTPHost     string `yaml:"smtp_host" toml:"smtp_host" env:"SMTP_HOST" flag:"smtp-host" usage:"SMTP relay host; empty logs email instead"`
+	SMTPPort     string `yaml:"smtp_port" toml:"smtp_port" env:"SMTP_PORT" flag:"smtp-port" usage:"SMTP relay port"`
+	SMTPUser     string `yaml:"smtp_user" toml:"smtp_user" env:"SMTP_USER" flag:"smtp-user" usage:"SMTP user"`
+	SMTPPassword string `yaml:"smtp_password" toml:"smtp_password" env:"SMTP_PASSWORD" flag:"-"`
+	From         string `yaml:"from" toml:"from" env:"SMTP_FROM" flag:"smtp-from" usage:"sender address of outgoing email"`
+}
+
+// PushConfig is the Firebase project push notifications are sent through. Without credentials,
+// push notifications are written to the log instead.
+type PushConfig struct {
+	FCMCredentialsFile string `yaml:"fcm_credentials_file" toml:"fcm_credentials_file" env:"FCM_CREDENTIALS_FILE" flag:"fcm-credentials-file" usage:"Firebase service account key (JSON); empty logs push notifications instead"`
+}
+
+// UploadConfig limits request bodies that carry files
+type UploadConfig struct {
+	MaxImportBytes int64 `yaml:"max_import_bytes" toml:"max_import_bytes" env:"MAX_IMPORT_BYTES" flag:"max-import-bytes" usage:"largest accepted CSV import, in bytes"`
+}
+
+// SchedulerConfig sets how often background jobs run
+type SchedulerConfig struct {
+	EscalationInterval time.Duration `yaml:"escalation_interval" toml:"escalation_interval" env:"ESCALATION_INTERVAL" flag:"escalation-interval" usage:"how often SLA escalation policies are evaluated"`
+}
+
+// SuperAdminConfig is the platform account created on first start, if set
+type SuperAdminConfig struct {
+	Email    string `yaml:"email" toml:"email" env:"SUPERADMIN_EMAIL" flag:"superadmin-email" usage:"platform super-admin created on first start"`
+	Password string `yaml:"password" toml:"password" env:"SUPERADMIN_PASSWORD" flag:"-"`
+}
+
+// LogConfig controls the JSON log written to stdout
+type LogConfig struct {
+	Level string `yaml:"level" toml:"level" env:"LOG_LEVEL" flag:"log-level" usage:"lowest level logged: debug, info, warn or error"`
 }
 
-func LoadConfig() *Config{
-	return &Config{
-		DBUrl: os.Getenv("DB_URL"),
-		Port: os.Getenv("PORT"),
-		JWTSecret: os.Getenv("JWT_SECRET"),
+// SlogLevel returns the configured level, info if it does not parse
+func (c LogConfig) SlogLevel() slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Level)); err != nil {
+		return slog.LevelInfo
 	}
-}
\ No newline at end of file
+	return level
+}
+
+// TracingConfig exports OpenTelemetry traces over OTLP/HTTP. Without an endpoint tracing is off.
+type TracingConfig struct {
+	Endpoint    string  `yaml:"otlp_endpoint" toml:"otlp_endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT" flag:"otlp-endpoint" usage:"OTLP/HTTP collector URL traces are sent to; empty disables tracing"`
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio" env:"TRACE_SAMPLE_RATIO" flag:"trace-sample-ratio" usage:"fraction of new traces sampled, from 0 to 1"`
+}
+
+// Default returns the settings used where no layer sets a value
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:             "8080",
+			CORSOrigins:      []string{"http://localhost:3000", "http://localhost:5173", "http://16.171.235.183:3000"},
+			WebSocketOrigins: []string{"http://localhost:3000", "http://localhost:5173"},
+			ShutdownTimeout:  30 * time.Second,
+		},
+		Database:  DatabaseConfig{MaxOpenConns: 25, MaxIdleConns: 10, ConnMaxLifetime: 30 * time.Minute},
+		Auth:      AuthConfig{TokenTTL: 24 * time.Hour},
+		Mail:      MailConfig{SMTPPort: "587"},
+		Uploads:   UploadConfig{MaxImportBytes: 5 << 20},
+		Scheduler: SchedulerConfig{EscalationInterval: 5 * time.Minute},
+		Log:       LogConfig{Level: "info"},
+		Tracing:   TracingConfig{SampleRatio: 1},
+	}
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+// env returns a lookup over a fixed set of variables, with the required ones filled in
+func env(vars map[string]string) func(string) (string, bool) {
+	all := map[string]string{"DB_URL": "postgres://localhost/flashtrack", "JWT_SECRET": testSecret}
+	for k, v := range vars {
+		all[k] = v
+	}
+	return func(key string) (string, bool) {
+		v, ok := all[key]
+		return v, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := Load(nil, env(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Port != "8080" || cfg.Auth.TokenTTL != 24*time.Hour || cfg.Scheduler.EscalationInterval != 5*time.Minute {
+		t.Errorf("defaults not applied: %+v", cfg)
+	}
+	if len(cfg.Server.WebSocketOrigins) == 0 || cfg.Uploads.MaxImportBytes != 5<<20 {
+		t.Errorf("defaults not applied: %+v", cfg)
+	}
+}
+
+func TestLoadLayersFilesEnvAndFlags(t *testing.T) {
+	yamlFile := writeFile(t, "base.yaml", `
+server:
+  port: "9000"
+  cors_origins: [https://app.example.com]
+  shutdown_timeout: 10s
+scheduler:
+  escalation_interval: 1m
+uploads:
+  max_import_bytes: 1024
+`)
+	tomlFile := writeFile(t, "override.toml", `
+[server]
+port = "9100"
+
+[scheduler]
+escalation_interval = "2m"
+`)
+
+	cfg, err := Load(
+		[]string{"--config", yamlFile, "--config", tomlFile, "--escalation-interval", "30s"},
+		env(map[string]string{"PORT": "9200", "MAX_IMPORT_BYTES": "2048", "SMTP_HOST": ""}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Server.CORSOrigins[0] != "https://app.example.com" || cfg.Server.ShutdownTimeout != 10*time.Second {
+		t.Errorf("YAML values not applied: %+v", cfg.Server)
+	}
+	if cfg.Server.Port != "9200" {
+		t.Errorf("port = %q, want the environment to override both files", cfg.Server.Port)
+	}
+	if cfg.Uploads.MaxImportBytes != 2048 {
+		t.Errorf("max import = %d, want the environment's 2048", cfg.Uploads.MaxImportBytes)
+	}
+	if cfg.Scheduler.EscalationInterval != 30*time.Second {
+		t.Errorf("escalation interval = %v, want the flag's 30s", cfg.Scheduler.EscalationInterval)
+	}
+	if cfg.Mail.SMTPHost != "" {
+		t.Errorf("an empty SMTP_HOST set the host to %q", cfg.Mail.SMTPHost)
+	}
+}
+
+func TestLoadFilesFromEnvironment(t *testing.T) {
+	file := writeFile(t, "flashtrack.yml", "app_url: https://flashtrack.example.com\n")
+	cfg, err := Load(nil, env(map[string]string{FilesEnv: file}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.AppURL != "https://flashtrack.example.com" {
+		t.Errorf("app URL = %q, want the file's", cfg.AppURL)
+	}
+}
+
+func TestLoadRejectsUnknownFileKeys(t *testing.T) {
+	for name, content := range map[string]string{
+		"typo.yaml": "server:\n  prot: \"9000\"\n",
+		"typo.toml": "[server]\nprot = \"9000\"\n",
+	} {
+		if _, err := Load([]string{"--config", writeFile(t, name, content)}, env(nil)); err == nil {
+			t.Errorf("%s: expected an error for the unknown key", name)
+		}
+	}
+}
+
+func TestSecretsHaveNoFlags(t *testing.T) {
+	_, err := Load([]string{"--jwt-secret", testSecret}, env(nil))
+	if err == nil || !strings.Contains(err.Error(), "unknown flag") {
+		t.Errorf("expected --jwt-secret to be refused, got %v", err)
+	}
+}
+
+func TestLoadHelp(t *testing.T) {
+	if _, err := Load([]string{"--help"}, env(nil)); !errors.Is(err, pflag.ErrHelp) {
+		t.Errorf("expected pflag.ErrHelp, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := map[string]struct {
+		vars map[string]string
+		want string
+	}{
+		"missing database":  {map[string]string{"DB_URL": ""}, "DB_URL is required"},
+		"no connections":    {map[string]string{"DB_MAX_OPEN_CONNS": "0"}, "DB_MAX_OPEN_CONNS"},
+		"idle above open":   {map[string]string{"DB_MAX_OPEN_CONNS": "5", "DB_MAX_IDLE_CONNS": "6"}, "DB_MAX_IDLE_CONNS"},
+		"negative lifetime": {map[string]string{"DB_CONN_MAX_LIFETIME": "-1m"}, "DB_CONN_MAX_LIFETIME"},
+		"short secret":      {map[string]string{"JWT_SECRET": "too-short"}, "JWT_SECRET must be at least 32"},
+		"bad port":          {map[string]string{"PORT": "http"}, "PORT"},
+		"origin with path":  {map[string]string{"CORS_ORIGINS": "https://app.example.com/login"}, "CORS_ORIGINS"},
+		"bare host origin":  {map[string]string{"WEBSOCKET_ORIGINS": "app.example.com"}, "WEBSOCKET_ORIGINS"},
+		"smtp without from": {map[string]string{"SMTP_HOST": "smtp.example.com", "APP_URL": "https://app.example.com"}, "SMTP_FROM"},
+		"smtp without url":  {map[string]string{"SMTP_HOST": "smtp.example.com", "SMTP_FROM": "noreply@example.com"}, "APP_URL is required"},
+		"missing fcm key":   {map[string]string{"FCM_CREDENTIALS_FILE": "/nonexistent/firebase.json"}, "FCM_CREDENTIALS_FILE"},
+		"half a superadmin": {map[string]string{"SUPERADMIN_EMAIL": "ops@example.com"}, "set together"},
+		"zero interval":     {map[string]string{"ESCALATION_INTERVAL": "0s"}, "ESCALATION_INTERVAL"},
+		"unknown log level": {map[string]string{"LOG_LEVEL": "verbose"}, "LOG_LEVEL"},
+		"bare otlp host":    {map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "collector:4318"}, "OTEL_EXPORTER_OTLP_ENDPOINT"},
+		"ratio above one":   {map[string]string{"TRACE_SAMPLE_RATIO": "1.5"}, "TRACE_SAMPLE_RATIO"},
+	}
+	for name, c := range cases {
+		_, err := Load(nil, env(c.vars))
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%s: expected an error mentioning %q, got %v", name, c.want, err)
+		}
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	_, err := Load(nil, func(string) (string, bool) { return "", false })
+	if err == nil || !strings.Contains(err.Error(), "DB_URL") || !strings.Contains(err.Error(), "JWT_SECRET") {
+		t.Errorf("expected both missing settings reported, got %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// FilesEnv lists configuration files, comma separated, when --config is not given
+const FilesEnv = "FLASHTRACK_CONFIG"
+
+// Load builds the configuration from the defaults, the configuration files, the environment
+// (read through lookupEnv, normally os.LookupEnv) and the command-line args, then validates it.
+// With --help it prints the flags and returns pflag.ErrHelp.
+func Load(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	cfg := Default()
+	fields := leaves(reflect.ValueOf(&cfg).Elem())
+
+	flags := pflag.NewFlagSet("flashtrack", pflag.ContinueOnError)
+	files := flags.StringSlice("config", nil, "YAML or TOML configuration file; repeat to layer several (env "+FilesEnv+")")
+	for _, f := range fields {
+		if f.flag != "-" {
+			flags.String(f.flag, f.String(), f.usage+" (env "+f.env+")")
+		}
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if !flags.Changed("config") {
+		*files = nil
+		if list, ok := lookupEnv(FilesEnv); ok && list != "" {
+			*files = strings.Split(list, ",")
+		}
+	}
+	for _, path := range *files {
+		if err := loadFile(&cfg, strings.TrimSpace(path)); err != nil {
+			return nil, err
+		}
+	}
+
+	// An empty variable counts as unset, as docker-compose passes through unset ones empty
+	for _, f := range fields {
+		if value, ok := lookupEnv(f.env); ok && value != "" {
+			if err := f.Set(value); err != nil {
+				return nil, fmt.Errorf("%s: %w", f.env, err)
+			}
+		}
+	}
+
+	for _, f := range fields {
+		if f.flag != "-" && flags.Changed(f.flag) {
+			if err := f.Set(flags.Lookup(f.flag).Value.String()); err != nil {
+				return nil, fmt.Errorf("--%s: %w", f.flag, err)
+			}
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// loadFile decodes a YAML or TOML file over cfg, so it only changes the keys it sets. Unknown
+// keys are rejected to catch typos.
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("%s: unknown key %s", path, undecoded[0])
+		}
+	default:
+		return fmt.Errorf("%s: configuration files must be .yaml, .yml or .toml", path)
+	}
+	return nil
+}
+
+// field is a settable leaf of Config together with its environment variable and flag
+type field struct {
+	value reflect.Value
+	env   string
+	flag  string
+	usage string
+}
+
+// leaves lists the tagged leaf fields of a config struct, depth first
+func leaves(v reflect.Value) []field {
+	var fields []field
+	for i := 0; i < v.NumField(); i++ {
+		tag := v.Type().Field(i).Tag
+		if v.Field(i).Kind() == reflect.Struct {
+			fields = append(fields, leaves(v.Field(i))...)
+			continue
+		}
+		fields = append(fields, field{value: v.Field(i), env: tag.Get("env"), flag: tag.Get("flag"), usage: tag.Get("usage")})
+	}
+	return fields
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Set parses s into the field: durations as "90s" or "5m", lists comma separated
+func (f field) Set(s string) error {
+	switch {
+	case f.value.Type() == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		f.value.SetInt(int64(d))
+	case f.value.Kind() == reflect.String:
+		f.value.SetString(s)
+	case f.value.Kind() == reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		f.value.SetInt(n)
+	case f.value.Kind() == reflect.Float64:
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		f.value.SetFloat(n)
+	case f.value.Kind() == reflect.Slice:
+		var list []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		f.value.Set(reflect.ValueOf(list))
+	default:
+		return fmt.Errorf("unsupported setting type %s", f.value.Type())
+	}
+	return nil
+}
+
+// String formats the field the way Set parses it
+func (f field) String() string {
+	switch {
+	case f.value.Type() == durationType:
+		return time.Duration(f.value.Int()).String()
+	case f.value.Kind() == reflect.Slice:
+		return strings.Join(f.value.Interface().([]string), ",")
+	default:
+		return fmt.Sprint(f.value.Interface())
+	}
+}
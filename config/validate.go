@@ -0,0 +1,120 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"net/url"
+	"os"
+	"strconv"
+)
+
+// Validate reports every setting that would stop the server from working correctly
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Database.URL == "" {
+		fail("DB_URL is required")
+	}
+	if c.Database.MaxOpenConns <= 0 {
+		fail("DB_MAX_OPEN_CONNS must be positive")
+	}
+	if c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		fail("DB_MAX_IDLE_CONNS must be between 0 and DB_MAX_OPEN_CONNS")
+	}
+	if c.Database.ConnMaxLifetime < 0 {
+		fail("DB_CONN_MAX_LIFETIME must not be negative")
+	}
+	if len(c.Auth.JWTSecret) < MinJWTSecretLength {
+		fail("JWT_SECRET must be at least %d characters", MinJWTSecretLength)
+	}
+	if c.Auth.TokenTTL <= 0 {
+		fail("TOKEN_TTL must be positive")
+	}
+
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		fail("PORT %q is not a valid port", c.Server.Port)
+	}
+	for _, origin := range c.Server.CORSOrigins {
+		if !isOrigin(origin) {
+			fail("CORS_ORIGINS: %q is not an origin like https://app.example.com", origin)
+		}
+	}
+	for _, origin := range c.Server.WebSocketOrigins {
+		if !isOrigin(origin) {
+			fail("WEBSOCKET_ORIGINS: %q is not an origin like https://app.example.com", origin)
+		}
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		fail("SHUTDOWN_TIMEOUT must be positive")
+	}
+
+	if c.AppURL != "" && !isHTTPURL(c.AppURL) {
+		fail("APP_URL %q must be an absolute http or https URL", c.AppURL)
+	}
+	if c.Mail.SMTPHost != "" {
+		if port, err := strconv.Atoi(c.Mail.SMTPPort); err != nil || port < 1 || port > 65535 {
+			fail("SMTP_PORT %q is not a valid port", c.Mail.SMTPPort)
+		}
+		if _, err := mail.ParseAddress(c.Mail.From); err != nil {
+			fail("SMTP_FROM %q must be an email address when SMTP_HOST is set", c.Mail.From)
+		}
+		if c.AppURL == "" {
+			fail("APP_URL is required when SMTP_HOST is set, for the links in invite emails")
+		}
+	}
+
+	if c.Push.FCMCredentialsFile != "" {
+		if _, err := os.Stat(c.Push.FCMCredentialsFile); err != nil {
+			fail("FCM_CREDENTIALS_FILE cannot be read: %v", err)
+		}
+	}
+
+	if c.Uploads.MaxImportBytes <= 0 {
+		fail("MAX_IMPORT_BYTES must be positive")
+	}
+	if c.Scheduler.EscalationInterval <= 0 {
+		fail("ESCALATION_INTERVAL must be positive")
+	}
+
+	if (c.SuperAdmin.Email == "") != (c.SuperAdmin.Password == "") {
+		fail("SUPERADMIN_EMAIL and SUPERADMIN_PASSWORD must be set together")
+	}
+	if c.SuperAdmin.Email != "" {
+		if _, err := mail.ParseAddress(c.SuperAdmin.Email); err != nil {
+			fail("SUPERADMIN_EMAIL %q is not an email address", c.SuperAdmin.Email)
+		}
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		fail("LOG_LEVEL %q must be debug, info, warn or error", c.Log.Level)
+	}
+
+	if c.Tracing.Endpoint != "" && !isHTTPURL(c.Tracing.Endpoint) {
+		fail("OTEL_EXPORTER_OTLP_ENDPOINT %q must be an absolute http or https URL", c.Tracing.Endpoint)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		fail("TRACE_SAMPLE_RATIO must be between 0 and 1")
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// isOrigin reports whether s is a scheme and host with nothing after them
+func isOrigin(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && isHTTPURL(s) && (u.Path == "" || u.Path == "/") && u.RawQuery == ""
+}
+
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,153 @@
+// Package apierror defines the errors the API returns to clients and writes them as RFC 7807
+// problem details (application/problem+json).
+//
+// Every error carries a stable, machine-readable Code that clients can switch on, and the code
+// decides the HTTP status. The detail is a human-readable message safe to show to users;
+// whatever caused an internal error is logged with the request but never sent.
+package apierror
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Code identifies a kind of error. Codes are part of the API and never change meaning.
+type Code string
+
+const (
+	CodeInvalidRequest     Code = "invalid_request"
+	CodeValidationFailed   Code = "validation_failed"
+	CodeUnauthorized       Code = "unauthorized"
+	CodePlanLimitReached   Code = "plan_limit_reached"
+	CodeForbidden          Code = "forbidden"
+	CodePermissionDenied   Code = "permission_denied"
+	CodeAccountDeactivated Code = "account_deactivated"
+	CodeSocietySuspended   Code = "society_suspended"
+	CodeFeatureNotInPlan   Code = "feature_not_in_plan"
+	CodeNotFound           Code = "not_found"
+	CodeConflict           Code = "conflict"
+	CodePayloadTooLarge    Code = "payload_too_large"
+	CodeUnprocessable      Code = "unprocessable"
+	CodeInternal           Code = "internal_error"
+)
+
+// uniqueViolation is the Postgres SQLSTATE of a duplicate key
+const uniqueViolation = "23505"
+
+// statuses maps each code to the HTTP status it is returned with
+var statuses = map[Code]int{
+	CodeInvalidRequest:     http.StatusBadRequest,
+	CodeValidationFailed:   http.StatusBadRequest,
+	CodeUnauthorized:       http.StatusUnauthorized,
+	CodePlanLimitReached:   http.StatusPaymentRequired,
+	CodeForbidden:          http.StatusForbidden,
+	CodePermissionDenied:   http.StatusForbidden,
+	CodeAccountDeactivated: http.StatusForbidden,
+	CodeSocietySuspended:   http.StatusForbidden,
+	CodeFeatureNotInPlan:   http.StatusForbidden,
+	CodeNotFound:           http.StatusNotFound,
+	CodeConflict:           http.StatusConflict,
+	CodePayloadTooLarge:    http.StatusRequestEntityTooLarge,
+	CodeUnprocessable:      http.StatusUnprocessableEntity,
+	CodeInternal:           http.StatusInternalServerError,
+}
+
+// Status returns the HTTP status the code is returned with
+func (c Code) Status() int {
+	if status, ok := statuses[c]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// FieldError explains why one request field was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error is an error the API reports to the client
+type Error struct {
+	Code   Code
+	Detail string
+
+	// Fields lists the rejected request fields of a validation error
+	Fields []FieldError
+
+	// Extensions are extra members of the problem document, such as the plan that lacks a feature
+	Extensions map[string]any
+
+	// Err is the underlying cause. It is logged, never sent.
+	Err error
+}
+
+// New returns an error with the given code and client-facing detail
+func New(code Code, detail string) *Error {
+	return &Error{Code: code, Detail: detail}
+}
+
+// Internal returns a 500 with a generic detail; err is logged with the request but not sent
+func Internal(err error, detail string) *Error {
+	return &Error{Code: CodeInternal, Detail: detail, Err: err}
+}
+
+// Wrap reports err with the given code, using its message as the detail. An *Error is returned
+// unchanged. A database or network failure becomes an internal error instead, as its message
+// can quote SQL or connection details, except that a unique constraint violation is a conflict.
+func Wrap(code Code, err error) *Error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return &Error{Code: CodeConflict, Detail: "a record with these details already exists", Err: err}
+	}
+	if isInfrastructure(err) {
+		return Internal(err, "internal server error")
+	}
+	return &Error{Code: code, Detail: err.Error(), Err: err}
+}
+
+// With adds an extension member to the problem document and returns e
+func (e *Error) With(key string, value any) *Error {
+	if e.Extensions == nil {
+		e.Extensions = make(map[string]any)
+	}
+	e.Extensions[key] = value
+	return e
+}
+
+// Status returns the HTTP status of the error's code
+func (e *Error) Status() int {
+	return e.Code.Status()
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return string(e.Code) + ": " + e.Detail + ": " + e.Err.Error()
+	}
+	return string(e.Code) + ": " + e.Detail
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// isInfrastructure reports whether err comes from the database driver or the network
+func isInfrastructure(err error) bool {
+	var pgErr *pgconn.PgError
+	var connectErr *pgconn.ConnectError
+	var netErr *net.OpError
+	return errors.As(err, &pgErr) || errors.As(err, &connectErr) || errors.As(err, &netErr) ||
+		errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, sql.ErrTxDone) ||
+		errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,188 @@
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestWrap(t *testing.T) {
+	syntaxErr := &pgconn.PgError{Code: "42601", Message: `syntax error at or near "FROM users"`}
+	duplicate := &pgconn.PgError{Code: uniqueViolation, Message: `duplicate key value violates unique constraint "idx_users_email"`}
+
+	tests := []struct {
+		name   string
+		err    error
+		code   Code
+		detail string
+	}{
+		{"plain error", errors.New("name is required"), CodeInvalidRequest, "name is required"},
+		{"api error", New(CodeConflict, "taken"), CodeConflict, "taken"},
+		{"database error", fmt.Errorf("failed to save: %w", syntaxErr), CodeInternal, "internal server error"},
+		{"duplicate key", duplicate, CodeConflict, "a record with these details already exists"},
+		{"connection error", &pgconn.ConnectError{}, CodeInternal, "internal server error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Wrap(CodeInvalidRequest, tt.err)
+			if got.Code != tt.code || got.Detail != tt.detail {
+				t.Errorf("Wrap() = %s %q, want %s %q", got.Code, got.Detail, tt.code, tt.detail)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("expected Wrap() to keep the cause")
+			}
+		})
+	}
+}
+
+type address struct {
+	PostCode string `json:"post_code" binding:"required,len=6"`
+}
+
+type signup struct {
+	Email   string    `json:"email" binding:"required,email"`
+	Age     int       `json:"age" binding:"gte=18"`
+	Plan    string    `json:"plan" binding:"oneof=free standard"`
+	Address address   `json:"address"`
+	Tags    []address `json:"tags" binding:"dive"`
+}
+
+func bindSignup(body string) error {
+	var s signup
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	return binding.JSON.Bind(req, &s)
+}
+
+func TestValidationFields(t *testing.T) {
+	err := Validation(bindSignup(`{"email":"nope","age":12,"plan":"gold","address":{"post_code":"123"},"tags":[{}]}`))
+	if err.Code != CodeValidationFailed || err.Status() != http.StatusBadRequest {
+		t.Fatalf("expected a 400 validation_failed, got %s", err.Code)
+	}
+
+	want := []FieldError{
+		{Field: "email", Rule: "email", Message: "must be a valid email address"},
+		{Field: "age", Rule: "gte", Param: "18", Message: "must be at least 18"},
+		{Field: "plan", Rule: "oneof", Param: "free standard", Message: "must be one of: free, standard"},
+		{Field: "address.post_code", Rule: "len", Param: "6", Message: "must be exactly 6 characters"},
+		{Field: "tags[0].post_code", Rule: "required", Message: "is required"},
+	}
+	if len(err.Fields) != len(want) {
+		t.Fatalf("expected %d field errors, got %+v", len(want), err.Fields)
+	}
+	for i := range want {
+		if err.Fields[i] != want[i] {
+			t.Errorf("field %d = %+v, want %+v", i, err.Fields[i], want[i])
+		}
+	}
+}
+
+func TestValidationBodyErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		code   Code
+		detail string
+	}{
+		{"empty body", bindSignup(""), CodeInvalidRequest, "request body is required"},
+		{"truncated body", bindSignup(`{"email":`), CodeInvalidRequest, "request body is not valid JSON"},
+		{"not json", bindSignup(`email=a`), CodeInvalidRequest, "request body is not valid JSON"},
+		{"wrong type", bindSignup(`{"age":"old"}`), CodeValidationFailed, "request validation failed"},
+		{"too large", &http.MaxBytesError{Limit: 10}, CodePayloadTooLarge, "request body is too large"},
+		{"other", io.ErrClosedPipe, CodeInvalidRequest, "invalid request"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Validation(tt.err)
+			if got.Code != tt.code || got.Detail != tt.detail {
+				t.Errorf("Validation() = %s %q, want %s %q", got.Code, got.Detail, tt.code, tt.detail)
+			}
+		})
+	}
+
+	if got := Validation(bindSignup(`{"age":"old"}`)); len(got.Fields) != 1 || got.Fields[0].Field != "age" || got.Fields[0].Message != "must be a whole number" {
+		t.Errorf("unexpected type error fields %+v", got.Fields)
+	}
+}
+
+// serve runs handler for one request and returns the recorded response and gin's errors
+func serve(handler gin.HandlerFunc) (*httptest.ResponseRecorder, []*gin.Error) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/things/7", nil)
+	c.Set("request_id", "req-1")
+	handler(c)
+	return w, c.Errors
+}
+
+func TestWriteProblem(t *testing.T) {
+	w, errs := serve(func(c *gin.Context) {
+		Write(c, New(CodeFeatureNotInPlan, "analytics is not in the free plan").With("plan", "free").With("status", 200))
+	})
+
+	if w.Code != http.StatusForbidden || w.Header().Get("Content-Type") != ContentType {
+		t.Fatalf("expected a 403 %s, got %d %s", ContentType, w.Code, w.Header().Get("Content-Type"))
+	}
+	var problem map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"type":       "urn:flashtrack:problem:feature_not_in_plan",
+		"title":      "Forbidden",
+		"status":     float64(403),
+		"detail":     "analytics is not in the free plan",
+		"error":      "analytics is not in the free plan",
+		"instance":   "/api/things/7",
+		"code":       "feature_not_in_plan",
+		"request_id": "req-1",
+		"plan":       "free",
+	}
+	for key, value := range want {
+		if problem[key] != value {
+			t.Errorf("%s = %v, want %v", key, problem[key], value)
+		}
+	}
+	if len(errs) != 0 {
+		t.Errorf("expected client errors to stay off the context, got %v", errs)
+	}
+}
+
+func TestWriteHidesInternalErrors(t *testing.T) {
+	cause := &pgconn.PgError{Code: "42P01", Message: `relation "secret_table" does not exist`}
+	for name, err := range map[string]error{
+		"internal":  Internal(cause, "failed to fetch things"),
+		"unwrapped": fmt.Errorf("query: %w", cause),
+	} {
+		t.Run(name, func(t *testing.T) {
+			w, errs := serve(func(c *gin.Context) { Write(c, err) })
+			if w.Code != http.StatusInternalServerError {
+				t.Fatalf("expected 500, got %d", w.Code)
+			}
+			if strings.Contains(w.Body.String(), "secret_table") {
+				t.Errorf("expected the cause to stay out of the response: %s", w.Body.String())
+			}
+			if len(errs) != 1 || !errors.Is(errs[0], cause) {
+				t.Errorf("expected the cause recorded on the context, got %v", errs)
+			}
+		})
+	}
+}
+
+func TestWriteAfterResponseStarted(t *testing.T) {
+	w, _ := serve(func(c *gin.Context) {
+		c.String(http.StatusOK, "partial")
+		Write(c, Internal(errors.New("stream failed"), "failed to export"))
+	})
+	if w.Code != http.StatusOK || w.Body.String() != "partial" {
+		t.Errorf("expected the started response to be left alone, got %d %q", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,63 @@
+package apierror
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentType is the media type of problem documents
+const ContentType = "application/problem+json"
+
+// TypePrefix starts the type URI of every problem; the code follows it
+const TypePrefix = "urn:flashtrack:problem:"
+
+// Write responds with err as a problem document and aborts the handler chain. Errors that are
+// not an *Error are reported as a generic internal error. The cause of a server error is
+// attached to the gin context, so the access log and the trace record it, and never written
+// to the response.
+//
+// The document has the RFC 7807 members type, title, status, detail and instance, plus code,
+// request_id, errors (for validation failures) and the error's extensions. It also repeats the
+// detail as error, for clients written against the earlier {"error": "..."} responses.
+func Write(c *gin.Context, err error) {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		apiErr = Internal(err, "internal server error")
+	}
+
+	status := apiErr.Status()
+	if status >= http.StatusInternalServerError {
+		c.Error(apiErr)
+	}
+	// A handler that already started streaming cannot change its response any more
+	if c.Writer.Written() {
+		c.Abort()
+		return
+	}
+
+	problem := gin.H{
+		"type":     TypePrefix + string(apiErr.Code),
+		"title":    http.StatusText(status),
+		"status":   status,
+		"detail":   apiErr.Detail,
+		"instance": c.Request.URL.Path,
+		"code":     apiErr.Code,
+		"error":    apiErr.Detail,
+	}
+	if id := c.GetString("request_id"); id != "" {
+		problem["request_id"] = id
+	}
+	if len(apiErr.Fields) > 0 {
+		problem["errors"] = apiErr.Fields
+	}
+	for key, value := range apiErr.Extensions {
+		if _, taken := problem[key]; !taken {
+			problem[key] = value
+		}
+	}
+
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(status, problem)
+}
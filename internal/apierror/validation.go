@@ -0,0 +1,124 @@
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Report fields by the names clients send, not the Go struct field names
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(fieldName)
+	}
+}
+
+// fieldName is the json, or else form, name of a struct field
+func fieldName(f reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// Validation reports why binding a request failed: which fields broke which rules, or that the
+// body is missing, is not JSON or is too large
+func Validation(err error) *Error {
+	var invalid validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	var tooLarge *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &invalid):
+		fields := make([]FieldError, len(invalid))
+		for i, fe := range invalid {
+			fields[i] = FieldError{Field: fieldPath(fe), Rule: fe.Tag(), Param: fe.Param(), Message: ruleMessage(fe)}
+		}
+		return &Error{Code: CodeValidationFailed, Detail: "request validation failed", Fields: fields, Err: err}
+	case errors.As(err, &typeErr):
+		field := FieldError{Field: typeErr.Field, Rule: "type", Message: "must be a " + jsonType(typeErr.Type)}
+		return &Error{Code: CodeValidationFailed, Detail: "request validation failed", Fields: []FieldError{field}, Err: err}
+	case errors.As(err, &tooLarge):
+		return &Error{Code: CodePayloadTooLarge, Detail: "request body is too large", Err: err}
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return &Error{Code: CodeInvalidRequest, Detail: "request body is not valid JSON", Err: err}
+	case errors.Is(err, io.EOF):
+		return &Error{Code: CodeInvalidRequest, Detail: "request body is required", Err: err}
+	default:
+		return &Error{Code: CodeInvalidRequest, Detail: "invalid request", Err: err}
+	}
+}
+
+// fieldPath is the field's path below the request struct, such as "items[0].name"
+func fieldPath(fe validator.FieldError) string {
+	if _, path, ok := strings.Cut(fe.Namespace(), "."); ok {
+		return path
+	}
+	return fe.Field()
+}
+
+// ruleMessage explains a failed validation rule in words
+func ruleMessage(fe validator.FieldError) string {
+	unit := ""
+	switch fe.Kind() {
+	case reflect.String:
+		unit = " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = " items"
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "min":
+		return fmt.Sprintf("must be at least %s%s", fe.Param(), unit)
+	case "max":
+		return fmt.Sprintf("must be at most %s%s", fe.Param(), unit)
+	case "len":
+		return fmt.Sprintf("must be exactly %s%s", fe.Param(), unit)
+	case "gte":
+		return "must be at least " + fe.Param()
+	case "lte":
+		return "must be at most " + fe.Param()
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "lt":
+		return "must be less than " + fe.Param()
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(fe.Param()), ", ")
+	default:
+		return "failed the " + fe.Tag() + " rule"
+	}
+}
+
+// jsonType names a Go type the way a JSON client thinks of it
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "whole number"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+)
+
+// TestAnalyticsCountsEachComplaintOnce reports on a past week of hand-dated complaints and checks
+// reassignment does not move time to assign and resolutions count when they happened
+func TestAnalyticsCountsEachComplaintOnce(t *testing.T) {
+	useTestDatabase(t)
+
+	f := seedSociety(t, fmt.Sprintf("analytics%d", time.Now().UnixNano()))
+	db := database.ForSociety(f.society.ID)
+	at := func(day, hour int) *time.Time {
+		t := time.Date(2024, time.March, day, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	// Assigned two hours after it was raised, reassigned two days later, resolved within its SLA
+	reassigned := models.Complaint{Title: "reassigned", Description: "x", Status: "resolved", ResidentID: f.resident.ID, CategoryID: f.category.ID,
+		StaffID: &f.staff.ID, CreatedAt: *at(1, 9), FirstAssignedAt: at(1, 11), AssignedAt: at(3, 9), ResolvedAt: at(4, 9), DueAt: at(5, 9)}
+	// Raised the week before and resolved in it
+	older := models.Complaint{Title: "older", Description: "x", Status: "resolved", ResidentID: f.resident.ID, CategoryID: f.category.ID,
+		StaffID: &f.staff.ID, CreatedAt: at(1, 9).AddDate(0, 0, -7), FirstAssignedAt: at(1, 9), AssignedAt: at(1, 9), ResolvedAt: at(2, 9)}
+	waiting := models.Complaint{Title: "waiting", Description: "x", Status: "pending", ResidentID: f.resident.ID, CategoryID: f.category.ID, CreatedAt: *at(2, 9)}
+	for _, c := range []*models.Complaint{&reassigned, &older, &waiting} {
+		if err := db.Create(c).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	duplicate := models.Complaint{Title: "duplicate", Description: "x", Status: "resolved", ResidentID: f.resident.ID, CategoryID: f.category.ID,
+		DuplicateOfID: &reassigned.ID, CreatedAt: *at(1, 10), ResolvedAt: at(4, 9)}
+	if err := db.Create(&duplicate).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	s := newAPIServer(t)
+	var report services.AnalyticsReport
+	s.expectJSON(200, "GET", "/api/admin/analytics?from=2024-03-01T00:00:00Z&to=2024-03-08T00:00:00Z&granularity=day",
+		tokenFor(t, f.admin), nil, &report)
+
+	if report.Total != 2 {
+		t.Errorf("total = %d, want the two complaints raised in the week", report.Total)
+	}
+	var created, resolved int64
+	for _, p := range report.ByPeriod {
+		created += p.Created
+		resolved += p.Resolved
+	}
+	if created != 2 || resolved != 2 {
+		t.Errorf("by period created %d resolved %d, want 2 each: %+v", created, resolved, report.ByPeriod)
+	}
+	if tta := report.TimeToAssign; tta.Count != 1 || tta.MeanHours == nil || *tta.MeanHours != 2 {
+		t.Errorf("time to assign %+v, want 2 hours to the first assignment", tta)
+	}
+	if ttr := report.TimeToResolve; ttr.Count != 1 || ttr.MeanHours == nil || *ttr.MeanHours != 72 {
+		t.Errorf("time to resolve %+v, want 72 hours", ttr)
+	}
+	if sla := report.SLA; sla.Resolved != 1 || sla.WithinSLA != 1 {
+		t.Errorf("sla %+v, want the one resolution within its deadline", sla)
+	}
+}
@@ -0,0 +1,98 @@
+// Package app assembles FlashTrack: it connects the database, wires the repositories, services
+// and controllers behind the HTTP router, and serves the API until it is told to stop.
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/config"
+	"github.com/VinVorteX/flashtrack/internal/controllers"
+	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/VinVorteX/flashtrack/internal/telemetry"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+	"github.com/gin-gonic/gin"
+)
+
+// App is the assembled application: the HTTP handler and the long-lived services behind it
+type App struct {
+	Config config.Config
+
+	// Handler serves the whole API, including the health probes
+	Handler http.Handler
+
+	// Notifications holds the open WebSocket connections, closed on shutdown
+	Notifications *services.NotificationService
+
+	// Escalations evaluates escalation policies in the background while the server runs
+	Escalations *services.EscalationService
+
+	router        *gin.Engine
+	complaints    *controllers.ComplaintController
+	feedback      *controllers.FeedbackController
+	categories    *controllers.CategoryController
+	notifications *controllers.NotificationController
+	profiles      *controllers.ProfileController
+	users         *controllers.UserController
+	auth          *controllers.AuthController
+	imports       *controllers.ImportController
+	platform      *controllers.PlatformController
+	admin         *controllers.AdminController
+	staffProfiles *controllers.StaffProfileController
+	locations     *controllers.LocationController
+	analytics     *controllers.AnalyticsController
+	exports       *controllers.ExportController
+	plans         *controllers.PlanController
+	roles         *controllers.RoleController
+	escalations   *controllers.EscalationController
+
+	// draining is set once shutdown starts, so readiness fails while requests drain
+	draining atomic.Bool
+
+	// stopTracing flushes and stops the trace exporter
+	stopTracing func(context.Context) error
+}
+
+// New starts tracing, connects the database, makes sure the platform super-admin exists and
+// builds the application
+func New(cfg config.Config) (*App, error) {
+	stopTracing, err := telemetry.StartTracing(context.Background(), cfg.Tracing)
+	if err != nil {
+		return nil, err
+	}
+	if err := database.Connect(cfg.Database); err != nil {
+		stopTracing(context.Background())
+		return nil, err
+	}
+
+	// Create the platform super-admin on first start
+	if err := services.EnsureSuperAdmin(cfg.SuperAdmin.Email, cfg.SuperAdmin.Password); err != nil {
+		slog.Error("failed to create super-admin", "error", err)
+	}
+
+	a := build(cfg)
+	a.stopTracing = stopTracing
+	return a, nil
+}
+
+// Close releases the database connections and flushes the remaining spans. Call it after Run
+// returns.
+func (a *App) Close() error {
+	var errs []error
+	if err := database.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close database: %w", err))
+	}
+	if a.stopTracing != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := a.stopTracing(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to flush traces: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/VinVorteX/flashtrack/internal/apierror"
+	"github.com/VinVorteX/flashtrack/internal/logging"
+)
+
+// decodeProblem checks that the response is a problem document and returns its members
+func decodeProblem(t *testing.T, w *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, apierror.ContentType) {
+		t.Fatalf("expected %s, got %q", apierror.ContentType, ct)
+	}
+	var problem map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if problem["status"] != float64(w.Code) || problem["request_id"] != w.Header().Get(logging.RequestIDHeader) {
+		t.Errorf("expected status %d and the request ID in %v", w.Code, problem)
+	}
+	return problem
+}
+
+// TestErrorsAreProblems checks the errors answered before any handler touches the database
+func TestErrorsAreProblems(t *testing.T) {
+	router := build(testConfig()).router
+
+	tests := []struct {
+		name, method, path, body string
+		status                   int
+		code                     apierror.Code
+	}{
+		{"missing token", "GET", "/api/me", "", 401, apierror.CodeUnauthorized},
+		{"unknown route", "GET", "/no-such-route", "", 404, apierror.CodeNotFound},
+		{"empty login", "POST", "/auth/login", "", 400, apierror.CodeInvalidRequest},
+		{"malformed login", "POST", "/auth/login", `{"email":`, 400, apierror.CodeInvalidRequest},
+		{"incomplete login", "POST", "/auth/login", `{"email":"a@example.com"}`, 400, apierror.CodeValidationFailed},
+		{"mistyped register", "POST", "/auth/register", `{"name":1}`, 400, apierror.CodeValidationFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			problem := decodeProblem(t, w)
+			if problem["code"] != string(tt.code) || problem["type"] != apierror.TypePrefix+string(tt.code) {
+				t.Errorf("expected code %s, got %v", tt.code, problem)
+			}
+			if problem["error"] != problem["detail"] || problem["instance"] != tt.path {
+				t.Errorf("expected error to repeat the detail and instance to be %s, got %v", tt.path, problem)
+			}
+		})
+	}
+}
+
+func TestValidationNamesJSONFields(t *testing.T) {
+	router := build(testConfig()).router
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/auth/login", strings.NewReader(`{"email":"a@example.com"}`)))
+	problem := decodeProblem(t, w)
+
+	fields, _ := problem["errors"].([]any)
+	if len(fields) != 1 {
+		t.Fatalf("expected one field error, got %v", problem)
+	}
+	field := fields[0].(map[string]any)
+	if field["field"] != "password" || field["rule"] != "required" || field["message"] != "is required" {
+		t.Errorf("unexpected field error %v", field)
+	}
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
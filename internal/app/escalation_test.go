@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+)
+
+// TestEvaluateRaisesEachLevelOnce runs the escalation worker twice over a stale complaint and
+// checks every policy it is due for acts exactly once
+func TestEvaluateRaisesEachLevelOnce(t *testing.T) {
+	useTestDatabase(t)
+
+	f := seedSociety(t, fmt.Sprintf("escalate%d", time.Now().UnixNano()))
+	db := database.ForSociety(f.society.ID)
+	stale := models.Complaint{Title: "stale", Description: "stale", Status: "pending", ResidentID: f.resident.ID, CategoryID: f.category.ID, Priority: "low"}
+	if err := db.Create(&stale).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&stale).Update("created_at", time.Now().Add(-72*time.Hour)).Error; err != nil {
+		t.Fatal(err)
+	}
+	unassigned := models.EscalationPolicy{Trigger: "unassigned", AfterHours: 24, Level: 2, Action: "notify_role", TargetRole: "staff"}
+	if err := db.Create(&unassigned).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	escalations := build(testConfig()).Escalations
+	for i := 0; i < 2; i++ {
+		if err := escalations.Evaluate(context.Background(), time.Now()); err != nil {
+			t.Fatalf("evaluate %d: %v", i+1, err)
+		}
+	}
+
+	var stored models.Complaint
+	if err := db.First(&stored, stale.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.EscalationLevel != 2 || stored.EscalatedAt == nil {
+		t.Errorf("escalation level %d escalated %v, want level 2", stored.EscalationLevel, stored.EscalatedAt)
+	}
+
+	var history []models.ComplaintEscalation
+	if err := db.Where("complaint_id = ?", stale.ID).Order("level").Find(&history).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].PolicyID != f.policy.ID || history[1].PolicyID != unassigned.ID {
+		t.Errorf("escalation history %+v, want one entry for each policy", history)
+	}
+
+	for _, u := range []models.User{f.admin, f.staff} {
+		var count int64
+		if err := db.Model(&models.Notification{}).Where("user_id = ? AND complaint_id = ? AND type = ?", u.ID, stale.ID, "escalation").
+			Count(&count).Error; err != nil {
+			t.Fatal(err)
+		}
+		if count != 1 {
+			t.Errorf("%s got %d escalation notifications, want 1", u.Role, count)
+		}
+	}
+
+	// The fresh pending complaint is not old enough for either policy
+	var fresh int64
+	db.Model(&models.Complaint{}).Where("id <> ? AND escalation_level > 0", stale.ID).Count(&fresh)
+	if fresh != 0 {
+		t.Errorf("%d other complaints escalated, want none", fresh)
+	}
+}
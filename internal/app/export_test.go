@@ -0,0 +1,135 @@
+package app
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+)
+
+// TestExportsApplyListScoping downloads the complaint and feedback exports as each member of a
+// society and checks they hold exactly the rows the listing endpoints would show them
+func TestExportsApplyListScoping(t *testing.T) {
+	useTestDatabase(t)
+
+	nonce := fmt.Sprintf("export%d", time.Now().UnixNano())
+	a := seedSociety(t, "a"+nonce)
+	b := seedSociety(t, nonce)
+	s := newAPIServer(t)
+
+	db := database.ForSociety(a.society.ID)
+	neighbor := models.User{Name: "neighbor", Email: "neighbor-" + nonce + "@example.com", Password: a.resident.Password, Role: "user"}
+	if err := db.Create(&neighbor).Error; err != nil {
+		t.Fatal(err)
+	}
+	other := models.Complaint{Title: "=HYPERLINK(\"x\")", Description: "neighbor", Status: "pending", ResidentID: neighbor.ID, CategoryID: a.category.ID, Priority: "low"}
+	if err := db.Create(&other).Error; err != nil {
+		t.Fatal(err)
+	}
+	var residentIDs []uint
+	if err := db.Model(&models.Complaint{}).Where("resident_id = ?", a.resident.ID).Order("id").Pluck("id", &residentIDs).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		path  string
+		token string
+		want  []uint
+	}{
+		{"resident sees own complaints", "/api/complaints/export", tokenFor(t, a.resident), residentIDs},
+		{"staff sees assigned complaints", "/api/complaints/export", tokenFor(t, a.staff), []uint{a.complaint.ID}},
+		{"admin sees the society", "/api/complaints/export", tokenFor(t, a.admin), append(slices.Clone(residentIDs), other.ID)},
+		{"admin sees the society's feedback", "/api/admin/export/feedback", tokenFor(t, a.admin), []uint{a.feedback.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := s.expect(200, "GET", tt.path+"?format=csv", tt.token, nil)
+			if bytes.Contains(body, []byte(b.society.Name)) {
+				t.Errorf("export contains society B's data: %s", truncate(string(body)))
+			}
+
+			records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []uint
+			for _, record := range records[1:] {
+				id, err := strconv.ParseUint(record[0], 10, 32)
+				if err != nil {
+					t.Fatalf("unexpected row %q", record)
+				}
+				got = append(got, uint(id))
+				for _, cell := range record {
+					if strings.HasPrefix(cell, "=") {
+						t.Errorf("formula exported unescaped: %q", cell)
+					}
+				}
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("exported IDs %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestExportFormats downloads every export in every format and checks the staff performance
+// figures and the listing filters carry over
+func TestExportFormats(t *testing.T) {
+	useTestDatabase(t)
+
+	f := seedSociety(t, fmt.Sprintf("formats%d", time.Now().UnixNano()))
+	s := newAPIServer(t)
+	admin := tokenFor(t, f.admin)
+
+	magic := map[string]string{"csv": "", "xlsx": "PK", "pdf": "%PDF"}
+	for _, path := range []string{"/api/complaints/export", "/api/admin/export/feedback", "/api/admin/export/staff-performance"} {
+		for format, prefix := range magic {
+			body := s.expect(200, "GET", path+"?format="+format, admin, nil)
+			if !bytes.HasPrefix(body, []byte(prefix)) || len(body) == 0 {
+				t.Errorf("%s as %s starts with %q, want %q", path, format, truncate(string(body)), prefix)
+			}
+		}
+		s.expect(400, "GET", path+"?format=docx", admin, nil)
+	}
+	s.expect(403, "GET", "/api/admin/export/staff-performance", tokenFor(t, f.resident), nil)
+
+	body := s.expect(200, "GET", "/api/admin/export/staff-performance?format=csv", admin, nil)
+	records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	staffID := strconv.Itoa(int(f.staff.ID))
+	found := false
+	for _, record := range records[1:] {
+		if record[0] != staffID {
+			continue
+		}
+		found = true
+		// Assigned, open, resolved, feedback, average rating and points of the seeded resolution
+		if record[3] != "1" || record[4] != "0" || record[5] != "1" || record[8] != "1" || record[9] != "4.0" || record[10] != "8" {
+			t.Errorf("staff performance row %q", record)
+		}
+	}
+	if !found {
+		t.Errorf("staff member %s missing from %q", staffID, records)
+	}
+
+	// The location filter of the listing applies to the export
+	body = s.expect(200, "GET", "/api/complaints/export?format=csv&location_id="+strconv.Itoa(int(f.unit.ID)), admin, nil)
+	records, err = csv.NewReader(bytes.NewReader(body)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[1][0] != strconv.Itoa(int(f.complaint.ID)) {
+		t.Errorf("export of the unit has %q, want only the complaint filed there", records)
+	}
+}
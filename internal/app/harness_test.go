@@ -0,0 +1,298 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/config"
+	"github.com/VinVorteX/flashtrack/internal/logging"
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/VinVorteX/flashtrack/internal/utils"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+	"github.com/VinVorteX/flashtrack/pkg/database/dbtest"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const testSecret = "integration-test-secret"
+
+var (
+	connectOnce sync.Once
+	connectErr  error
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	code := m.Run()
+	dbtest.Stop()
+	os.Exit(code)
+}
+
+// useTestDatabase connects database.DB to the disposable test database, migrating it on first
+// use, or skips the test when there is none
+func useTestDatabase(t *testing.T) {
+	t.Helper()
+	url, err := dbtest.URL()
+	if errors.Is(err, dbtest.ErrUnavailable) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	connectOnce.Do(func() {
+		cfg := config.Default().Database
+		cfg.URL = url
+		connectErr = database.Connect(cfg)
+	})
+	if connectErr != nil {
+		t.Fatal(connectErr)
+	}
+}
+
+// testConfig is the default configuration signing tokens with testSecret
+func testConfig() config.Config {
+	cfg := config.Default()
+	cfg.Auth.JWTSecret = testSecret
+	return cfg
+}
+
+// societyFixture is a society seeded with one row of each kind a handler could reach
+type societyFixture struct {
+	society   models.Society
+	admin     models.User
+	staff     models.User
+	resident  models.User
+	category  models.Category
+	unit      models.Location
+	complaint models.Complaint
+	feedback  models.Feedback
+	notice    models.Notification
+	policy    models.EscalationPolicy
+	leave     models.StaffLeave
+	role      models.Role
+}
+
+// seedSociety creates a society whose text columns all carry marker and removes it after the test.
+// Every member's password is marker.
+func seedSociety(t *testing.T, marker string) *societyFixture {
+	t.Helper()
+	f := &societyFixture{society: models.Society{Name: marker, Address: marker, Plan: "premium"}}
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	must(database.DB.Create(&f.society).Error)
+	t.Cleanup(func() {
+		now := time.Now()
+		database.DB.Model(&f.society).Update("suspended_at", &now)
+		if err := (&services.PlatformService{}).Delete(context.Background(), &models.User{}, f.society.ID); err != nil {
+			t.Errorf("cleanup society %d: %v", f.society.ID, err)
+		}
+	})
+
+	db := database.ForSociety(f.society.ID)
+	must(db.Create(&models.Location{Type: "building", Name: marker}).Error)
+	f.unit = models.Location{Type: "unit", Name: marker}
+	must(db.Create(&f.unit).Error)
+	f.category = models.Category{Name: marker, SLAHours: 24, DefaultPriority: "medium"}
+	must(db.Create(&f.category).Error)
+
+	password := utils.HashPassword(marker)
+	f.admin = models.User{Name: marker, Email: "admin-" + marker + "@example.com", Password: password, Role: "admin"}
+	f.staff = models.User{Name: marker, Email: "staff-" + marker + "@example.com", Password: password, Role: "staff"}
+	f.resident = models.User{Name: marker, Email: "resident-" + marker + "@example.com", Password: password, Role: "user", UnitID: &f.unit.ID}
+	for _, u := range []*models.User{&f.admin, &f.staff, &f.resident} {
+		must(db.Create(u).Error)
+	}
+
+	resolved := time.Now()
+	f.complaint = models.Complaint{
+		Title: marker, Description: marker, Status: "resolved", ResidentID: f.resident.ID, StaffID: &f.staff.ID,
+		CategoryID: f.category.ID, LocationID: &f.unit.ID, Priority: "medium", ResolvedAt: &resolved,
+	}
+	must(db.Create(&f.complaint).Error)
+	must(db.Create(&models.Complaint{
+		Title: marker, Description: marker, Status: "pending", ResidentID: f.resident.ID, CategoryID: f.category.ID, Priority: "low",
+	}).Error)
+
+	f.feedback = models.Feedback{ComplaintID: f.complaint.ID, UserID: f.resident.ID, StaffID: f.staff.ID, Rating: 4, Comment: marker, Points: 8}
+	must(db.Create(&f.feedback).Error)
+	must(db.Create(&models.StaffPoints{StaffID: f.staff.ID, TotalPoints: 8, TasksCompleted: 1}).Error)
+	f.notice = models.Notification{UserID: f.resident.ID, Title: marker, Message: marker, Type: "status_update", ComplaintID: &f.complaint.ID}
+	must(db.Create(&f.notice).Error)
+	f.policy = models.EscalationPolicy{Trigger: "unresolved", AfterHours: 48, Level: 1, Action: "notify_admin"}
+	must(db.Create(&f.policy).Error)
+	must(db.Create(&models.StaffProfile{StaffID: f.staff.ID, ShiftStart: "09:00", ShiftEnd: "17:00"}).Error)
+	must(db.Create(&models.StaffCategory{StaffID: f.staff.ID, CategoryID: f.category.ID}).Error)
+	f.leave = models.StaffLeave{StaffID: &f.staff.ID, StartDate: resolved, EndDate: resolved, Reason: marker}
+	must(db.Create(&f.leave).Error)
+	f.role = models.Role{Name: "role-" + marker, Description: marker, Permissions: services.PermComplaintViewAll}
+	must(db.Create(&f.role).Error)
+	return f
+}
+
+// seedSuperAdmin creates a platform super-admin and removes it after the test
+func seedSuperAdmin(t *testing.T, marker string) models.User {
+	t.Helper()
+	admin := models.User{Name: marker, Email: "platform-" + marker + "@example.com", Password: utils.HashPassword(marker), Role: services.RoleSuperAdmin}
+	if err := database.DB.Create(&admin).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.DB.Delete(&admin) })
+	return admin
+}
+
+// tokenFor signs a session token for the user
+func tokenFor(t *testing.T, user models.User) string {
+	t.Helper()
+	token, err := utils.GenerateJWT(&user, testSecret, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// apiServer serves the full router over HTTP and records which routes the tests reached
+type apiServer struct {
+	t      *testing.T
+	server *httptest.Server
+	routes []gin.RouteInfo
+	called map[string]bool
+}
+
+func newAPIServer(t *testing.T) *apiServer {
+	t.Helper()
+	router := build(testConfig()).router
+	s := &apiServer{t: t, server: httptest.NewServer(router), routes: router.Routes(), called: map[string]bool{}}
+	t.Cleanup(s.server.Close)
+
+	// Match static segments before parameters, as the router does
+	sort.SliceStable(s.routes, func(i, j int) bool {
+		return strings.Count(s.routes[i].Path, ":") < strings.Count(s.routes[j].Path, ":")
+	})
+	return s
+}
+
+var routeParam = regexp.MustCompile(`:[a-z_]+`)
+
+// route returns the registered route a request path is served by
+func (s *apiServer) route(method, path string) string {
+	path = strings.SplitN(path, "?", 2)[0]
+	for _, r := range s.routes {
+		pattern := "^" + routeParam.ReplaceAllString(regexp.QuoteMeta(r.Path), "[^/]+") + "$"
+		if r.Method == method && regexp.MustCompile(pattern).MatchString(path) {
+			return r.Method + " " + r.Path
+		}
+	}
+	return ""
+}
+
+// do sends a request with an optional bearer token. A string body is sent as is, anything
+// else as JSON.
+func (s *apiServer) do(method, path, token string, body interface{}) (int, []byte) {
+	s.t.Helper()
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		reader = strings.NewReader(b)
+	default:
+		encoded, err := json.Marshal(b)
+		if err != nil {
+			s.t.Fatal(err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequest(method, s.server.URL+path, reader)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	if _, isString := body.(string); body != nil && !isString {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	res, err := s.server.Client().Do(req)
+	if err != nil {
+		s.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer res.Body.Close()
+	out, _ := io.ReadAll(res.Body)
+
+	if r := s.route(method, path); r != "" {
+		s.called[r] = true
+	}
+	return res.StatusCode, out
+}
+
+// expect sends a request and fails the test unless it is answered with status
+func (s *apiServer) expect(status int, method, path, token string, body interface{}) []byte {
+	s.t.Helper()
+	code, out := s.do(method, path, token, body)
+	if code != status {
+		s.t.Errorf("%s %s returned %d, want %d: %s", method, path, code, status, truncate(string(out)))
+	}
+	return out
+}
+
+// expectJSON is expect for responses whose body is decoded into v
+func (s *apiServer) expectJSON(status int, method, path, token string, body, v interface{}) {
+	s.t.Helper()
+	out := s.expect(status, method, path, token, body)
+	if v != nil && status < 300 {
+		if err := json.Unmarshal(out, v); err != nil {
+			s.t.Fatalf("%s %s: decode %s: %v", method, path, truncate(string(out)), err)
+		}
+	}
+}
+
+// dial opens a WebSocket as the token's user from an allowed frontend origin
+func (s *apiServer) dial(path, token string) *websocket.Conn {
+	s.t.Helper()
+	header := http.Header{"Authorization": {"Bearer " + token}, "Origin": {"http://localhost:3000"}}
+	conn, res, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(s.server.URL, "http")+path, header)
+	if err != nil {
+		status := 0
+		if res != nil {
+			status = res.StatusCode
+		}
+		s.t.Fatalf("dial %s: %v (status %d)", path, err, status)
+	}
+	if res.Header.Get(logging.RequestIDHeader) == "" {
+		s.t.Errorf("dial %s: handshake response has no %s", path, logging.RequestIDHeader)
+	}
+	s.called[s.route("GET", path)] = true
+	return conn
+}
+
+// uncalled lists the registered routes no request has reached
+func (s *apiServer) uncalled() []string {
+	var missing []string
+	for _, r := range s.routes {
+		if !s.called[r.Method+" "+r.Path] {
+			missing = append(missing, r.Method+" "+r.Path)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/pkg/database"
+	"github.com/gin-gonic/gin"
+)
+
+// readyTimeout bounds the database ping of a readiness probe
+const readyTimeout = 2 * time.Second
+
+// healthz is the liveness probe. It only shows the process is serving requests and does not
+// touch the database, so an outage there does not get every instance restarted.
+func healthz(c *gin.Context) {
+	c.JSON(200, gin.H{"status": "ok"})
+}
+
+// readyz is the readiness probe: it fails while the server shuts down or when the database does
+// not answer a ping, so the load balancer stops sending traffic to this instance
+func (a *App) readyz(c *gin.Context) {
+	if a.draining.Load() {
+		c.JSON(503, gin.H{"status": "shutting down"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readyTimeout)
+	defer cancel()
+	if err := database.Ping(ctx); err != nil {
+		slog.WarnContext(c.Request.Context(), "readiness check failed", "error", err)
+		c.JSON(503, gin.H{"status": "unavailable", "error": "database unreachable"})
+		return
+	}
+
+	c.JSON(200, gin.H{"status": "ready"})
+}
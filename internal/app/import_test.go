@@ -0,0 +1,122 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+)
+
+// TestImportReleasesDemotedStaff re-imports a staff member as a resident and checks their open
+// complaints are handed back, as when an admin changes the role
+func TestImportReleasesDemotedStaff(t *testing.T) {
+	useTestDatabase(t)
+
+	marker := fmt.Sprintf("import%d", time.Now().UnixNano())
+	f := seedSociety(t, marker)
+	s := newAPIServer(t)
+
+	db := database.ForSociety(f.society.ID)
+	assigned := time.Now()
+	open := models.Complaint{
+		Title: marker, Description: marker, Status: "in-progress", ResidentID: f.resident.ID, StaffID: &f.staff.ID,
+		CategoryID: f.category.ID, Priority: "medium", AssignedAt: &assigned,
+	}
+	if err := db.Create(&open).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	csv := fmt.Sprintf("name,email,role,unit\n%s,%s,user,%d\n", f.staff.Name, f.staff.Email, f.unit.ID)
+	var report services.ImportReport
+	s.expectJSON(200, "POST", "/api/admin/import/users", tokenFor(t, f.admin), csv, &report)
+	if report.Updated != 1 || report.Complaints == nil || report.Complaints.Unassigned+report.Complaints.Reassigned != 1 {
+		t.Fatalf("unexpected report %+v, complaints %+v", report, report.Complaints)
+	}
+
+	if err := db.First(&open, open.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if open.StaffID != nil && *open.StaffID == f.staff.ID {
+		t.Errorf("complaint %d is still assigned to the demoted staff member", open.ID)
+	}
+}
+
+// TestImportChecksEmailsInAnyCase imports rows whose emails differ only in case from existing
+// accounts of the society and of another society, plus an admin row, which import never accepts
+func TestImportChecksEmailsInAnyCase(t *testing.T) {
+	useTestDatabase(t)
+
+	marker := fmt.Sprintf("importcase%d", time.Now().UnixNano())
+	f := seedSociety(t, marker)
+	other := seedSociety(t, marker+"other")
+	s := newAPIServer(t)
+
+	csv := fmt.Sprintf("name,email,role,unit\n%s,%s,user,%d\nTaken,%s,user,\nBoss,boss-%s@example.com,admin,\n",
+		f.resident.Name, strings.ToUpper(f.resident.Email), f.unit.ID, strings.ToUpper(other.staff.Email), marker)
+	var report services.ImportReport
+	s.expectJSON(200, "POST", "/api/admin/import/users?dry_run=true", tokenFor(t, f.admin), csv, &report)
+	if len(report.Rows) != 3 {
+		t.Fatalf("expected 3 rows, got %+v", report.Rows)
+	}
+
+	if row := report.Rows[0]; row.Action != services.ImportUnchanged || row.Key != f.resident.Email {
+		t.Errorf("resident row %+v, want the existing resident matched and unchanged", row)
+	}
+	if row := report.Rows[1]; len(row.Errors) != 1 || !strings.Contains(row.Errors[0], "another society") {
+		t.Errorf("other society row %+v, want the email reported as taken", row)
+	}
+	if row := report.Rows[2]; len(row.Errors) != 1 || !strings.Contains(row.Errors[0], "admin") {
+		t.Errorf("admin row %+v, want it refused", row)
+	}
+
+	// Sign-in finds the account whatever the case of the email
+	s.expect(200, "POST", "/auth/login", "", map[string]string{"email": strings.ToUpper(f.resident.Email), "password": marker})
+}
+
+// TestImportUsersIsIdempotent previews an import, applies it and applies it again, checking only
+// the real run writes and the repeat finds every row unchanged
+func TestImportUsersIsIdempotent(t *testing.T) {
+	useTestDatabase(t)
+
+	marker := fmt.Sprintf("importagain%d", time.Now().UnixNano())
+	f := seedSociety(t, marker)
+	s := newAPIServer(t)
+	admin := tokenFor(t, f.admin)
+	db := database.ForSociety(f.society.ID)
+
+	csv := fmt.Sprintf("name,email,role,unit,password\nNew Resident,new-%[1]s@example.com,user,%[2]d,%[1]s\nNew Staff,newstaff-%[1]s@example.com,staff,,\n",
+		marker, f.unit.ID)
+	emails := []string{"new-" + marker + "@example.com", "newstaff-" + marker + "@example.com"}
+	count := func() int64 {
+		var n int64
+		db.Model(&models.User{}).Where("email IN ?", emails).Count(&n)
+		return n
+	}
+
+	var report services.ImportReport
+	s.expectJSON(200, "POST", "/api/admin/import/users?dry_run=true&send_invites=true", admin, csv, &report)
+	if report.Applied || report.Created != 2 || count() != 0 {
+		t.Fatalf("dry run report %+v left %d users, want a preview only", report, count())
+	}
+
+	// Without invites the staff row has no password, which refuses the whole file
+	body := s.expect(422, "POST", "/api/admin/import/users", admin, csv)
+	if !strings.Contains(string(body), "password is required") || count() != 0 {
+		t.Fatalf("response %s left %d users, want the file refused", truncate(string(body)), count())
+	}
+
+	s.expectJSON(200, "POST", "/api/admin/import/users?send_invites=true", admin, csv, &report)
+	if !report.Applied || report.Created != 2 || report.Invited != 1 || count() != 2 {
+		t.Fatalf("report %+v left %d users, want both created and the staff member invited", report, count())
+	}
+	s.expect(200, "POST", "/auth/login", "", map[string]string{"email": emails[0], "password": marker})
+
+	s.expectJSON(200, "POST", "/api/admin/import/users?send_invites=true", admin, csv, &report)
+	if report.Unchanged != 2 || report.Created != 0 || report.Invited != 0 || count() != 2 {
+		t.Errorf("repeat report %+v left %d users, want every row unchanged", report, count())
+	}
+}
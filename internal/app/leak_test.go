@@ -0,0 +1,240 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/config"
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/utils"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	leakEmail    = "leak@example.com"
+	leakPassword = "correct-horse-battery"
+	leakSecret   = "leak-test-secret"
+)
+
+var (
+	leakHashOnce sync.Once
+	leakHash     string
+
+	// leakRole is the role of every user the fake database returns
+	leakRole = "admin"
+
+	// passwordKey matches a "password" key in a JSON body, in any case
+	passwordKey = regexp.MustCompile(`(?i)"[a-z_]*password[a-z_]*"\s*:`)
+)
+
+// TestNoHandlerLeaksPasswordHash calls every registered route against a database that answers
+// each query with a row carrying a real bcrypt hash in its password column. A handler that
+// serialises a database model, or copies the column into its response, fails the test.
+func TestNoHandlerLeaksPasswordHash(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sqlDB := useLeakyDatabase(t)
+
+	token, err := utils.GenerateJWT(&models.User{ID: 1, Email: leakEmail, Role: "admin", SocietyID: 1}, leakSecret, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Default()
+	cfg.Auth.JWTSecret = leakSecret
+	router := build(cfg).router
+	seen := map[string]int{}
+
+	for _, route := range router.Routes() {
+		path := regexp.MustCompile(`:[a-z_]+`).ReplaceAllString(route.Path, "1")
+		name := route.Method + " " + route.Path
+
+		t.Run(name, func(t *testing.T) {
+			body := "{}"
+			if route.Path == "/auth/login" {
+				body = `{"email":"` + leakEmail + `","password":"` + leakPassword + `"}`
+			}
+
+			req := httptest.NewRequest(route.Method, path, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			closed := sqlDB.Stats().MaxIdleClosed
+			router.ServeHTTP(rec, req)
+
+			seen[name] = rec.Code
+			assertNoPassword(t, rec.Body.String())
+			// Only one connection is kept idle, so a second one taken beside the tenant session is closed
+			if sqlDB.Stats().MaxIdleClosed != closed {
+				t.Errorf("queried outside the request's tenant session on a second connection")
+			}
+		})
+	}
+
+	// Make sure the fake database really drives the handlers that return users
+	for _, name := range []string{
+		"POST /auth/login", "GET /api/me", "GET /api/admin/users", "GET /api/admin/users/:id", "GET /api/staff",
+		"GET /api/admin/analytics", "GET /api/admin/export/staff-performance",
+	} {
+		if seen[name] != http.StatusOK {
+			t.Errorf("%s returned %d, want 200; the leak check did not exercise it", name, seen[name])
+		}
+	}
+}
+
+func assertNoPassword(t *testing.T, body string) {
+	t.Helper()
+	if strings.Contains(body, leakHash) || strings.Contains(body, "$2a$") {
+		t.Errorf("response contains a bcrypt hash: %s", truncate(body))
+	}
+	if passwordKey.MatchString(body) {
+		t.Errorf("response contains a password field: %s", truncate(body))
+	}
+}
+
+func truncate(s string) string {
+	if len(s) > 300 {
+		return s[:300] + "..."
+	}
+	return s
+}
+
+// useLeakyDatabase points database.DB at the fake driver for the duration of the test
+func useLeakyDatabase(t *testing.T) *sql.DB {
+	return useFakeDatabase(t, "leaky")
+}
+
+// useFakeDatabase points database.DB at one of the fake drivers for the duration of the test
+func useFakeDatabase(t *testing.T, driverName string) *sql.DB {
+	leakHashOnce.Do(func() {
+		leakHash = utils.HashPassword(leakPassword)
+		sql.Register("leaky", leakDriver{})
+		sql.Register("slow-tenant", slowTenantDriver{})
+	})
+
+	sqlDB, err := sql.Open(driverName, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxIdleConns(1)
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := database.RegisterTenantScopes(db); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := database.DB
+	database.DB = db
+	t.Cleanup(func() {
+		database.DB = previous
+		sqlDB.Close()
+	})
+	return sqlDB
+}
+
+// leakDriver is a database/sql driver whose every query returns one row covering the columns
+// of all models, including users.password
+type leakDriver struct{}
+
+func (leakDriver) Open(string) (driver.Conn, error) { return leakConn{}, nil }
+
+type leakConn struct{}
+
+func (leakConn) Prepare(query string) (driver.Stmt, error) { return leakStmt{query}, nil }
+func (leakConn) Close() error                              { return nil }
+func (leakConn) Begin() (driver.Tx, error)                 { return leakTx{}, nil }
+
+func (leakConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	return newLeakRows(query), nil
+}
+
+func (leakConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+type leakStmt struct{ query string }
+
+func (leakStmt) Close() error  { return nil }
+func (leakStmt) NumInput() int { return -1 }
+func (leakStmt) Exec([]driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+func (s leakStmt) Query([]driver.Value) (driver.Rows, error) { return newLeakRows(s.query), nil }
+
+type leakTx struct{}
+
+func (leakTx) Commit() error   { return nil }
+func (leakTx) Rollback() error { return nil }
+
+// singleColumn matches "SELECT <one expression> FROM", which callers scan into a single value
+var singleColumn = regexp.MustCompile(`(?is)^\s*(?:WITH .*\)\s*)?SELECT\s+([^,]+?)\s+FROM\s`)
+
+func newLeakRows(query string) *leakRows {
+	if m := singleColumn.FindStringSubmatch(query); m != nil && m[1] != "*" && !strings.HasSuffix(m[1], ".*") {
+		// No society is suspended, or every request would be refused before reaching its handler
+		if strings.Contains(query, "suspended_at IS NOT NULL") {
+			return &leakRows{columns: []string{"value"}, values: []driver.Value{int64(0)}}
+		}
+		// Every feature is in the premium plan, so the plan-gated handlers run too
+		if strings.Trim(m[1], `"`) == "plan" {
+			return &leakRows{columns: []string{"plan"}, values: []driver.Value{"premium"}}
+		}
+		return &leakRows{columns: []string{"value"}, values: []driver.Value{int64(1)}}
+	}
+
+	now := time.Now()
+	row := []struct {
+		column string
+		value  driver.Value
+	}{
+		{"id", int64(1)}, {"name", "Leak Tester"}, {"email", leakEmail}, {"password", leakHash},
+		{"role", leakRole}, {"society_id", int64(1)}, {"unit_id", nil}, {"fcm_token", ""},
+		{"deactivated_at", nil}, {"deletion_requested_at", nil}, {"suspended_at", nil},
+		{"title", "Leaking tap"}, {"description", "Kitchen tap"}, {"status", "pending"},
+		{"resident_id", int64(1)}, {"staff_id", int64(1)}, {"category_id", int64(1)}, {"location_id", nil},
+		{"priority", "medium"}, {"is_emergency", false}, {"reopen_count", int64(0)}, {"duplicate_of_id", nil},
+		{"complaint_id", int64(1)}, {"user_id", int64(1)}, {"rating", int64(5)}, {"comment", "great"},
+		{"points", int64(10)}, {"reversed", false}, {"message", "hello"}, {"type", "unit"}, {"is_read", false},
+		{"total_points", int64(10)}, {"tasks_completed", int64(1)}, {"count", int64(1)},
+		{"created_at", now}, {"updated_at", now},
+	}
+
+	rows := &leakRows{}
+	for _, c := range row {
+		rows.columns = append(rows.columns, c.column)
+		rows.values = append(rows.values, c.value)
+	}
+	return rows
+}
+
+type leakRows struct {
+	columns []string
+	values  []driver.Value
+	done    bool
+}
+
+func (r *leakRows) Columns() []string { return r.columns }
+func (r *leakRows) Close() error      { return nil }
+
+func (r *leakRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, r.values)
+	return nil
+}
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/services"
+)
+
+// TestLocationTreeScopesComplaints builds a building through the API and checks the hierarchy
+// rules, where residents may report, and that listings and stats roll up to the building
+func TestLocationTreeScopesComplaints(t *testing.T) {
+	useTestDatabase(t)
+
+	nonce := fmt.Sprintf("location%d", time.Now().UnixNano())
+	a := seedSociety(t, "a"+nonce)
+	b := seedSociety(t, "b"+nonce)
+	s := newAPIServer(t)
+	admin, resident := tokenFor(t, a.admin), tokenFor(t, a.resident)
+
+	create := func(status int, locationType, name string, parentID *uint) models.Location {
+		t.Helper()
+		var location models.Location
+		s.expectJSON(status, "POST", "/api/admin/locations", admin,
+			map[string]interface{}{"type": locationType, "name": name, "parent_id": parentID}, &location)
+		return location
+	}
+	building := create(200, services.LocationBuilding, "Tower "+nonce, nil)
+	floor := create(200, services.LocationFloor, "3", &building.ID)
+	unit := create(200, services.LocationUnit, "301", &floor.ID)
+	lobby := create(200, services.LocationCommonArea, "Lobby", &building.ID)
+
+	create(400, services.LocationUnit, "302", &building.ID)
+	create(400, services.LocationFloor, "1", nil)
+	create(400, "garage", "G", nil)
+	create(400, services.LocationFloor, "1", &b.unit.ID)
+
+	// Residents report for their own unit or a common area
+	report := func(status int, token string, locationID uint) uint {
+		t.Helper()
+		var complaint struct {
+			ID uint `json:"id"`
+		}
+		s.expectJSON(status, "POST", "/api/complaints", token, map[string]interface{}{
+			"title": "Leak", "description": "water", "category_id": a.category.ID, "location_id": locationID,
+		}, &complaint)
+		return complaint.ID
+	}
+	report(400, resident, unit.ID)
+	report(400, resident, floor.ID)
+	s.expect(400, "PUT", "/api/admin/users/"+strconv.Itoa(int(a.resident.ID))+"/unit", admin, map[string]interface{}{"unit_id": floor.ID})
+	s.expect(200, "PUT", "/api/admin/users/"+strconv.Itoa(int(a.resident.ID))+"/unit", admin, map[string]interface{}{"unit_id": unit.ID})
+	inUnit := report(200, resident, unit.ID)
+	inLobby := report(200, resident, lobby.ID)
+
+	var listed []struct {
+		ID uint `json:"id"`
+	}
+	s.expectJSON(200, "GET", "/api/complaints?location_id="+strconv.Itoa(int(building.ID)), admin, nil, &listed)
+	var ids []uint
+	for _, c := range listed {
+		ids = append(ids, c.ID)
+	}
+	slices.Sort(ids)
+	if want := []uint{inUnit, inLobby}; !slices.Equal(ids, want) {
+		t.Errorf("complaints in the building: %v, want %v", ids, want)
+	}
+
+	var stats []struct {
+		LocationID uint             `json:"location_id"`
+		Total      int64            `json:"total"`
+		Open       int64            `json:"open"`
+		ByCategory map[string]int64 `json:"by_category"`
+	}
+	s.expectJSON(200, "GET", "/api/admin/stats/locations", admin, nil, &stats)
+	found := false
+	for _, stat := range stats {
+		if stat.LocationID != building.ID {
+			continue
+		}
+		found = true
+		if stat.Total != 2 || stat.Open != 2 || stat.ByCategory[strconv.Itoa(int(a.category.ID))] != 2 {
+			t.Errorf("building stats %+v, want both complaints rolled up", stat)
+		}
+	}
+	if !found {
+		t.Errorf("no stats for the building in %+v", stats)
+	}
+
+	// Locations something refers to stay, unused ones can go
+	s.expect(400, "DELETE", "/api/admin/locations/"+strconv.Itoa(int(floor.ID)), admin, nil)
+	s.expect(400, "DELETE", "/api/admin/locations/"+strconv.Itoa(int(lobby.ID)), admin, nil)
+	spare := create(200, services.LocationCommonArea, "Roof", &building.ID)
+	s.expect(200, "DELETE", "/api/admin/locations/"+strconv.Itoa(int(spare.ID)), admin, nil)
+	s.expect(404, "DELETE", "/api/admin/locations/"+strconv.Itoa(int(b.unit.ID)), admin, nil)
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/VinVorteX/flashtrack/internal/logging"
+	"github.com/gin-gonic/gin"
+)
+
+// captureLog sends the default logger to a buffer for the rest of the test
+func captureLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(logging.New(&buf, slog.LevelDebug))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+// accessRecords returns the "request" records in the captured log
+func accessRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var record map[string]any
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatalf("log line is not JSON: %v\n%s", err, line)
+		}
+		if record["msg"] == "request" {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+func TestAccessLogCarriesRequestID(t *testing.T) {
+	buf := captureLog(t)
+	router := build(testConfig()).router
+
+	// A usable ID from a proxy is kept, anything else is replaced
+	for incoming, keep := range map[string]bool{"edge-1234": true, "": false, "bad id\n": false} {
+		buf.Reset()
+		req := httptest.NewRequest("GET", "/healthz?reset_token=abc123&page=2", nil)
+		if incoming != "" {
+			req.Header.Set(logging.RequestIDHeader, incoming)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		id := w.Header().Get(logging.RequestIDHeader)
+		if keep && id != incoming {
+			t.Errorf("expected the incoming ID %q to be kept, got %q", incoming, id)
+		}
+		if !keep && (id == "" || id == incoming) {
+			t.Errorf("expected a generated ID for %q, got %q", incoming, id)
+		}
+
+		records := accessRecords(t, buf)
+		if len(records) != 1 {
+			t.Fatalf("expected one access record, got %d: %s", len(records), buf.String())
+		}
+		record := records[0]
+		if record["request_id"] != id || record["route"] != "/healthz" || record["status"] != float64(http.StatusOK) {
+			t.Errorf("unexpected access record %v", record)
+		}
+		if _, ok := record["latency_ms"]; !ok {
+			t.Errorf("expected latency_ms in %v", record)
+		}
+		if bytes.Contains(buf.Bytes(), []byte("abc123")) {
+			t.Errorf("expected the token in the query to be redacted: %s", buf.String())
+		}
+	}
+}
+
+func TestRecoveryLogsPanics(t *testing.T) {
+	buf := captureLog(t)
+	a := build(testConfig())
+	a.router.GET("/panic", func(*gin.Context) { panic("boom: SELECT * FROM users") })
+
+	w := httptest.NewRecorder()
+	a.router.ServeHTTP(w, httptest.NewRequest("GET", "/panic", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", w.Code)
+	}
+	problem := decodeProblem(t, w)
+	if problem["code"] != "internal_error" {
+		t.Errorf("expected an internal_error problem, got %v", problem)
+	}
+	for _, secret := range []string{"boom", "SELECT", "goroutine", "logging_test.go"} {
+		if strings.Contains(w.Body.String(), secret) {
+			t.Errorf("expected the response to hide %q: %s", secret, w.Body.String())
+		}
+	}
+
+	id := w.Header().Get(logging.RequestIDHeader)
+	if !bytes.Contains(buf.Bytes(), []byte(`"msg":"panic serving request"`)) || !bytes.Contains(buf.Bytes(), []byte(`"request_id":"`+id+`"`)) {
+		t.Errorf("expected the panic logged under request %s: %s", id, buf.String())
+	}
+	if records := accessRecords(t, buf); len(records) != 1 || records[0]["level"] != "ERROR" {
+		t.Errorf("expected one error-level access record, got %v", records)
+	}
+}
@@ -0,0 +1,114 @@
+package app
+
+import (
+	"context"
+	"database/sql/driver"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/config"
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/VinVorteX/flashtrack/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// poolSize stands in for DB_MAX_OPEN_CONNS
+	poolSize = 2
+	// tenantPause holds each tenant session's connection long enough for the concurrent requests to overlap
+	tenantPause = 10 * time.Millisecond
+	// poolDeadline bounds each request; only one stuck waiting for a connection runs into it
+	poolDeadline = 3 * time.Second
+)
+
+// TestRequestsFitThePool sends one request more than the pool has connections to every route at
+// once. Each request may hold just its tenant session's connection: a handler that also queries on
+// a second connection leaves every request holding one and waiting for another, which only the
+// request deadline breaks.
+func TestRequestsFitThePool(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sqlDB := useFakeDatabase(t, "slow-tenant")
+	sqlDB.SetMaxOpenConns(poolSize)
+
+	token, err := utils.GenerateJWT(&models.User{ID: 1, Email: leakEmail, Role: "admin", SocietyID: 1}, leakSecret, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Default()
+	cfg.Auth.JWTSecret = leakSecret
+	router := build(cfg).router
+
+	for _, route := range router.Routes() {
+		if route.Path == "/api/ws/notifications" {
+			continue
+		}
+		path := regexp.MustCompile(`:[a-z_]+`).ReplaceAllString(route.Path, "1")
+		body := "{}"
+		if route.Path == "/api/admin/import/users" {
+			body = "name,email,role\nNew Resident,new@example.com,user\n"
+		}
+
+		t.Run(route.Method+" "+route.Path, func(t *testing.T) {
+			// Platform routes refuse anyone but the super-admin
+			if strings.HasPrefix(route.Path, "/api/platform/") {
+				leakRole = services.RoleSuperAdmin
+				defer func() { leakRole = "admin" }()
+			}
+
+			var wg sync.WaitGroup
+			expired := make(chan bool, poolSize+1)
+			for i := 0; i < poolSize+1; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					ctx, cancel := context.WithTimeout(context.Background(), poolDeadline)
+					defer cancel()
+
+					req := httptest.NewRequest(route.Method, path, strings.NewReader(body)).WithContext(ctx)
+					req.Header.Set("Content-Type", "application/json")
+					req.Header.Set("Authorization", "Bearer "+token)
+					router.ServeHTTP(httptest.NewRecorder(), req)
+					expired <- ctx.Err() != nil
+				}()
+			}
+
+			done := make(chan struct{})
+			go func() {
+				wg.Wait()
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * poolDeadline):
+				t.Fatalf("%d concurrent requests never finished on a pool of %d connections", poolSize+1, poolSize)
+			}
+
+			close(expired)
+			for stuck := range expired {
+				if stuck {
+					t.Errorf("a request ran into its deadline waiting for a connection; the handler holds more than its tenant session's")
+				}
+			}
+		})
+	}
+}
+
+// slowTenantDriver is leakDriver with a pause when a tenant session sets its role
+type slowTenantDriver struct{}
+
+func (slowTenantDriver) Open(string) (driver.Conn, error) { return slowTenantConn{}, nil }
+
+type slowTenantConn struct{ leakConn }
+
+func (c slowTenantConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if strings.HasPrefix(query, "SET ROLE ") {
+		time.Sleep(tenantPause)
+	}
+	return c.leakConn.ExecContext(ctx, query, args)
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+)
+
+// TestProfileUpdatesOwnAccount edits the resident's profile through /api/me and checks the
+// stored fields, that partial updates leave the rest alone and that no hash is ever returned
+func TestProfileUpdatesOwnAccount(t *testing.T) {
+	useTestDatabase(t)
+
+	marker := fmt.Sprintf("profile%d", time.Now().UnixNano())
+	f := seedSociety(t, marker)
+	s := newAPIServer(t)
+	resident := tokenFor(t, f.resident)
+
+	var me map[string]interface{}
+	s.expectJSON(200, "PATCH", "/api/me", resident, map[string]string{
+		"name": "  Renamed  ", "phone": "+91 98765 43210", "avatar_url": "https://example.com/me.png",
+		"language": "hi-IN", "timezone": "Asia/Kolkata", "fcm_token": "device-" + marker,
+	}, &me)
+	if me["name"] != "Renamed" || me["phone"] != "+91 98765 43210" || me["timezone"] != "Asia/Kolkata" {
+		t.Errorf("updated profile %v", me)
+	}
+
+	// Only the fields sent change, and a refused change writes nothing
+	s.expectJSON(200, "PATCH", "/api/me", resident, map[string]string{"language": "en"}, &me)
+	s.expect(400, "PATCH", "/api/me", resident, map[string]string{"name": "Again", "timezone": "Mars/Olympus"})
+	var stored models.User
+	if err := database.ForSociety(f.society.ID).First(&stored, f.resident.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.Name != "Renamed" || stored.Language != "en" || stored.AvatarURL != "https://example.com/me.png" || stored.FCMToken != "device-"+marker {
+		t.Errorf("stored profile %+v", stored)
+	}
+
+	for _, body := range []string{
+		string(s.expect(200, "GET", "/api/me", resident, nil)),
+		string(s.expect(200, "PATCH", "/api/me", resident, map[string]string{})),
+	} {
+		if strings.Contains(body, "password") || strings.Contains(body, "$2a$") || strings.Contains(body, "fcm_token") {
+			t.Errorf("profile response exposes credentials: %s", truncate(body))
+		}
+	}
+
+	s.expect(403, "POST", "/api/me/password", resident, map[string]string{"current_password": "wrong", "new_password": "password456"})
+	s.expect(200, "POST", "/api/me/password", resident, map[string]string{"current_password": marker, "new_password": "password456"})
+	s.expect(200, "POST", "/auth/login", "", map[string]string{"email": f.resident.Email, "password": "password456"})
+	s.expect(401, "POST", "/auth/login", "", map[string]string{"email": f.resident.Email, "password": marker})
+}
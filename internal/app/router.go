@@ -0,0 +1,181 @@
+package app
+
+import (
+	"net/http"
+
+	"github.com/VinVorteX/flashtrack/internal/apierror"
+	"github.com/VinVorteX/flashtrack/internal/logging"
+	"github.com/VinVorteX/flashtrack/internal/middleware"
+	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/VinVorteX/flashtrack/internal/telemetry"
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// traced reports whether a request gets a trace span; probes and scrapes would only add noise
+func traced(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/healthz", "/readyz", "/metrics":
+		return false
+	}
+	return true
+}
+
+// setupRouter registers middleware and every API route
+func setupRouter(a *App) *gin.Engine {
+	r := gin.New()
+
+	// Tag each request with an ID and a trace span, log it and count it once served, and turn
+	// panics into logged internal_error problems
+	r.Use(
+		middleware.RequestID(),
+		otelgin.Middleware(telemetry.ServiceName, otelgin.WithFilter(traced)),
+		middleware.AccessLog(),
+		telemetry.Middleware(),
+		middleware.Recovery(),
+	)
+
+	// CORS middleware
+	r.Use(cors.New(cors.Config{
+		AllowOrigins:     a.Config.Server.CORSOrigins,
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", logging.RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", logging.RequestIDHeader},
+		AllowCredentials: true,
+	}))
+
+	// Health probes for the orchestrator, outside authentication
+	r.GET("/healthz", healthz)
+	r.GET("/readyz", a.readyz)
+
+	// Prometheus scrape endpoint
+	r.GET("/metrics", gin.WrapH(telemetry.Handler()))
+
+	auth := r.Group("/auth")
+	{
+		auth.POST("/register", a.auth.Register)
+		auth.POST("/login", a.auth.Login)
+		auth.POST("/invites/accept", a.imports.AcceptInvite)
+	}
+
+	// WebSocket endpoint for real-time notifications. It stays open for as long as the client is
+	// connected, so it must not hold a tenant session and with it a pooled connection.
+	authenticate := middleware.AuthMiddleware(a.Config.Auth.JWTSecret, a.roles.Roles)
+	r.GET("/api/ws/notifications", authenticate, a.notifications.WebSocketHandler)
+
+	// Platform routes for super-admins, across every society. They take no tenant session: their
+	// queries run on the platform login, and pinning a tenant connection as well would have each
+	// request hold two of the pool's connections.
+	platformRoutes := r.Group("/api/platform")
+	platformRoutes.Use(authenticate, middleware.RequirePermission(services.PermPlatformManage))
+	{
+		platformRoutes.GET("/societies", a.platform.ListSocieties)
+		platformRoutes.POST("/societies", a.platform.CreateSociety)
+		platformRoutes.POST("/societies/:id/suspend", a.platform.SuspendSociety)
+		platformRoutes.POST("/societies/:id/resume", a.platform.ResumeSociety)
+		platformRoutes.PUT("/societies/:id/plan", a.platform.SetSocietyPlan)
+		platformRoutes.DELETE("/societies/:id", a.platform.DeleteSociety)
+		platformRoutes.POST("/societies/:id/impersonate", a.platform.ImpersonateSocietyAdmin)
+		platformRoutes.GET("/plans", a.plans.GetPlans)
+		platformRoutes.GET("/usage", a.platform.GetPlatformUsage)
+		platformRoutes.GET("/audit-logs", a.platform.GetPlatformAuditLogs)
+	}
+
+	api := r.Group("/api")
+	api.Use(authenticate, middleware.TenantMiddleware())
+	limitImport := middleware.LimitBody(a.Config.Uploads.MaxImportBytes)
+	requireSLA := middleware.RequireFeature(a.plans.Plans, services.FeatureSLA)
+	requireAnalytics := middleware.RequireFeature(a.plans.Plans, services.FeatureAnalytics)
+	requireExports := middleware.RequireFeature(a.plans.Plans, services.FeatureExports)
+
+	// Profile routes for the signed-in user
+	api.GET("/me", a.profiles.GetMe)
+	api.PATCH("/me", a.profiles.UpdateMe)
+	api.POST("/me/password", a.profiles.ChangeMyPassword)
+	api.DELETE("/me", a.profiles.DeleteMe)
+
+	// Notification routes
+	api.GET("/notifications", a.notifications.GetNotifications)
+	api.POST("/notifications/read", a.notifications.MarkNotificationRead)
+
+	// Complaint routes - accessible to all authenticated users
+	api.GET("/complaints", a.complaints.GetComplaints)
+	api.GET("/complaints/export", requireExports, a.complaints.ExportComplaints)
+	api.GET("/categories", a.categories.GetCategories)
+	api.GET("/locations", a.locations.GetLocations)
+
+	// Resident routes
+	api.POST("/complaints", middleware.RequirePermission(services.PermComplaintCreate), a.complaints.CreateComplaint)
+	api.PATCH("/complaints/:id", middleware.RequirePermission(services.PermComplaintEditOwn), a.complaints.UpdateComplaint)
+	api.POST("/complaints/:id/cancel", middleware.RequirePermission(services.PermComplaintEditOwn), a.complaints.CancelComplaint)
+	api.POST("/complaints/:id/reopen", middleware.RequirePermission(services.PermComplaintEditOwn), a.complaints.ReopenComplaint)
+
+	// Staff routes
+	staffRoutes := api.Group("/staff")
+	{
+		staffRoutes.PUT("/resolve/:id", middleware.RequirePermission(services.PermComplaintResolve), a.complaints.ResolveComplaint)
+		staffRoutes.GET("/points", middleware.RequirePermission(services.PermPointsView), a.feedback.GetStaffPoints)
+	}
+
+	// Feedback routes
+	api.POST("/feedback", middleware.RequirePermission(services.PermFeedbackSubmit), a.feedback.SubmitFeedback)
+	api.POST("/feedback/check", a.feedback.CheckPendingFeedback)
+	api.GET("/feedback", middleware.RequirePermission(services.PermFeedbackView), a.feedback.GetFeedbackForAdmin)
+
+	// Management routes, each gated by the permission it needs
+	adminRoutes := api.Group("/admin")
+	{
+		adminRoutes.PUT("/assign", middleware.RequirePermission(services.PermComplaintAssign), a.complaints.AssignStaff)
+		adminRoutes.GET("/stats", middleware.RequirePermission(services.PermStatsView), a.admin.GetSocietyStats)
+		adminRoutes.GET("/settings", middleware.RequirePermission(services.PermSettingsManage), a.admin.GetSocietySettings)
+		adminRoutes.PUT("/settings", middleware.RequirePermission(services.PermSettingsManage), a.admin.UpdateSocietySettings)
+		adminRoutes.PUT("/staff/:id/categories", middleware.RequirePermission(services.PermStaffManage), a.admin.SetStaffCategories)
+		adminRoutes.GET("/staff/:id/profile", middleware.RequirePermission(services.PermStaffManage), a.staffProfiles.GetStaffProfile)
+		adminRoutes.PUT("/staff/:id/profile", middleware.RequirePermission(services.PermStaffManage), a.staffProfiles.UpdateStaffProfile)
+		adminRoutes.POST("/staff/:id/leaves", middleware.RequirePermission(services.PermStaffManage), a.staffProfiles.CreateStaffLeave)
+		adminRoutes.GET("/leaves", middleware.RequirePermission(services.PermStaffManage), a.staffProfiles.GetLeaves)
+		adminRoutes.POST("/holidays", middleware.RequirePermission(services.PermStaffManage), a.staffProfiles.CreateHoliday)
+		adminRoutes.DELETE("/leaves/:id", middleware.RequirePermission(services.PermStaffManage), a.staffProfiles.DeleteLeave)
+		adminRoutes.GET("/escalation-policies", middleware.RequirePermission(services.PermEscalationManage), requireSLA, a.escalations.GetEscalationPolicies)
+		adminRoutes.POST("/escalation-policies", middleware.RequirePermission(services.PermEscalationManage), requireSLA, a.escalations.CreateEscalationPolicy)
+		adminRoutes.DELETE("/escalation-policies/:id", middleware.RequirePermission(services.PermEscalationManage), requireSLA, a.escalations.DeleteEscalationPolicy)
+		adminRoutes.GET("/complaints/:id/escalations", middleware.RequirePermission(services.PermEscalationManage), requireSLA, a.escalations.GetComplaintEscalations)
+		adminRoutes.POST("/complaints/merge", middleware.RequirePermission(services.PermComplaintMerge), a.complaints.MergeComplaints)
+		adminRoutes.PUT("/complaints/:id/priority", middleware.RequirePermission(services.PermComplaintPrioritize), a.complaints.UpdateComplaintPriority)
+		adminRoutes.PUT("/categories/:id", middleware.RequirePermission(services.PermCategoryManage), a.categories.UpdateCategory)
+		adminRoutes.POST("/import/categories", middleware.RequirePermission(services.PermCategoryManage), limitImport, a.imports.ImportCategories)
+		adminRoutes.POST("/locations", middleware.RequirePermission(services.PermLocationManage), a.locations.CreateLocation)
+		adminRoutes.DELETE("/locations/:id", middleware.RequirePermission(services.PermLocationManage), a.locations.DeleteLocation)
+		adminRoutes.GET("/users", middleware.RequirePermission(services.PermUserManage), a.users.ListUsers)
+		adminRoutes.GET("/users/:id", middleware.RequirePermission(services.PermUserManage), a.users.GetUser)
+		adminRoutes.PATCH("/users/:id", middleware.RequirePermission(services.PermUserManage), a.users.UpdateUser)
+		adminRoutes.POST("/users/:id/deactivate", middleware.RequirePermission(services.PermUserManage), a.users.DeactivateUser)
+		adminRoutes.POST("/users/:id/reactivate", middleware.RequirePermission(services.PermUserManage), a.users.ReactivateUser)
+		adminRoutes.POST("/users/:id/reset-password", middleware.RequirePermission(services.PermUserManage), a.users.ResetUserPassword)
+		adminRoutes.DELETE("/users/:id", middleware.RequirePermission(services.PermUserManage), a.users.DeleteUser)
+		adminRoutes.PUT("/users/:id/unit", middleware.RequirePermission(services.PermUserManage), a.locations.SetUserUnit)
+		adminRoutes.POST("/import/users", middleware.RequirePermission(services.PermUserManage), limitImport, a.imports.ImportUsers)
+		adminRoutes.GET("/audit-logs", middleware.RequirePermission(services.PermAuditView), a.users.GetAuditLogs)
+		adminRoutes.GET("/stats/locations", middleware.RequirePermission(services.PermAnalyticsView), requireAnalytics, a.locations.GetLocationStats)
+		adminRoutes.GET("/analytics", middleware.RequirePermission(services.PermAnalyticsView), requireAnalytics, a.analytics.GetAnalytics)
+		adminRoutes.GET("/export/feedback", middleware.RequirePermission(services.PermReportExport), requireExports, a.exports.ExportFeedback)
+		adminRoutes.GET("/export/staff-performance", middleware.RequirePermission(services.PermReportExport), requireExports, a.exports.ExportStaffPerformance)
+		adminRoutes.GET("/plan", middleware.RequirePermission(services.PermSettingsManage), a.plans.GetPlanUsage)
+		adminRoutes.GET("/roles", middleware.RequirePermission(services.PermRoleManage), a.roles.GetRoles)
+		adminRoutes.POST("/roles", middleware.RequirePermission(services.PermRoleManage), a.roles.CreateRole)
+		adminRoutes.PUT("/roles/:id", middleware.RequirePermission(services.PermRoleManage), a.roles.UpdateRole)
+		adminRoutes.DELETE("/roles/:id", middleware.RequirePermission(services.PermRoleManage), a.roles.DeleteRole)
+		adminRoutes.GET("/permissions", middleware.RequirePermission(services.PermRoleManage), a.roles.GetPermissions)
+	}
+
+	// Staff list endpoint for admins, supports ?available=true&category_id=
+	api.GET("/staff", a.admin.GetStaffMembers)
+
+	// Unknown paths get the same problem document as every other error
+	r.NoRoute(func(c *gin.Context) {
+		apierror.Write(c, apierror.New(apierror.CodeNotFound, "no such endpoint"))
+	})
+
+	return r
+}
@@ -0,0 +1,365 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/repository"
+	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+)
+
+// featureRoutes are gated by a plan feature the free plan lacks
+var featureRoutes = []string{
+	"GET /api/complaints/export",
+	"GET /api/admin/escalation-policies",
+	"POST /api/admin/escalation-policies",
+	"DELETE /api/admin/escalation-policies/:id",
+	"GET /api/admin/complaints/:id/escalations",
+	"GET /api/admin/stats/locations",
+	"GET /api/admin/analytics",
+	"GET /api/admin/export/feedback",
+	"GET /api/admin/export/staff-performance",
+}
+
+func newMarker(prefix string) string {
+	return fmt.Sprintf("%s%d", prefix, time.Now().UnixNano())
+}
+
+func id(v uint) string {
+	return fmt.Sprint(v)
+}
+
+// TestEveryRoute walks a society through its life over HTTP as each role and fails if any
+// registered route was never reached
+func TestEveryRoute(t *testing.T) {
+	useTestDatabase(t)
+	marker := newMarker("routes")
+	f := seedSociety(t, marker)
+	platform := seedSuperAdmin(t, marker)
+	s := newAPIServer(t)
+
+	admin, staff, resident := tokenFor(t, f.admin), tokenFor(t, f.staff), tokenFor(t, f.resident)
+	superadmin := tokenFor(t, platform)
+
+	s.expect(200, "GET", "/healthz", "", nil)
+	s.expect(200, "GET", "/readyz", "", nil)
+
+	// Sign-up, sign-in and invites
+	s.expect(200, "POST", "/auth/register", "", map[string]interface{}{
+		"name": marker, "email": "new-" + marker + "@example.com", "password": "password123",
+		"role": "user", "society_id": f.society.ID, "unit_id": f.unit.ID,
+	})
+	var login struct {
+		Token string `json:"token"`
+		User  struct {
+			ID uint `json:"id"`
+		} `json:"user"`
+	}
+	s.expectJSON(200, "POST", "/auth/login", "", map[string]string{"email": "new-" + marker + "@example.com", "password": "password123"}, &login)
+	member := login.Token
+
+	invited := models.User{Name: marker, Email: "invited-" + marker + "@example.com", Role: "user"}
+	if err := database.ForSociety(f.society.ID).Create(&invited).Error; err != nil {
+		t.Fatal(err)
+	}
+	invites := &services.InviteService{Invites: repository.NewInviteRepository()}
+	invite, err := invites.Create(database.WithSession(context.Background(), database.ForSociety(f.society.ID)), invited.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.expect(200, "POST", "/auth/invites/accept", "", map[string]string{"token": invite, "password": "password123"})
+
+	// The signed-in user's own account
+	s.expect(200, "GET", "/api/me", member, nil)
+	s.expect(200, "PATCH", "/api/me", member, map[string]string{"phone": "555-0100"})
+	s.expect(200, "POST", "/api/me/password", member, map[string]string{"current_password": "password123", "new_password": "password456"})
+	s.expect(200, "GET", "/api/notifications", resident, nil)
+	s.expect(200, "POST", "/api/notifications/read", resident, map[string]uint{"notification_id": f.notice.ID})
+
+	// Residents report, edit and cancel complaints
+	s.expect(200, "GET", "/api/categories", resident, nil)
+	s.expect(200, "GET", "/api/locations", resident, nil)
+	s.expect(200, "GET", "/api/staff", admin, nil)
+	var primary, duplicate, cancelled struct {
+		ID uint `json:"id"`
+	}
+	for _, c := range []interface{}{&primary, &duplicate, &cancelled} {
+		s.expectJSON(200, "POST", "/api/complaints", resident, map[string]interface{}{
+			"title": marker, "description": marker, "category_id": f.category.ID,
+		}, c)
+	}
+	s.expect(200, "GET", "/api/complaints", resident, nil)
+	s.expect(200, "PATCH", "/api/complaints/"+id(primary.ID), resident, map[string]string{"description": "still " + marker})
+	s.expect(200, "POST", "/api/complaints/"+id(cancelled.ID)+"/cancel", resident, nil)
+
+	// Admins triage and assign, staff resolve, residents rate and reopen
+	s.expect(200, "POST", "/api/admin/complaints/merge", admin, map[string]interface{}{"primary_id": primary.ID, "duplicate_ids": []uint{duplicate.ID}})
+	s.expect(200, "PUT", "/api/admin/complaints/"+id(primary.ID)+"/priority", admin, map[string]string{"priority": "high"})
+	s.expect(200, "PUT", "/api/admin/assign", admin, map[string]interface{}{"complaint_id": primary.ID, "staff_id": f.staff.ID, "force": true})
+	s.expect(200, "PUT", "/api/staff/resolve/"+id(primary.ID), staff, nil)
+	s.expect(200, "POST", "/api/feedback/check", resident, map[string][]uint{"complaint_ids": {primary.ID}})
+	s.expect(200, "POST", "/api/feedback", resident, map[string]interface{}{"complaint_id": primary.ID, "rating": 5})
+	s.expect(200, "GET", "/api/staff/points", staff, nil)
+	s.expect(200, "POST", "/api/complaints/"+id(primary.ID)+"/reopen", resident, map[string]string{"reason": "still broken"})
+	s.expect(200, "GET", "/api/feedback", admin, nil)
+	s.expect(200, "GET", "/api/complaints/export", admin, nil)
+
+	// Society settings, staff scheduling and escalation
+	s.expect(200, "GET", "/api/admin/stats", staff, nil)
+	s.expect(200, "GET", "/api/admin/settings", admin, nil)
+	s.expect(200, "PUT", "/api/admin/settings", admin, map[string]int{"reopen_window_hours": 48})
+	s.expect(200, "GET", "/api/admin/plan", admin, nil)
+	s.expect(200, "PUT", "/api/admin/staff/"+id(f.staff.ID)+"/categories", admin, map[string][]uint{"category_ids": {f.category.ID}})
+	s.expect(200, "GET", "/api/admin/staff/"+id(f.staff.ID)+"/profile", admin, nil)
+	s.expect(200, "PUT", "/api/admin/staff/"+id(f.staff.ID)+"/profile", admin, map[string]interface{}{
+		"shift_start": "08:00", "shift_end": "20:00", "working_days": []int{1, 2, 3, 4, 5}, "max_concurrent": 3,
+	})
+	s.expect(200, "POST", "/api/admin/staff/"+id(f.staff.ID)+"/leaves", admin, map[string]string{"start_date": "2030-01-06", "reason": marker})
+	s.expect(200, "POST", "/api/admin/holidays", admin, map[string]string{"start_date": "2030-12-25", "reason": marker})
+	s.expect(200, "GET", "/api/admin/leaves", admin, nil)
+	s.expect(200, "DELETE", "/api/admin/leaves/"+id(f.leave.ID), admin, nil)
+	s.expect(200, "GET", "/api/admin/escalation-policies", admin, nil)
+	s.expect(200, "POST", "/api/admin/escalation-policies", admin, map[string]interface{}{"trigger": "unresolved", "after_hours": 24, "level": 2, "action": "notify_admin"})
+	s.expect(200, "DELETE", "/api/admin/escalation-policies/"+id(f.policy.ID), admin, nil)
+	s.expect(200, "GET", "/api/admin/complaints/"+id(primary.ID)+"/escalations", admin, nil)
+
+	// Categories, locations and imports
+	s.expect(200, "PUT", "/api/admin/categories/"+id(f.category.ID), admin, map[string]int{"sla_hours": 12})
+	s.expect(200, "POST", "/api/admin/import/categories?dry_run=true", admin, "name,sla_hours,default_priority\nImported "+marker+",12,low\n")
+	s.expect(200, "POST", "/api/admin/import/users?dry_run=true", admin, "name,email,role\n"+marker+",imported-"+marker+"@example.com,user\n")
+	var gym models.Location
+	s.expectJSON(200, "POST", "/api/admin/locations", admin, map[string]string{"type": "common_area", "name": "Gym " + marker}, &gym)
+	s.expect(200, "DELETE", "/api/admin/locations/"+id(gym.ID), admin, nil)
+
+	// User management, ending with the new member's deletion
+	userPath := "/api/admin/users/" + id(login.User.ID)
+	s.expect(200, "GET", "/api/admin/users", admin, nil)
+	s.expect(200, "GET", userPath, admin, nil)
+	s.expect(200, "PATCH", userPath, admin, map[string]string{"name": "Renamed " + marker})
+	s.expect(200, "PUT", userPath+"/unit", admin, map[string]uint{"unit_id": f.unit.ID})
+	s.expect(200, "POST", userPath+"/deactivate", admin, nil)
+	s.expect(200, "POST", userPath+"/reactivate", admin, nil)
+	s.expect(200, "POST", userPath+"/reset-password", admin, map[string]string{"password": "password789"})
+	s.expect(202, "DELETE", "/api/me", member, map[string]string{"password": "password789", "reason": "moving out"})
+	s.expect(200, "DELETE", userPath, admin, nil)
+
+	// Custom roles, reports and the audit trail
+	var role models.Role
+	s.expect(200, "GET", "/api/admin/permissions", admin, nil)
+	s.expect(200, "GET", "/api/admin/roles", admin, nil)
+	s.expectJSON(201, "POST", "/api/admin/roles", admin, map[string]interface{}{"name": "auditor-" + marker, "permissions": []string{services.PermAuditView}}, &role)
+	s.expect(200, "PUT", "/api/admin/roles/"+id(role.ID), admin, map[string]string{"description": marker})
+	s.expect(200, "DELETE", "/api/admin/roles/"+id(role.ID), admin, nil)
+	s.expect(200, "GET", "/api/admin/stats/locations", admin, nil)
+	s.expect(200, "GET", "/api/admin/analytics", admin, nil)
+	s.expect(200, "GET", "/api/admin/export/feedback", admin, nil)
+	s.expect(200, "GET", "/api/admin/export/staff-performance", admin, nil)
+	s.expect(200, "GET", "/api/admin/audit-logs", admin, nil)
+
+	// The platform operator manages societies
+	var created struct {
+		Society struct {
+			ID uint `json:"id"`
+		} `json:"society"`
+	}
+	s.expect(200, "GET", "/api/platform/societies", superadmin, nil)
+	s.expectJSON(201, "POST", "/api/platform/societies", superadmin, map[string]string{"name": "platform-" + marker, "plan": "free"}, &created)
+	societyPath := "/api/platform/societies/" + id(created.Society.ID)
+	s.expect(200, "PUT", societyPath+"/plan", superadmin, map[string]string{"plan": "standard"})
+	s.expect(200, "POST", societyPath+"/suspend", superadmin, map[string]string{"reason": "test"})
+	s.expect(200, "POST", societyPath+"/resume", superadmin, nil)
+	s.expect(200, "POST", societyPath+"/suspend", superadmin, map[string]string{"reason": "test"})
+	s.expect(200, "DELETE", societyPath, superadmin, nil)
+	s.expect(200, "POST", "/api/platform/societies/"+id(f.society.ID)+"/impersonate", superadmin, map[string]string{"reason": "support ticket"})
+	s.expect(200, "GET", "/api/platform/plans", superadmin, nil)
+	s.expect(200, "GET", "/api/platform/usage", superadmin, nil)
+	s.expect(200, "GET", "/api/platform/audit-logs", superadmin, nil)
+
+	s.dial("/api/ws/notifications", staff).Close()
+
+	if missing := s.uncalled(); len(missing) > 0 {
+		t.Errorf("routes never called:\n%s", strings.Join(missing, "\n"))
+	}
+}
+
+// TestRoutePermissions checks that every route refuses callers without a session, roles without
+// the permission it needs and societies whose plan lacks its feature
+func TestRoutePermissions(t *testing.T) {
+	useTestDatabase(t)
+	marker := newMarker("perms")
+	f := seedSociety(t, marker)
+	platform := seedSuperAdmin(t, marker)
+	s := newAPIServer(t)
+
+	tokens := map[string]string{
+		"resident":   tokenFor(t, f.resident),
+		"staff":      tokenFor(t, f.staff),
+		"admin":      tokenFor(t, f.admin),
+		"superadmin": tokenFor(t, platform),
+	}
+	// denied reports whether a role must be refused a route before its handler runs
+	denied := func(role, route string) bool {
+		path := route[strings.Index(route, " ")+1:]
+		admin := strings.HasPrefix(path, "/api/admin/")
+		platform := strings.HasPrefix(path, "/api/platform/")
+		switch role {
+		case "resident":
+			return admin || platform
+		case "staff":
+			return (admin && route != "GET /api/admin/stats") || platform
+		case "admin":
+			return platform
+		default:
+			return admin
+		}
+	}
+
+	for _, r := range s.routes {
+		if !strings.HasPrefix(r.Path, "/api/") {
+			continue
+		}
+		path := routeParam.ReplaceAllString(r.Path, id(f.complaint.ID))
+		route := r.Method + " " + r.Path
+
+		s.expect(401, r.Method, path, "", nil)
+		for role, token := range tokens {
+			if !denied(role, route) {
+				continue
+			}
+			if code, body := s.do(r.Method, path, token, nil); code != 403 {
+				t.Errorf("%s as %s returned %d, want 403: %s", route, role, code, truncate(string(body)))
+			}
+		}
+	}
+
+	if err := database.DB.Model(&f.society).Update("plan", "free").Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, route := range featureRoutes {
+		method, path := route[:strings.Index(route, " ")], route[strings.Index(route, " ")+1:]
+		code, body := s.do(method, routeParam.ReplaceAllString(path, id(f.complaint.ID)), tokens["admin"], nil)
+		var refusal struct {
+			Feature string `json:"feature"`
+		}
+		json.Unmarshal(body, &refusal)
+		if code != 403 || refusal.Feature == "" {
+			t.Errorf("%s on the free plan returned %d, want 403 naming the feature: %s", route, code, truncate(string(body)))
+		}
+	}
+}
+
+// TestWebSocketDeliversAssignment checks that assigning a complaint reaches the staff member's
+// open notification socket
+func TestWebSocketDeliversAssignment(t *testing.T) {
+	useTestDatabase(t)
+	marker := newMarker("ws")
+	f := seedSociety(t, marker)
+	s := newAPIServer(t)
+
+	// An unread notification is sent as soon as the socket is registered, so receiving it
+	// shows that later pushes have somewhere to go
+	pending := models.Notification{UserID: f.staff.ID, Title: marker, Message: marker, Type: "status_update"}
+	if err := database.ForSociety(f.society.ID).Create(&pending).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	conn := s.dial("/api/ws/notifications", tokenFor(t, f.staff))
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+
+	type message struct {
+		ID          uint   `json:"id"`
+		Type        string `json:"type"`
+		ComplaintID *uint  `json:"complaint_id"`
+	}
+	var first message
+	if err := conn.ReadJSON(&first); err != nil {
+		t.Fatalf("read unread notification: %v", err)
+	}
+	if first.ID != pending.ID {
+		t.Errorf("first message is notification %d, want the unread %d", first.ID, pending.ID)
+	}
+
+	// An idle socket must not hold on to a pooled database connection
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inUse := sqlDB.Stats().InUse; inUse != 0 {
+		t.Errorf("%d database connections in use while the socket is open, want 0", inUse)
+	}
+
+	var complaint struct {
+		ID uint `json:"id"`
+	}
+	s.expectJSON(200, "POST", "/api/complaints", tokenFor(t, f.resident), map[string]interface{}{
+		"title": marker, "description": marker, "category_id": f.category.ID,
+	}, &complaint)
+	s.expect(200, "PUT", "/api/admin/assign", tokenFor(t, f.admin), map[string]interface{}{
+		"complaint_id": complaint.ID, "staff_id": f.staff.ID, "force": true,
+	})
+
+	for {
+		var m message
+		if err := conn.ReadJSON(&m); err != nil {
+			t.Fatalf("no assignment notification for complaint %d: %v", complaint.ID, err)
+		}
+		if m.Type == "assignment" && m.ComplaintID != nil && *m.ComplaintID == complaint.ID {
+			return
+		}
+	}
+}
+
+// TestFeedbackPointsFlow follows a complaint from report to rating over HTTP and checks the
+// staff member's points after each rating and after the resident reopens it
+func TestFeedbackPointsFlow(t *testing.T) {
+	useTestDatabase(t)
+	marker := newMarker("points")
+	f := seedSociety(t, marker)
+	s := newAPIServer(t)
+	admin, staff, resident := tokenFor(t, f.admin), tokenFor(t, f.staff), tokenFor(t, f.resident)
+
+	points := func() models.StaffPoints {
+		t.Helper()
+		var p models.StaffPoints
+		s.expectJSON(200, "GET", "/api/staff/points", staff, nil, &p)
+		return p
+	}
+	start := points()
+
+	var complaint struct {
+		ID uint `json:"id"`
+	}
+	s.expectJSON(200, "POST", "/api/complaints", resident, map[string]interface{}{
+		"title": marker, "description": marker, "category_id": f.category.ID,
+	}, &complaint)
+	path := id(complaint.ID)
+
+	// Feedback is refused until the complaint is resolved
+	s.expect(400, "POST", "/api/feedback", resident, map[string]interface{}{"complaint_id": complaint.ID, "rating": 4})
+	s.expect(200, "PUT", "/api/admin/assign", admin, map[string]interface{}{"complaint_id": complaint.ID, "staff_id": f.staff.ID, "force": true})
+	s.expect(200, "PUT", "/api/staff/resolve/"+path, staff, nil)
+
+	s.expect(200, "POST", "/api/feedback", resident, map[string]interface{}{"complaint_id": complaint.ID, "rating": 4})
+	s.expect(400, "POST", "/api/feedback", resident, map[string]interface{}{"complaint_id": complaint.ID, "rating": 5})
+	if p := points(); p.TotalPoints-start.TotalPoints != 8 || p.TasksCompleted-start.TasksCompleted != 1 {
+		t.Errorf("after a 4-star rating points went %+v -> %+v, want +8 for 1 task", start, p)
+	}
+
+	// Reopening takes the points back, and the next resolution can be rated afresh
+	s.expect(200, "POST", "/api/complaints/"+path+"/reopen", resident, map[string]string{"reason": "still leaking"})
+	if p := points(); p.TotalPoints != start.TotalPoints {
+		t.Errorf("after the reopen total points = %d, want %d", p.TotalPoints, start.TotalPoints)
+	}
+	s.expect(200, "PUT", "/api/staff/resolve/"+path, staff, nil)
+	s.expect(200, "POST", "/api/feedback", resident, map[string]interface{}{"complaint_id": complaint.ID, "rating": 5})
+	if p := points(); p.TotalPoints-start.TotalPoints != 10 {
+		t.Errorf("after the second rating total points = %d, want %d", p.TotalPoints, start.TotalPoints+10)
+	}
+}
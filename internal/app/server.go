@@ -0,0 +1,67 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Run listens on the configured port and serves until ctx is cancelled
+func (a *App) Run(ctx context.Context) error {
+	port := a.Config.Server.Port
+	l, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return err
+	}
+	slog.Info("listening", "port", port)
+	return a.Serve(ctx, l)
+}
+
+// Serve answers requests on l and runs the background workers until ctx is cancelled. It then
+// shuts down gracefully: readiness starts failing, no new connections are accepted, WebSocket
+// clients are told to go away, in-flight requests get up to the configured shutdown timeout to finish and the
+// workers and background notification deliveries are waited for.
+func (a *App) Serve(ctx context.Context, l net.Listener) error {
+	server := &http.Server{Handler: a.Handler, ReadHeaderTimeout: 10 * time.Second}
+	// Shutdown does not wait for hijacked connections, so close the WebSockets explicitly
+	server.RegisterOnShutdown(a.Notifications.CloseConnections)
+
+	workers, stopWorkers := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// Evaluate escalation policies for stale complaints in the background
+		a.Escalations.Run(workers, a.Config.Scheduler.EscalationInterval)
+	}()
+	defer func() {
+		stopWorkers()
+		wg.Wait()
+		// Let emergency alerts raised by the last requests finish sending
+		a.Notifications.Wait()
+	}()
+
+	served := make(chan error, 1)
+	go func() { served <- server.Serve(l) }()
+
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down, draining requests")
+	a.draining.Store(true)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.Config.Server.ShutdownTimeout)
+	defer cancel()
+	err := server.Shutdown(shutdownCtx)
+	if serveErr := <-served; !errors.Is(serveErr, http.ErrServerClosed) && err == nil {
+		err = serveErr
+	}
+	return err
+}
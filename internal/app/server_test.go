@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/pkg/database"
+	"github.com/gin-gonic/gin"
+)
+
+func TestHealthProbes(t *testing.T) {
+	previous := database.DB
+	database.DB = nil
+	t.Cleanup(func() { database.DB = previous })
+
+	s := newAPIServer(t)
+	s.expect(200, "GET", "/healthz", "", nil)
+	// Without a database the instance is alive but must not receive traffic
+	s.expect(503, "GET", "/readyz", "", nil)
+}
+
+// TestServeDrainsRequestsOnShutdown cancels the server's context while a request is in flight
+// and checks that the request still completes and Serve returns cleanly
+func TestServeDrainsRequestsOnShutdown(t *testing.T) {
+	a := build(testConfig())
+	started, release := make(chan struct{}), make(chan struct{})
+	a.router.GET("/slow", func(c *gin.Context) {
+		close(started)
+		<-release
+		c.String(200, "done")
+	})
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := "http://" + l.Addr().String()
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- a.Serve(ctx, l) }()
+
+	type result struct {
+		body string
+		err  error
+	}
+	slow := make(chan result, 1)
+	go func() {
+		res, err := http.Get(base + "/slow")
+		if err != nil {
+			slow <- result{err: err}
+			return
+		}
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		slow <- result{string(body), err}
+	}()
+
+	<-started
+	cancel()
+	for !a.draining.Load() {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+
+	if r := <-slow; r.err != nil || r.body != "done" {
+		t.Errorf("in-flight request got %q, %v; want it to complete", r.body, r.err)
+	}
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("Serve returned %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after shutdown")
+	}
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	router := build(testConfig()).router
+	for _, path := range []string{"/healthz", "/api/me", "/no-such-route"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 from /metrics, got %d", w.Code)
+	}
+	for _, want := range []string{
+		`flashtrack_http_requests_total{method="GET",route="/healthz",status="200"}`,
+		`flashtrack_http_requests_total{method="GET",route="/api/me",status="401"}`,
+		`flashtrack_http_requests_total{method="GET",route="unmatched",status="404"}`,
+		`flashtrack_http_request_duration_seconds_count{method="GET",route="/healthz"}`,
+		`flashtrack_websocket_connections`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("expected %s in the metrics", want)
+		}
+	}
+}
+
+// TestRequestsAreTraced records spans in memory and checks that API requests continue the
+// caller's trace, probes are not traced and the access log names the trace
+func TestRequestsAreTraced(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+		provider.Shutdown(context.Background())
+	})
+	buf := captureLog(t)
+	router := build(testConfig()).router
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/healthz", nil))
+	if spans := exporter.GetSpans(); len(spans) != 0 {
+		t.Fatalf("expected probes not to be traced, got %d spans", len(spans))
+	}
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest("GET", "/api/me", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected one span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.SpanContext.TraceID().String() != traceID {
+		t.Errorf("expected the span to continue trace %s, got %s", traceID, span.SpanContext.TraceID())
+	}
+	attrs := map[string]string{}
+	for _, attr := range span.Attributes {
+		attrs[string(attr.Key)] = attr.Value.Emit()
+	}
+	if attrs["http.route"] != "/api/me" || attrs["http.response.status_code"] != "401" {
+		t.Errorf("unexpected span attributes %v", attrs)
+	}
+
+	buf.Reset()
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	if records := accessRecords(t, buf); len(records) != 1 || records[0]["trace_id"] != traceID {
+		t.Errorf("expected the access record to carry trace %s, got %v", traceID, records)
+	}
+}
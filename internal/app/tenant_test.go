@@ -0,0 +1,116 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+)
+
+// tenantTables are the tenant-owned tables compared before and after the requests
+var tenantTables = []string{
+	"societies", "users", "complaints", "categories", "locations", "feedbacks", "notifications",
+	"escalation_policies", "staff_leaves", "staff_profiles", "staff_categories", "staff_points",
+	"roles", "complaint_reopens", "complaint_escalations", "invites",
+}
+
+// TestNoEndpointCrossesSocieties signs in as each member of society A and calls every route
+// with the IDs of society B's rows. No response may contain B's data and B's rows must be
+// unchanged afterwards.
+func TestNoEndpointCrossesSocieties(t *testing.T) {
+	useTestDatabase(t)
+
+	nonce := fmt.Sprintf("xtenant%d", time.Now().UnixNano())
+	a := seedSociety(t, "a"+nonce[1:])
+	b := seedSociety(t, nonce)
+	before := snapshotSociety(t, b.society.ID)
+
+	ids := []uint{b.society.ID, b.admin.ID, b.staff.ID, b.resident.ID, b.category.ID, b.unit.ID,
+		b.complaint.ID, b.feedback.ID, b.notice.ID, b.policy.ID, b.leave.ID, b.role.ID}
+	router := build(testConfig()).router
+	param := regexp.MustCompile(`:[a-z_]+`)
+
+	for _, member := range []models.User{a.admin, a.staff, a.resident} {
+		token := tokenFor(t, member)
+
+		for _, route := range router.Routes() {
+			if strings.HasPrefix(route.Path, "/auth") {
+				continue
+			}
+			for _, id := range ids {
+				path := param.ReplaceAllString(route.Path, strconv.FormatUint(uint64(id), 10))
+				req := httptest.NewRequest(route.Method, path+"?"+crossTenantQuery(b), strings.NewReader(crossTenantBody(b)))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Authorization", "Bearer "+token)
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+
+				if strings.Contains(rec.Body.String(), nonce) {
+					t.Errorf("%s %s as %s returned society B's data: %s", route.Method, path, member.Role, truncate(rec.Body.String()))
+				}
+			}
+		}
+	}
+
+	if after := snapshotSociety(t, b.society.ID); after != before {
+		t.Errorf("society B's rows changed:\nbefore %s\nafter  %s", truncate(before), truncate(after))
+	}
+}
+
+// snapshotSociety serialises every tenant-owned row of the society
+func snapshotSociety(t *testing.T, societyID uint) string {
+	t.Helper()
+	snapshot := map[string][]map[string]interface{}{}
+	for _, table := range tenantTables {
+		var rows []map[string]interface{}
+		if err := database.ForSociety(societyID).Table(table).Order("id").Find(&rows).Error; err != nil {
+			t.Fatalf("snapshot %s: %v", table, err)
+		}
+		snapshot[table] = rows
+	}
+	out, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+// crossTenantQuery passes society B's IDs to every query parameter a handler might read
+func crossTenantQuery(b *societyFixture) string {
+	return fmt.Sprintf("complaint_id=%d&staff_id=%d&category_id=%d&location_id=%d&unit_id=%d&user_id=%d&society_id=%d",
+		b.complaint.ID, b.staff.ID, b.category.ID, b.unit.ID, b.unit.ID, b.resident.ID, b.society.ID)
+}
+
+// crossTenantBody references society B's rows from every body field a handler might read
+func crossTenantBody(b *societyFixture) string {
+	body, _ := json.Marshal(map[string]interface{}{
+		"complaint_id":    b.complaint.ID,
+		"complaint_ids":   []uint{b.complaint.ID},
+		"staff_id":        b.staff.ID,
+		"category_id":     b.category.ID,
+		"category_ids":    []uint{b.category.ID},
+		"location_id":     b.unit.ID,
+		"unit_id":         b.unit.ID,
+		"parent_id":       b.unit.ID,
+		"primary_id":      b.complaint.ID,
+		"duplicate_ids":   []uint{b.complaint.ID},
+		"notification_id": b.notice.ID,
+		"user_ids":        []uint{b.resident.ID},
+		"rating":          5,
+		"reason":          "cross-tenant",
+		"title":           "cross-tenant",
+		"description":     "cross-tenant",
+		"name":            "cross-tenant",
+		"status":          "resolved",
+		"priority":        "urgent",
+		"role":            "staff",
+	})
+	return string(body)
+}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestDeactivationLocksTheAccountOut deactivates a resident and checks neither their existing
+// session nor a new sign-in gets through until they are reactivated
+func TestDeactivationLocksTheAccountOut(t *testing.T) {
+	useTestDatabase(t)
+
+	marker := fmt.Sprintf("deactivate%d", time.Now().UnixNano())
+	f := seedSociety(t, marker)
+	s := newAPIServer(t)
+	admin, resident := tokenFor(t, f.admin), tokenFor(t, f.resident)
+	userPath := "/api/admin/users/" + strconv.Itoa(int(f.resident.ID))
+	login := map[string]string{"email": f.resident.Email, "password": marker}
+
+	s.expect(200, "GET", "/api/me", resident, nil)
+	s.expect(200, "POST", userPath+"/deactivate", admin, nil)
+	s.expect(403, "GET", "/api/me", resident, nil)
+	s.expect(403, "POST", "/auth/login", "", login)
+	s.expect(409, "POST", userPath+"/deactivate", admin, nil)
+
+	// Admins cannot lock themselves out
+	s.expect(403, "POST", "/api/admin/users/"+strconv.Itoa(int(f.admin.ID))+"/deactivate", admin, nil)
+
+	s.expect(200, "POST", userPath+"/reactivate", admin, nil)
+	s.expect(200, "GET", "/api/me", resident, nil)
+	s.expect(200, "POST", "/auth/login", "", login)
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"github.com/VinVorteX/flashtrack/config"
+	"github.com/VinVorteX/flashtrack/internal/controllers"
+	"github.com/VinVorteX/flashtrack/internal/repository"
+	"github.com/VinVorteX/flashtrack/internal/services"
+)
+
+// build creates the repositories and services once, hands them to the controllers that need
+// them and sets up the router. It expects database.DB to be connected before requests arrive.
+func build(cfg config.Config) *App {
+	complaintRepo := repository.NewComplaintRepository()
+	categoryRepo := repository.NewCategoryRepository()
+	feedbackRepo := repository.NewFeedbackRepository()
+	userRepo := repository.NewUserRepository()
+	societyRepo := repository.NewSocietyRepository()
+	tx := repository.NewTransactor()
+
+	mailer := services.NewMailer(cfg.Mail)
+	notifications := &services.NotificationService{
+		Notifications: repository.NewNotificationRepository(),
+		Users:         userRepo,
+		Channels: []services.NotificationChannel{
+			services.EmailChannel{Mailer: mailer},
+			services.PushChannel{Pusher: services.NewPusher(cfg.Push)},
+		},
+	}
+	invites := &services.InviteService{Invites: repository.NewInviteRepository(), Mailer: mailer, AppURL: cfg.AppURL}
+	roles := &services.RoleService{Roles: repository.NewRoleRepository(), Users: userRepo}
+	staff := &services.StaffService{
+		Staff:      repository.NewStaffRepository(),
+		Users:      userRepo,
+		Complaints: complaintRepo,
+		Categories: categoryRepo,
+		Societies:  societyRepo,
+		Roles:      roles,
+	}
+	plans := &services.PlanService{}
+	locations := &services.LocationService{}
+	analytics := &services.AnalyticsService{Roles: roles}
+	assignment := &services.AssignmentService{
+		Complaints:    complaintRepo,
+		Users:         userRepo,
+		Societies:     societyRepo,
+		Feedback:      feedbackRepo,
+		Cursors:       repository.NewAssignmentRepository(),
+		Notifications: notifications,
+		Staff:         staff,
+		Roles:         roles,
+	}
+	complaints := &services.ComplaintService{
+		Complaints:    complaintRepo,
+		Categories:    categoryRepo,
+		Feedback:      feedbackRepo,
+		Users:         userRepo,
+		Societies:     societyRepo,
+		Tx:            tx,
+		Notifications: notifications,
+		Assignment:    assignment,
+		Staff:         staff,
+		Locations:     locations,
+		Roles:         roles,
+	}
+	users := &services.UserService{
+		Users:      userRepo,
+		Audit:      repository.NewAuditRepository(),
+		Tx:         tx,
+		Assignment: assignment,
+		Complaints: complaints,
+		Invites:    invites,
+		Roles:      roles,
+		Plans:      plans,
+		Locations:  locations,
+	}
+	escalations := &services.EscalationService{
+		Escalations:   repository.NewEscalationRepository(),
+		Complaints:    complaintRepo,
+		Categories:    categoryRepo,
+		Users:         userRepo,
+		Notifications: notifications,
+		Assignment:    assignment,
+		Plans:         plans,
+	}
+
+	a := &App{
+		Config:        cfg,
+		Notifications: notifications,
+		Escalations:   escalations,
+		complaints:    &controllers.ComplaintController{Complaints: complaints},
+		feedback: &controllers.FeedbackController{Feedback: &services.FeedbackService{
+			Feedback:   feedbackRepo,
+			Complaints: complaintRepo,
+			Users:      userRepo,
+		}},
+		categories: &controllers.CategoryController{Categories: &services.CategoryService{Categories: categoryRepo}},
+		notifications: &controllers.NotificationController{
+			Notifications:  notifications,
+			AllowedOrigins: cfg.Server.WebSocketOrigins,
+		},
+		profiles: &controllers.ProfileController{Profiles: &services.ProfileService{Notifications: notifications}},
+		users:    &controllers.UserController{Users: users},
+		auth: &controllers.AuthController{Auth: &services.AuthService{
+			JWTSecret: cfg.Auth.JWTSecret,
+			TokenTTL:  cfg.Auth.TokenTTL,
+			Users:     users,
+			Plans:     plans,
+		}},
+		imports: &controllers.ImportController{
+			Imports: &services.ImportService{
+				Users:      userRepo,
+				Categories: categoryRepo,
+				Tx:         tx,
+				Accounts:   users,
+				Invites:    invites,
+				Roles:      roles,
+				Plans:      plans,
+				Locations:  locations,
+			},
+			Invites: invites,
+		},
+		platform:      &controllers.PlatformController{Platform: &services.PlatformService{Invites: invites, JWTSecret: cfg.Auth.JWTSecret}},
+		admin:         &controllers.AdminController{Staff: staff},
+		staffProfiles: &controllers.StaffProfileController{Staff: staff},
+		locations:     &controllers.LocationController{Locations: locations},
+		analytics:     &controllers.AnalyticsController{Analytics: analytics},
+		exports:       &controllers.ExportController{Analytics: analytics},
+		plans:         &controllers.PlanController{Plans: plans},
+		roles:         &controllers.RoleController{Roles: roles},
+		escalations:   &controllers.EscalationController{Escalations: escalations},
+	}
+	a.router = setupRouter(a)
+	a.Handler = a.router
+	return a
+}
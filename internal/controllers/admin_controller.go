@@ -1,40 +1,97 @@
 package controllers
 
 import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/apierror"
+	"github.com/VinVorteX/flashtrack/internal/dto"
 	"github.com/VinVorteX/flashtrack/internal/models"
 	"github.com/VinVorteX/flashtrack/internal/services"
-	"github.com/VinVorteX/flashtrack/pkg/database"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
-var notifService = &services.NotificationService{}
+// AdminController serves the society's settings, statistics and staff list
+type AdminController struct {
+	Staff *services.StaffService
+}
 
-func GetStaffMembers(c *gin.Context) {
+// GetStaffMembers lists staff in the user's society with their skills and current availability.
+// Supports ?available=true to only return staff who can take work now and ?category_id= to filter by skill.
+func (ac *AdminController) GetStaffMembers(c *gin.Context) {
 	user := c.MustGet("user").(*models.User)
 
-	var staff []models.User
-	if err := database.DB.Where("role = ? AND society_id = ?", "staff", user.SocietyID).Select("id", "name", "email", "role").Find(&staff).Error; err != nil {
-		c.JSON(500, gin.H{"error": "failed to fetch staff members"})
-		return
+	var categoryID *uint
+	if categoryParam := c.Query("category_id"); categoryParam != "" {
+		id, err := strconv.ParseUint(categoryParam, 10, 32)
+		if err != nil {
+			apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "invalid category_id"))
+			return
+		}
+		categoryID = new(uint)
+		*categoryID = uint(id)
 	}
 
-	c.JSON(200, staff)
-}
+	staff, err := ac.Staff.Members(c.Request.Context(), user.SocietyID, categoryID)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch staff members"))
+		return
+	}
 
-func GetSocietyStats(c *gin.Context) {
-	user := c.MustGet("user").(*models.User)
+	ids := make([]uint, len(staff))
+	for i, s := range staff {
+		ids[i] = s.ID
+	}
 
-	var staffCount, residentCount int64
+	availability, err := ac.Staff.Availability(c.Request.Context(), user.SocietyID, ids, time.Now())
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch staff availability"))
+		return
+	}
 
-	// Count staff members
-	if err := database.DB.Model(&models.User{}).Where("role = ? AND society_id = ?", "staff", user.SocietyID).Count(&staffCount).Error; err != nil {
-		c.JSON(500, gin.H{"error": "failed to count staff"})
+	skills, err := ac.Staff.GetCategoryIDs(c.Request.Context(), ids)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch staff skills"))
 		return
 	}
 
-	// Count residents (users with role 'user')
-	if err := database.DB.Model(&models.User{}).Where("role = ? AND society_id = ?", "user", user.SocietyID).Count(&residentCount).Error; err != nil {
-		c.JSON(500, gin.H{"error": "failed to count residents"})
+	type StaffResponse struct {
+		dto.UserResponse
+		services.StaffAvailability
+		Available   bool   `json:"available"`
+		CategoryIDs []uint `json:"category_ids"`
+	}
+
+	onlyAvailable := c.Query("available") == "true"
+	response := []StaffResponse{}
+	for _, s := range staff {
+		a := availability[s.ID]
+		if onlyAvailable && !a.Available() {
+			continue
+		}
+		categoryIDs := skills[s.ID]
+		if categoryIDs == nil {
+			categoryIDs = []uint{}
+		}
+		response = append(response, StaffResponse{
+			UserResponse:      dto.NewUserResponse(&s),
+			StaffAvailability: a,
+			Available:         a.Available(),
+			CategoryIDs:       categoryIDs,
+		})
+	}
+
+	c.JSON(200, response)
+}
+
+func (ac *AdminController) GetSocietyStats(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	staffCount, residentCount, err := ac.Staff.Headcount(c.Request.Context(), user.SocietyID)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to count users"))
 		return
 	}
 
@@ -45,75 +102,119 @@ func GetSocietyStats(c *gin.Context) {
 	})
 }
 
-func AssignStaff(c *gin.Context) {
+// GetSocietySettings returns the admin-configurable settings of the admin's society
+func (ac *AdminController) GetSocietySettings(c *gin.Context) {
 	user := c.MustGet("user").(*models.User)
+	db := c.MustGet("db").(*gorm.DB)
 
-	// Only admins can assign staff
-	if user.Role != "admin" {
-		c.JSON(403, gin.H{"error": "only admins can assign staff"})
+	var society models.Society
+	if err := db.First(&society, user.SocietyID).Error; err != nil {
+		apierror.Write(c, apierror.New(apierror.CodeNotFound, "society not found"))
 		return
 	}
 
+	reopenWindowHours := society.ReopenWindowHours
+	if reopenWindowHours == 0 {
+		reopenWindowHours = services.DefaultReopenWindowHours
+	}
+
+	c.JSON(200, gin.H{
+		"assignment_strategy": society.AssignmentStrategy,
+		"reopen_window_hours": reopenWindowHours,
+		"timezone":            society.Location().String(),
+	})
+}
+
+// UpdateSocietySettings updates the admin-configurable settings of the admin's society
+func (ac *AdminController) UpdateSocietySettings(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+	db := c.MustGet("db").(*gorm.DB)
+
 	var body struct {
-		ComplaintID uint `json:"complaint_id" binding:"required"`
-		StaffID     uint `json:"staff_id" binding:"required"`
+		AssignmentStrategy *string `json:"assignment_strategy"`
+		ReopenWindowHours  *int    `json:"reopen_window_hours"`
+		Timezone           *string `json:"timezone"`
 	}
 
 	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(400, gin.H{"error": "invalid request: " + err.Error()})
+		apierror.Write(c, apierror.Validation(err))
 		return
 	}
 
-	// Verify complaint exists and belongs to same society
-	var complaint models.Complaint
-	if err := database.DB.First(&complaint, body.ComplaintID).Error; err != nil {
-		c.JSON(404, gin.H{"error": "complaint not found"})
-		return
+	updates := map[string]interface{}{}
+	if body.AssignmentStrategy != nil {
+		if !services.IsValidAssignmentStrategy(*body.AssignmentStrategy) {
+			apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "unknown assignment strategy"))
+			return
+		}
+		updates["assignment_strategy"] = *body.AssignmentStrategy
+	}
+	if body.ReopenWindowHours != nil {
+		if *body.ReopenWindowHours < 0 {
+			apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "reopen_window_hours cannot be negative"))
+			return
+		}
+		updates["reopen_window_hours"] = *body.ReopenWindowHours
+	}
+	if body.Timezone != nil {
+		// LoadLocation accepts "" and "Local" too; only explicit IANA names are stored
+		if _, err := time.LoadLocation(*body.Timezone); err != nil || *body.Timezone == "" || *body.Timezone == "Local" {
+			apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "timezone must be an IANA time zone such as Asia/Kolkata"))
+			return
+		}
+		updates["timezone"] = *body.Timezone
 	}
 
-	if complaint.SocietyID != user.SocietyID {
-		c.JSON(403, gin.H{"error": "cannot assign staff to complaints from other societies"})
-		return
+	if len(updates) > 0 {
+		if err := db.Model(&models.Society{}).Where("id = ?", user.SocietyID).Updates(updates).Error; err != nil {
+			apierror.Write(c, apierror.Internal(err, "failed to update settings"))
+			return
+		}
 	}
 
-	// Verify staff exists, is actually staff role, and belongs to same society
-	var staff models.User
-	if err := database.DB.First(&staff, body.StaffID).Error; err != nil {
-		c.JSON(404, gin.H{"error": "staff not found"})
+	ac.GetSocietySettings(c)
+}
+
+// SetStaffCategories replaces the categories a staff member handles for skill-based assignment
+func (ac *AdminController) SetStaffCategories(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	staffID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "invalid staff ID"))
 		return
 	}
 
-	if staff.Role != "staff" {
-		c.JSON(400, gin.H{"error": "selected user is not a staff member"})
-		return
+	var body struct {
+		CategoryIDs []uint `json:"category_ids"`
 	}
 
-	if staff.SocietyID != user.SocietyID {
-		c.JSON(403, gin.H{"error": "cannot assign staff from other societies"})
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
 		return
 	}
 
-	// Update complaint
-	complaint.StaffID = &body.StaffID
-	complaint.Status = "in-progress"
-
-	if err := database.DB.Save(&complaint).Error; err != nil {
-		c.JSON(500, gin.H{"error": "failed to assign staff"})
+	staff, err := ac.Staff.FindMember(c.Request.Context(), user.SocietyID, uint(staffID))
+	if errors.Is(err, services.ErrStaffNotFound) || (err == nil && !staff.IsActive()) {
+		apierror.Write(c, apierror.New(apierror.CodeNotFound, "staff not found"))
+		return
+	}
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch staff"))
 		return
 	}
 
-	// Send notification to staff
-	if err := notifService.NotifyStaffAssignment(body.StaffID, &complaint); err != nil {
-		// Log error but don't fail the request
-		c.JSON(200, gin.H{
-			"complaint": complaint,
-			"message":   "staff assigned successfully, but notification failed",
-		})
+	if err := ac.Staff.SetCategories(c.Request.Context(), user.SocietyID, staff.ID, body.CategoryIDs); err != nil {
+		if errors.Is(err, services.ErrCategoryNotInSociety) {
+			apierror.Write(c, apierror.Wrap(apierror.CodeInvalidRequest, err))
+			return
+		}
+		apierror.Write(c, apierror.Internal(err, "failed to update staff categories"))
 		return
 	}
 
 	c.JSON(200, gin.H{
-		"complaint": complaint,
-		"message":   "staff assigned and notified successfully",
+		"staff_id":     staff.ID,
+		"category_ids": body.CategoryIDs,
 	})
 }
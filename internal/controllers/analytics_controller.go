@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/apierror"
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// AnalyticsController serves the complaint metrics of the admin's society
+type AnalyticsController struct {
+	Analytics *services.AnalyticsService
+}
+
+// parseDateParam accepts RFC3339 timestamps or YYYY-MM-DD dates
+func parseDateParam(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	return t, true, err
+}
+
+// GetAnalytics returns complaint volume and resolution metrics for the admin's society.
+// Query params: from, to (RFC3339 or YYYY-MM-DD, default last 30 days) and granularity (day, week, month).
+func (ac *AnalyticsController) GetAnalytics(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+
+	if value := c.Query("from"); value != "" {
+		parsed, _, err := parseDateParam(value)
+		if err != nil {
+			apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "invalid from date"))
+			return
+		}
+		from = parsed
+	}
+
+	if value := c.Query("to"); value != "" {
+		parsed, dateOnly, err := parseDateParam(value)
+		if err != nil {
+			apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "invalid to date"))
+			return
+		}
+		// A plain date includes the whole day
+		if dateOnly {
+			parsed = parsed.AddDate(0, 0, 1)
+		}
+		to = parsed
+	}
+
+	if !from.Before(to) {
+		apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "from must be before to"))
+		return
+	}
+
+	granularity := c.DefaultQuery("granularity", "day")
+	if !services.IsValidGranularity(granularity) {
+		apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "granularity must be day, week or month"))
+		return
+	}
+
+	report, err := ac.Analytics.Report(c.Request.Context(), user.SocietyID, from, to, granularity)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to compute analytics"))
+		return
+	}
+
+	c.JSON(200, report)
+}
@@ -1,39 +1,64 @@
 package controllers
 
 import (
+    "errors"
+
+    "github.com/VinVorteX/flashtrack/internal/apierror"
+    "github.com/VinVorteX/flashtrack/internal/dto"
     "github.com/VinVorteX/flashtrack/internal/models"
     "github.com/VinVorteX/flashtrack/internal/services"
     "github.com/VinVorteX/flashtrack/pkg/database"
     "github.com/gin-gonic/gin"
 )
 
-func Register(c *gin.Context) {
-    var body models.User
+// AuthController signs users up and in
+type AuthController struct {
+    Auth *services.AuthService
+}
+
+func (ac *AuthController) Register(c *gin.Context) {
+    var body dto.RegisterRequest
 
     if err := c.ShouldBindJSON(&body); err != nil {
-        c.JSON(400, gin.H{"error": "invalid request"})
+        apierror.Write(c, apierror.Validation(err))
         return
     }
 
-    if err := services.Register(body); err != nil {
-        c.JSON(500, gin.H{"error": err.Error()})
+    if err := ac.Auth.Register(c.Request.Context(), body.ToModel()); err != nil {
+        if planError(c, err) {
+            return
+        }
+        apierror.Write(c, apierror.Wrap(apierror.CodeUnprocessable, err))
         return
     }
 
     c.JSON(200, gin.H{"message": "registered"})
 }
 
-func Login(c *gin.Context) {
+func (ac *AuthController) Login(c *gin.Context) {
     var body struct {
-        Email    string
-        Password string
+        Email    string `json:"email" binding:"required"`
+        Password string `json:"password" binding:"required"`
     }
 
-    c.BindJSON(&body)
+    if err := c.ShouldBindJSON(&body); err != nil {
+        apierror.Write(c, apierror.Validation(err))
+        return
+    }
 
-    token, user, err := services.Login(body.Email, body.Password)
-    if err != nil {
-        c.JSON(401, gin.H{"error": err.Error()})
+    token, user, err := ac.Auth.Login(body.Email, body.Password)
+    switch {
+    case errors.Is(err, services.ErrInvalidCredentials):
+        apierror.Write(c, apierror.New(apierror.CodeUnauthorized, err.Error()))
+        return
+    case errors.Is(err, services.ErrAccountDeactivated):
+        apierror.Write(c, apierror.New(apierror.CodeAccountDeactivated, err.Error()))
+        return
+    case errors.Is(err, services.ErrSocietySuspended):
+        apierror.Write(c, apierror.New(apierror.CodeSocietySuspended, err.Error()))
+        return
+    case err != nil:
+        apierror.Write(c, apierror.Internal(err, "failed to sign in"))
         return
     }
 
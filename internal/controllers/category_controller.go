@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/VinVorteX/flashtrack/internal/apierror"
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func categoryResponse(category models.Category) gin.H {
+	defaultPriority := category.DefaultPriority
+	if defaultPriority == "" {
+		defaultPriority = services.PriorityMedium
+	}
+
+	return gin.H{
+		"id":               category.ID,
+		"name":             category.Name,
+		"society_id":       category.SocietyID,
+		"sla_hours":        category.SLAHours,
+		"default_priority": defaultPriority,
+	}
+}
+
+// CategoryController serves the complaint categories and their SLA settings
+type CategoryController struct {
+	Categories *services.CategoryService
+}
+
+// GetCategories lists the categories available to the user's society, including shared ones
+func (cc *CategoryController) GetCategories(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	categories, err := cc.Categories.List(c.Request.Context(), user.SocietyID)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch categories"))
+		return
+	}
+
+	response := []gin.H{}
+	for _, category := range categories {
+		response = append(response, categoryResponse(category))
+	}
+
+	c.JSON(200, response)
+}
+
+// UpdateCategory sets the SLA target and default priority of one of the admin's society categories
+func (cc *CategoryController) UpdateCategory(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "invalid category ID"))
+		return
+	}
+
+	var body struct {
+		SLAHours        *int    `json:"sla_hours" binding:"omitempty,min=0"`
+		DefaultPriority *string `json:"default_priority" binding:"omitempty,oneof=low medium high urgent"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	// Shared categories are not owned by any one society, so only society categories can change
+	category, err := cc.Categories.Update(c.Request.Context(), user.SocietyID, uint(categoryID), services.CategoryChanges{
+		SLAHours:        body.SLAHours,
+		DefaultPriority: body.DefaultPriority,
+	})
+	if errors.Is(err, services.ErrCategoryNotFound) {
+		apierror.Write(c, apierror.Wrap(apierror.CodeNotFound, err))
+		return
+	}
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to update category"))
+		return
+	}
+
+	c.JSON(200, categoryResponse(*category))
+}
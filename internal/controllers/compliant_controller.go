@@ -1,116 +1,158 @@
 package controllers
 
 import (
+	"errors"
+	"strconv"
+
+	"github.com/VinVorteX/flashtrack/internal/apierror"
+	"github.com/VinVorteX/flashtrack/internal/dto"
 	"github.com/VinVorteX/flashtrack/internal/models"
-	"github.com/VinVorteX/flashtrack/pkg/database"
+	"github.com/VinVorteX/flashtrack/internal/repository"
+	"github.com/VinVorteX/flashtrack/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
-// GetComplaints retrieves all complaints for the user's society with related data
-func GetComplaints(c *gin.Context) {
-	user := c.MustGet("user").(*models.User)
+// ComplaintController serves the complaint endpoints of residents, staff and admins
+type ComplaintController struct {
+	Complaints *services.ComplaintService
+}
 
-	var complaints []models.Complaint
-	query := database.DB.Where("society_id = ?", user.SocietyID)
+// complaintError writes the response for an error returned by the complaint service
+func complaintError(c *gin.Context, err error, action string) {
+	var unavailable *services.StaffUnavailableError
+	switch {
+	case errors.As(err, &unavailable):
+		apierror.Write(c, apierror.New(apierror.CodeConflict, err.Error()).With("availability", unavailable.Availability))
+	case errors.Is(err, services.ErrComplaintNotFound), errors.Is(err, services.ErrStaffNotFound):
+		apierror.Write(c, apierror.Wrap(apierror.CodeNotFound, err))
+	case errors.Is(err, services.ErrNotComplaintOwner), errors.Is(err, services.ErrNotAssignee):
+		apierror.Write(c, apierror.Wrap(apierror.CodeForbidden, err))
+	case errors.Is(err, services.ErrMergedComplaint),
+		errors.Is(err, services.ErrNotResolved),
+		errors.Is(err, services.ErrReopenWindowClosed),
+		errors.Is(err, services.ErrNotPending),
+		errors.Is(err, services.ErrNotCancellable),
+		errors.Is(err, services.ErrHasDuplicates),
+		errors.Is(err, services.ErrInvalidMerge),
+		errors.Is(err, services.ErrCategoryNotFound),
+		errors.Is(err, services.ErrCategoryNotInSociety),
+		errors.Is(err, services.ErrNotStaffMember),
+		errors.Is(err, services.ErrStaffDeactivated),
+		errors.Is(err, services.ErrInvalidLocation),
+		errors.Is(err, services.ErrNotComplaintLocation),
+		errors.Is(err, services.ErrNotOwnUnit):
+		apierror.Write(c, apierror.Wrap(apierror.CodeInvalidRequest, err))
+	default:
+		apierror.Write(c, apierror.Internal(err, "failed to "+action))
+	}
+}
 
-	// Filter based on role
-	switch user.Role {
-	case "user":
-		// Users see only their own complaints
-		query = query.Where("resident_id = ?", user.ID)
-	case "staff":
-		// Staff see complaints assigned to them
-		query = query.Where("staff_id = ?", user.ID)
-	case "admin":
-		// Admins see all complaints in their society
-		// No additional filter needed
+// complaintIDParam parses the :id route parameter, writing a 400 when it is invalid
+func complaintIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "invalid complaint ID"))
+		return 0, false
 	}
+	return uint(id), true
+}
 
-	if err := query.Order("created_at DESC").Find(&complaints).Error; err != nil {
-		c.JSON(500, gin.H{"error": "failed to fetch complaints"})
-		return
+// listFilter turns the request's query parameters into the complaint filter shared by the
+// listing and export endpoints. It writes the error response itself on failure.
+func (cc *ComplaintController) listFilter(c *gin.Context, user *models.User) (repository.ComplaintFilter, bool) {
+	opts := services.ComplaintListOptions{
+		IncludeDuplicates: c.Query("include_duplicates") == "true",
+		// ?sort=priority puts emergencies and urgent complaints first
+		SortByPriority: c.Query("sort") == "priority",
 	}
 
-	// Prepare response with resident and staff details
-	type ComplaintResponse struct {
-		models.Complaint
-		ResidentName string  `json:"resident_name"`
-		StaffName    *string `json:"staff_name,omitempty"`
-		CategoryName string  `json:"category_name"`
+	// ?location_id= matches the location and everything beneath it
+	if locationParam := c.Query("location_id"); locationParam != "" {
+		locationID, err := strconv.ParseUint(locationParam, 10, 32)
+		if err != nil {
+			apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "invalid location_id"))
+			return repository.ComplaintFilter{}, false
+		}
+		id := uint(locationID)
+		opts.LocationID = &id
 	}
 
-	var response []ComplaintResponse
-	// Ensure we always return an array, never null
-	if len(complaints) == 0 {
-		c.JSON(200, []ComplaintResponse{})
-		return
+	permissions := c.MustGet("permissions").(services.PermissionSet)
+	filter, err := cc.Complaints.Filter(c.Request.Context(), user, permissions, opts)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to resolve location"))
+		return filter, false
 	}
-	for _, complaint := range complaints {
-		// Get resident name
-		var resident models.User
-		database.DB.Select("name").First(&resident, complaint.ResidentID)
+	return filter, true
+}
 
-		// Get staff name if assigned
-		var staffName *string
-		if complaint.StaffID != nil {
-			var staff models.User
-			if err := database.DB.Select("name").First(&staff, *complaint.StaffID).Error; err == nil {
-				staffName = &staff.Name
-			}
-		}
+// findComplaint loads the complaint from the :id param, scoped to the user's society
+func (cc *ComplaintController) findComplaint(c *gin.Context, user *models.User) (*models.Complaint, bool) {
+	complaintID, ok := complaintIDParam(c)
+	if !ok {
+		return nil, false
+	}
 
-		// Get category name
-		var category models.Category
-		categoryName := "General"
-		if err := database.DB.Select("name").First(&category, complaint.CategoryID).Error; err == nil {
-			categoryName = category.Name
-		}
+	complaint, err := cc.Complaints.Find(c.Request.Context(), user.SocietyID, complaintID)
+	if err != nil {
+		complaintError(c, err, "fetch complaint")
+		return nil, false
+	}
+	return complaint, true
+}
 
-		response = append(response, ComplaintResponse{
-			Complaint:    complaint,
-			ResidentName: resident.Name,
-			StaffName:    staffName,
-			CategoryName: categoryName,
-		})
+// GetComplaints retrieves all complaints for the user's society with related data
+func (cc *ComplaintController) GetComplaints(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	filter, ok := cc.listFilter(c, user)
+	if !ok {
+		return
+	}
+
+	complaints, err := cc.Complaints.List(c.Request.Context(), filter)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch complaints"))
+		return
 	}
 
 	// Return array directly for frontend compatibility
-	c.JSON(200, response)
+	c.JSON(200, complaints)
 }
 
-func CreateComplaint(c *gin.Context) {
+func (cc *ComplaintController) CreateComplaint(c *gin.Context) {
 	var body struct {
 		Title       string `json:"title" binding:"required"`
 		Description string `json:"description" binding:"required"`
 		CategoryID  uint   `json:"category_id" binding:"required"`
+		Priority    string `json:"priority" binding:"omitempty,oneof=low medium high urgent"`
+		IsEmergency bool   `json:"is_emergency"`
+		LocationID  *uint  `json:"location_id"` // unit or common area, defaults to the resident's unit
 	}
 
 	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(400, gin.H{"error": "invalid request: " + err.Error()})
+		apierror.Write(c, apierror.Validation(err))
 		return
 	}
 
 	user := c.MustGet("user").(*models.User)
 
-	complaint := models.Complaint{
+	complaint, category, staff, err := cc.Complaints.Create(c.Request.Context(), user, services.NewComplaint{
 		Title:       body.Title,
 		Description: body.Description,
-		Status:      "pending",
-		ResidentID:  user.ID,
-		SocietyID:   user.SocietyID,
 		CategoryID:  body.CategoryID,
-	}
-
-	if err := database.DB.Create(&complaint).Error; err != nil {
-		c.JSON(500, gin.H{"error": "failed to create complaint"})
+		Priority:    body.Priority,
+		IsEmergency: body.IsEmergency,
+		LocationID:  body.LocationID,
+	})
+	if err != nil {
+		complaintError(c, err, "create complaint")
 		return
 	}
 
-	// Get category name
-	var category models.Category
-	categoryName := "General"
-	if err := database.DB.Select("name").First(&category, complaint.CategoryID).Error; err == nil {
-		categoryName = category.Name
+	var staffName *string
+	if staff != nil {
+		staffName = &staff.Name
 	}
 
 	// Return complaint with additional details
@@ -119,14 +161,195 @@ func CreateComplaint(c *gin.Context) {
 		"title":         complaint.Title,
 		"description":   complaint.Description,
 		"status":        complaint.Status,
+		"priority":      complaint.Priority,
+		"is_emergency":  complaint.IsEmergency,
+		"due_at":        complaint.DueAt,
 		"resident_id":   complaint.ResidentID,
 		"resident_name": user.Name,
 		"staff_id":      complaint.StaffID,
-		"staff_name":    nil,
+		"staff_name":    staffName,
 		"society_id":    complaint.SocietyID,
 		"category_id":   complaint.CategoryID,
-		"category_name": categoryName,
+		"category_name": category.Name,
+		"location_id":   complaint.LocationID,
 		"created_at":    complaint.CreatedAt,
 		"updated_at":    complaint.UpdatedAt,
 	})
 }
+
+// ReopenComplaint lets a resident send a resolved complaint back to staff within the reopen window
+func (cc *ComplaintController) ReopenComplaint(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	complaintID, ok := complaintIDParam(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	complaint, err := cc.Complaints.Find(c.Request.Context(), user.SocietyID, complaintID)
+	if err != nil {
+		complaintError(c, err, "fetch complaint")
+		return
+	}
+
+	reopen, err := cc.Complaints.Reopen(c.Request.Context(), complaint, user, body.Reason)
+	if err != nil {
+		complaintError(c, err, "reopen complaint")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"message":   "complaint reopened",
+		"complaint": dto.NewComplaintResponse(complaint),
+		"reopen":    reopen,
+	})
+}
+
+// UpdateComplaint lets a resident fix the title, description or category of a pending complaint
+func (cc *ComplaintController) UpdateComplaint(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	var body struct {
+		Title       *string `json:"title" binding:"omitempty,min=1"`
+		Description *string `json:"description" binding:"omitempty,min=1"`
+		CategoryID  *uint   `json:"category_id"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	complaint, ok := cc.findComplaint(c, user)
+	if !ok {
+		return
+	}
+
+	if err := cc.Complaints.Edit(c.Request.Context(), complaint, user, body.Title, body.Description, body.CategoryID); err != nil {
+		complaintError(c, err, "update complaint")
+		return
+	}
+
+	c.JSON(200, dto.NewComplaintResponse(complaint))
+}
+
+// CancelComplaint lets a resident withdraw an open complaint
+func (cc *ComplaintController) CancelComplaint(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	complaint, ok := cc.findComplaint(c, user)
+	if !ok {
+		return
+	}
+
+	if err := cc.Complaints.Cancel(c.Request.Context(), complaint, user); err != nil {
+		complaintError(c, err, "withdraw complaint")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"message":   "complaint withdrawn",
+		"complaint": dto.NewComplaintResponse(complaint),
+	})
+}
+
+// MergeComplaints links duplicate reports to a primary complaint in the admin's society
+func (cc *ComplaintController) MergeComplaints(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	var body struct {
+		PrimaryID    uint   `json:"primary_id" binding:"required"`
+		DuplicateIDs []uint `json:"duplicate_ids" binding:"required,min=1"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	primary, duplicates, err := cc.Complaints.Merge(c.Request.Context(), user.SocietyID, body.PrimaryID, body.DuplicateIDs)
+	if err != nil {
+		complaintError(c, err, "merge complaints")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"message":    "complaints merged",
+		"primary":    dto.NewComplaintResponse(primary),
+		"duplicates": dto.NewComplaintResponses(duplicates),
+	})
+}
+
+// UpdateComplaintPriority lets an admin override a complaint's priority or emergency flag.
+// The SLA deadline is recalculated from the original creation time.
+func (cc *ComplaintController) UpdateComplaintPriority(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	var body struct {
+		Priority    string `json:"priority" binding:"required,oneof=low medium high urgent"`
+		IsEmergency *bool  `json:"is_emergency"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	complaint, ok := cc.findComplaint(c, user)
+	if !ok {
+		return
+	}
+
+	if err := cc.Complaints.SetPriority(c.Request.Context(), complaint, body.Priority, body.IsEmergency); err != nil {
+		complaintError(c, err, "update priority")
+		return
+	}
+
+	c.JSON(200, dto.NewComplaintResponse(complaint))
+}
+
+// AssignStaff hands a complaint to a staff member of the admin's society and notifies them.
+// Off-shift or overloaded staff are refused unless force is set.
+func (cc *ComplaintController) AssignStaff(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	var body struct {
+		ComplaintID uint `json:"complaint_id" binding:"required"`
+		StaffID     uint `json:"staff_id" binding:"required"`
+		Force       bool `json:"force"` // assign even if the staff member is off shift or at capacity
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	complaint, warning, notified, err := cc.Complaints.Assign(c.Request.Context(), user, body.ComplaintID, body.StaffID, body.Force)
+	if err != nil {
+		complaintError(c, err, "assign staff")
+		return
+	}
+
+	response := gin.H{
+		"complaint": dto.NewComplaintResponse(complaint),
+		"message":   "staff assigned and notified successfully",
+	}
+	if warning != "" {
+		response["warning"] = warning
+	}
+	if !notified {
+		// The assignment stands even when the notification could not be sent
+		response["message"] = "staff assigned successfully, but notification failed"
+	}
+
+	c.JSON(200, response)
+}
@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/VinVorteX/flashtrack/internal/apierror"
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// EscalationController serves the society's escalation policies and the escalations they raised
+type EscalationController struct {
+	Escalations *services.EscalationService
+}
+
+// GetEscalationPolicies lists the escalation policies of the admin's society
+func (ec *EscalationController) GetEscalationPolicies(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	policies, err := ec.Escalations.Policies(c.Request.Context(), user.SocietyID)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch escalation policies"))
+		return
+	}
+
+	c.JSON(200, policies)
+}
+
+// CreateEscalationPolicy adds an escalation policy for the admin's society
+func (ec *EscalationController) CreateEscalationPolicy(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	var body struct {
+		CategoryID *uint  `json:"category_id"`
+		Trigger    string `json:"trigger" binding:"required"`
+		AfterHours int    `json:"after_hours" binding:"required"`
+		Level      int    `json:"level" binding:"required"`
+		Action     string `json:"action" binding:"required"`
+		TargetRole string `json:"target_role"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	policy := models.EscalationPolicy{
+		SocietyID:  user.SocietyID,
+		CategoryID: body.CategoryID,
+		Trigger:    body.Trigger,
+		AfterHours: body.AfterHours,
+		Level:      body.Level,
+		Action:     body.Action,
+		TargetRole: body.TargetRole,
+	}
+
+	if err := ec.Escalations.ValidatePolicy(&policy); err != nil {
+		apierror.Write(c, apierror.Wrap(apierror.CodeInvalidRequest, err))
+		return
+	}
+
+	if err := ec.Escalations.CreatePolicy(c.Request.Context(), &policy); err != nil {
+		if errors.Is(err, services.ErrCategoryNotInSociety) {
+			apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "category not found"))
+			return
+		}
+		apierror.Write(c, apierror.Internal(err, "failed to create escalation policy"))
+		return
+	}
+
+	c.JSON(200, policy)
+}
+
+// DeleteEscalationPolicy removes an escalation policy from the admin's society
+func (ec *EscalationController) DeleteEscalationPolicy(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	policyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "invalid policy ID"))
+		return
+	}
+
+	if err := ec.Escalations.DeletePolicy(c.Request.Context(), user.SocietyID, uint(policyID)); err != nil {
+		if errors.Is(err, services.ErrPolicyNotFound) {
+			apierror.Write(c, apierror.Wrap(apierror.CodeNotFound, err))
+			return
+		}
+		apierror.Write(c, apierror.Internal(err, "failed to delete escalation policy"))
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "escalation policy deleted"})
+}
+
+// GetComplaintEscalations returns the escalation history of a complaint in the admin's society
+func (ec *EscalationController) GetComplaintEscalations(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	complaintID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "invalid complaint ID"))
+		return
+	}
+
+	complaint, escalations, err := ec.Escalations.History(c.Request.Context(), user.SocietyID, uint(complaintID))
+	if err != nil {
+		if errors.Is(err, services.ErrComplaintNotFound) {
+			apierror.Write(c, apierror.Wrap(apierror.CodeNotFound, err))
+			return
+		}
+		apierror.Write(c, apierror.Internal(err, "failed to fetch escalations"))
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"complaint_id":     complaint.ID,
+		"escalation_level": complaint.EscalationLevel,
+		"escalated_at":     complaint.EscalatedAt,
+		"escalations":      escalations,
+	})
+}
@@ -0,0 +1,432 @@
+package controllers
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/apierror"
+	"github.com/VinVorteX/flashtrack/internal/export"
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/repository"
+	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// pdfTableRows caps the detail table of PDF summaries; the counts still cover every row
+const pdfTableRows = 100
+
+type complaintExportRow struct {
+	ID           uint
+	Title        string
+	Status       string
+	Priority     string
+	IsEmergency  bool
+	CategoryName *string
+	ResidentName *string
+	StaffName    *string
+	LocationName *string
+	ReopenCount  int
+	CreatedAt    time.Time
+	AssignedAt   *time.Time
+	DueAt        *time.Time
+	ResolvedAt   *time.Time
+}
+
+var complaintExportHeader = []string{
+	"ID", "Title", "Status", "Priority", "Emergency", "Category", "Resident", "Staff",
+	"Location", "Reopens", "Created At", "Assigned At", "Due At", "Resolved At",
+}
+
+func (r complaintExportRow) values() []string {
+	return []string{
+		strconv.FormatUint(uint64(r.ID), 10), r.Title, r.Status, r.Priority, strconv.FormatBool(r.IsEmergency),
+		deref(r.CategoryName), deref(r.ResidentName), deref(r.StaffName), deref(r.LocationName),
+		strconv.Itoa(r.ReopenCount), formatTime(&r.CreatedAt), formatTime(r.AssignedAt), formatTime(r.DueAt), formatTime(r.ResolvedAt),
+	}
+}
+
+type feedbackExportRow struct {
+	ID             uint
+	ComplaintID    uint
+	ComplaintTitle *string
+	ResidentName   *string
+	StaffName      *string
+	Rating         int
+	Comment        string
+	Points         int
+	Reversed       bool
+	CreatedAt      time.Time
+}
+
+var feedbackExportHeader = []string{
+	"ID", "Complaint ID", "Complaint", "Resident", "Staff", "Rating", "Comment", "Points", "Reversed", "Created At",
+}
+
+func (r feedbackExportRow) values() []string {
+	return []string{
+		strconv.FormatUint(uint64(r.ID), 10), strconv.FormatUint(uint64(r.ComplaintID), 10), deref(r.ComplaintTitle),
+		deref(r.ResidentName), deref(r.StaffName), strconv.Itoa(r.Rating), r.Comment, strconv.Itoa(r.Points),
+		strconv.FormatBool(r.Reversed), formatTime(&r.CreatedAt),
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func formatHours(h *float64) string {
+	if h == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*h, 'f', 1, 64)
+}
+
+// exportFormat reads ?format= and rejects unknown formats
+func exportFormat(c *gin.Context) (string, bool) {
+	format := c.DefaultQuery("format", export.FormatCSV)
+	switch format {
+	case export.FormatCSV, export.FormatXLSX, export.FormatPDF:
+		return format, true
+	}
+	apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, export.ErrUnknownFormat.Error()))
+	return "", false
+}
+
+// startDownload sets the headers for an attachment download
+func startDownload(c *gin.Context, name, format string) {
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102-150405"), format)
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(200)
+}
+
+// streamRows runs the query and hands each row to emit without loading the result set into memory
+func streamRows(query *gorm.DB, scan func(rows *sql.Rows) error) error {
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ExportComplaints downloads complaints as csv, xlsx or a pdf summary.
+// It accepts the same filters and role scoping as GET /api/complaints.
+func (cc *ComplaintController) ExportComplaints(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	filter, ok := cc.listFilter(c, user)
+	if !ok {
+		return
+	}
+
+	query := repository.ComplaintQuery(c.MustGet("db").(*gorm.DB), filter).Select(`complaints.id, complaints.title, complaints.status, complaints.priority, complaints.is_emergency,
+			cat.name AS category_name, r.name AS resident_name, s.name AS staff_name, loc.name AS location_name,
+			complaints.reopen_count, complaints.created_at, complaints.assigned_at, complaints.due_at, complaints.resolved_at`).
+		Joins("LEFT JOIN categories cat ON cat.id = complaints.category_id").
+		Joins("LEFT JOIN users r ON r.id = complaints.resident_id").
+		Joins("LEFT JOIN users s ON s.id = complaints.staff_id").
+		Joins("LEFT JOIN locations loc ON loc.id = complaints.location_id")
+
+	if format == export.FormatPDF {
+		exportComplaintsPDF(c, query)
+		return
+	}
+
+	startDownload(c, "complaints", format)
+	writer, err := export.NewRowWriter(format, c.Writer, "Complaints")
+	if err == nil {
+		err = writer.WriteRow(complaintExportHeader)
+	}
+	if err == nil {
+		err = streamRows(query, func(rows *sql.Rows) error {
+			var row complaintExportRow
+			if err := database.DB.ScanRows(rows, &row); err != nil {
+				return err
+			}
+			return writer.WriteRow(row.values())
+		})
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		// Headers are already sent, so the client sees a truncated file
+		slog.ErrorContext(c.Request.Context(), "complaint export failed", "error", err)
+	}
+}
+
+// exportComplaintsPDF aggregates the complaint rows into a summary report
+func exportComplaintsPDF(c *gin.Context, query *gorm.DB) {
+	byStatus := map[string]int{}
+	byPriority := map[string]int{}
+	byCategory := map[string]int{}
+	total, emergencies := 0, 0
+	var table [][]string
+
+	err := streamRows(query, func(rows *sql.Rows) error {
+		var row complaintExportRow
+		if err := database.DB.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		total++
+		byStatus[row.Status]++
+		byPriority[row.Priority]++
+		byCategory[deref(row.CategoryName)]++
+		if row.IsEmergency {
+			emergencies++
+		}
+		if len(table) < pdfTableRows {
+			table = append(table, []string{
+				strconv.FormatUint(uint64(row.ID), 10), row.Title, row.Status, row.Priority,
+				deref(row.CategoryName), deref(row.StaffName), row.CreatedAt.Format("2006-01-02"),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to export complaints"))
+		return
+	}
+
+	summary := export.Summary{
+		Title:       "Complaints Summary",
+		Subtitle:    fmt.Sprintf("%d complaints, %d emergencies", total, emergencies),
+		GeneratedAt: time.Now(),
+		Sections: []export.SummarySection{
+			{Heading: "By Status", Rows: countRows(byStatus)},
+			{Heading: "By Priority", Rows: countRows(byPriority)},
+			{Heading: "By Category", Rows: countRows(byCategory)},
+		},
+		Table: &export.SummaryTable{
+			Heading: fmt.Sprintf("Most Recent Complaints (up to %d)", pdfTableRows),
+			Header:  []string{"ID", "Title", "Status", "Priority", "Category", "Staff", "Created"},
+			Widths:  []float64{12, 60, 22, 18, 28, 30, 20},
+			Rows:    table,
+		},
+	}
+
+	startDownload(c, "complaints", export.FormatPDF)
+	if err := export.WritePDF(c.Writer, summary); err != nil {
+		slog.ErrorContext(c.Request.Context(), "complaint PDF export failed", "error", err)
+	}
+}
+
+// ExportController downloads the admin's society reports
+type ExportController struct {
+	Analytics *services.AnalyticsService
+}
+
+// ExportFeedback downloads the feedback of the admin's society as csv, xlsx or a pdf summary
+func (ec *ExportController) ExportFeedback(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+	db := c.MustGet("db").(*gorm.DB)
+
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	query := db.Table("feedbacks").
+		Select(`feedbacks.id, feedbacks.complaint_id, complaints.title AS complaint_title, r.name AS resident_name,
+			s.name AS staff_name, feedbacks.rating, feedbacks.comment, feedbacks.points, feedbacks.reversed, feedbacks.created_at`).
+		Joins("JOIN complaints ON feedbacks.complaint_id = complaints.id").
+		Joins("LEFT JOIN users r ON r.id = feedbacks.user_id").
+		Joins("LEFT JOIN users s ON s.id = feedbacks.staff_id").
+		Where("complaints.society_id = ?", user.SocietyID).
+		Order("feedbacks.created_at DESC")
+
+	if format == export.FormatPDF {
+		exportFeedbackPDF(c, query)
+		return
+	}
+
+	startDownload(c, "feedback", format)
+	writer, err := export.NewRowWriter(format, c.Writer, "Feedback")
+	if err == nil {
+		err = writer.WriteRow(feedbackExportHeader)
+	}
+	if err == nil {
+		err = streamRows(query, func(rows *sql.Rows) error {
+			var row feedbackExportRow
+			if err := database.DB.ScanRows(rows, &row); err != nil {
+				return err
+			}
+			return writer.WriteRow(row.values())
+		})
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "feedback export failed", "error", err)
+	}
+}
+
+// exportFeedbackPDF aggregates the feedback rows into a summary report
+func exportFeedbackPDF(c *gin.Context, query *gorm.DB) {
+	byRating := map[string]int{}
+	total, ratingSum := 0, 0
+	var table [][]string
+
+	err := streamRows(query, func(rows *sql.Rows) error {
+		var row feedbackExportRow
+		if err := database.DB.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		total++
+		ratingSum += row.Rating
+		byRating[fmt.Sprintf("%d stars", row.Rating)]++
+		if len(table) < pdfTableRows {
+			table = append(table, []string{
+				strconv.FormatUint(uint64(row.ComplaintID), 10), deref(row.ComplaintTitle), deref(row.StaffName),
+				strconv.Itoa(row.Rating), row.Comment, row.CreatedAt.Format("2006-01-02"),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to export feedback"))
+		return
+	}
+
+	average := "-"
+	if total > 0 {
+		average = strconv.FormatFloat(float64(ratingSum)/float64(total), 'f', 2, 64)
+	}
+
+	summary := export.Summary{
+		Title:       "Feedback Summary",
+		Subtitle:    fmt.Sprintf("%d feedback entries, average rating %s", total, average),
+		GeneratedAt: time.Now(),
+		Sections:    []export.SummarySection{{Heading: "Rating Distribution", Rows: countRows(byRating)}},
+		Table: &export.SummaryTable{
+			Heading: fmt.Sprintf("Most Recent Feedback (up to %d)", pdfTableRows),
+			Header:  []string{"Complaint", "Title", "Staff", "Rating", "Comment", "Date"},
+			Widths:  []float64{18, 45, 30, 14, 63, 20},
+			Rows:    table,
+		},
+	}
+
+	startDownload(c, "feedback", export.FormatPDF)
+	if err := export.WritePDF(c.Writer, summary); err != nil {
+		slog.ErrorContext(c.Request.Context(), "feedback PDF export failed", "error", err)
+	}
+}
+
+// ExportStaffPerformance downloads per-staff workload, speed and rating figures
+func (ec *ExportController) ExportStaffPerformance(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	performance, err := ec.Analytics.StaffPerformance(c.Request.Context(), user.SocietyID)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to compute staff performance"))
+		return
+	}
+
+	header := []string{"Staff ID", "Name", "Email", "Assigned", "Open", "Resolved", "Reopens",
+		"Mean Resolve Hours", "Feedback", "Average Rating", "Points"}
+	var rows [][]string
+	for _, p := range performance {
+		rows = append(rows, []string{
+			strconv.FormatUint(uint64(p.StaffID), 10), p.Name, p.Email,
+			strconv.FormatInt(p.Assigned, 10), strconv.FormatInt(p.Open, 10), strconv.FormatInt(p.Resolved, 10),
+			strconv.FormatInt(p.Reopens, 10), formatHours(p.MeanResolveHours), strconv.FormatInt(p.FeedbackCount, 10),
+			formatHours(p.AverageRating), strconv.FormatInt(p.TotalPoints, 10),
+		})
+	}
+
+	startDownload(c, "staff-performance", format)
+
+	if format == export.FormatPDF {
+		pdfRows := make([][]string, len(rows))
+		for i, r := range rows {
+			pdfRows[i] = []string{r[1], r[3], r[4], r[5], r[6], r[7], r[9], r[10]}
+		}
+		err = export.WritePDF(c.Writer, export.Summary{
+			Title:       "Staff Performance",
+			Subtitle:    fmt.Sprintf("%d staff members", len(rows)),
+			GeneratedAt: time.Now(),
+			Table: &export.SummaryTable{
+				Heading: "Staff",
+				Header:  []string{"Name", "Assigned", "Open", "Resolved", "Reopens", "Resolve h", "Rating", "Points"},
+				Widths:  []float64{50, 20, 18, 20, 20, 22, 20, 20},
+				Rows:    pdfRows,
+			},
+		})
+	} else {
+		var writer export.RowWriter
+		writer, err = export.NewRowWriter(format, c.Writer, "Staff Performance")
+		if err == nil {
+			err = writer.WriteRow(header)
+		}
+		for _, r := range rows {
+			if err != nil {
+				break
+			}
+			err = writer.WriteRow(r)
+		}
+		if err == nil {
+			err = writer.Close()
+		}
+	}
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "staff performance export failed", "error", err)
+	}
+}
+
+// countRows turns a count map into label/value rows sorted by count
+func countRows(counts map[string]int) [][2]string {
+	labels := make([]string, 0, len(counts))
+	for label := range counts {
+		labels = append(labels, label)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if counts[labels[i]] != counts[labels[j]] {
+			return counts[labels[i]] > counts[labels[j]]
+		}
+		return labels[i] < labels[j]
+	})
+
+	rows := make([][2]string, len(labels))
+	for i, label := range labels {
+		name := label
+		if name == "" {
+			name = "(none)"
+		}
+		rows[i] = [2]string{name, strconv.Itoa(counts[label])}
+	}
+	return rows
+}
@@ -1,12 +1,21 @@
 package controllers
 
 import (
+	"errors"
+
+	"github.com/VinVorteX/flashtrack/internal/apierror"
+	"github.com/VinVorteX/flashtrack/internal/dto"
 	"github.com/VinVorteX/flashtrack/internal/models"
-	"github.com/VinVorteX/flashtrack/pkg/database"
+	"github.com/VinVorteX/flashtrack/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
-func SubmitFeedback(c *gin.Context) {
+// FeedbackController serves residents' ratings and the staff points they earn
+type FeedbackController struct {
+	Feedback *services.FeedbackService
+}
+
+func (fc *FeedbackController) SubmitFeedback(c *gin.Context) {
 	user := c.MustGet("user").(*models.User)
 
 	var body struct {
@@ -16,85 +25,46 @@ func SubmitFeedback(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(400, gin.H{"error": "invalid request: " + err.Error()})
+		apierror.Write(c, apierror.Validation(err))
 		return
 	}
 
-	// Get complaint
-	var complaint models.Complaint
-	if err := database.DB.First(&complaint, body.ComplaintID).Error; err != nil {
-		c.JSON(404, gin.H{"error": "complaint not found"})
+	feedback, err := fc.Feedback.Submit(c.Request.Context(), user, body.ComplaintID, body.Rating, body.Comment)
+	switch {
+	case errors.Is(err, services.ErrComplaintNotFound):
+		apierror.Write(c, apierror.Wrap(apierror.CodeNotFound, err))
 		return
-	}
-
-	// Verify user owns this complaint
-	if complaint.ResidentID != user.ID {
-		c.JSON(403, gin.H{"error": "you can only provide feedback for your own complaints"})
+	case errors.Is(err, services.ErrFeedbackNotOwner):
+		apierror.Write(c, apierror.Wrap(apierror.CodeForbidden, err))
 		return
-	}
-
-	// Verify complaint is resolved
-	if complaint.Status != "resolved" {
-		c.JSON(400, gin.H{"error": "can only provide feedback for resolved complaints"})
+	case errors.Is(err, services.ErrFeedbackNotResolved), errors.Is(err, services.ErrFeedbackExists),
+		errors.Is(err, services.ErrMergedComplaint):
+		apierror.Write(c, apierror.Wrap(apierror.CodeInvalidRequest, err))
 		return
-	}
-
-	// Check if feedback already exists
-	var existingFeedback models.Feedback
-	if err := database.DB.Where("complaint_id = ?", body.ComplaintID).First(&existingFeedback).Error; err == nil {
-		c.JSON(400, gin.H{"error": "feedback already submitted for this complaint"})
+	case err != nil:
+		apierror.Write(c, apierror.Internal(err, "failed to submit feedback"))
 		return
 	}
 
-	// Calculate points based on rating (2 points per star)
-	points := body.Rating * 2
-
-	// Create feedback
-	feedback := models.Feedback{
-		ComplaintID: body.ComplaintID,
-		UserID:      user.ID,
-		StaffID:     *complaint.StaffID,
-		Rating:      body.Rating,
-		Comment:     body.Comment,
-		Points:      points,
-	}
-
-	if err := database.DB.Create(&feedback).Error; err != nil {
-		c.JSON(500, gin.H{"error": "failed to submit feedback"})
-		return
-	}
-
-	// Award points to staff based on user feedback
-	var staffPoints models.StaffPoints
-	if err := database.DB.Where("staff_id = ?", *complaint.StaffID).FirstOrCreate(&staffPoints, models.StaffPoints{StaffID: *complaint.StaffID}).Error; err == nil {
-		staffPoints.TotalPoints += points
-		staffPoints.TasksCompleted += 1
-		database.DB.Save(&staffPoints)
-	}
-
 	c.JSON(200, gin.H{
 		"message":  "feedback submitted successfully",
-		"feedback": feedback,
+		"feedback": dto.NewFeedbackResponse(feedback),
 	})
 }
 
-func GetStaffPoints(c *gin.Context) {
+func (fc *FeedbackController) GetStaffPoints(c *gin.Context) {
 	user := c.MustGet("user").(*models.User)
 
-	var staffPoints models.StaffPoints
-	if err := database.DB.Where("staff_id = ?", user.ID).First(&staffPoints).Error; err != nil {
-		// Return zero points if not found
-		c.JSON(200, gin.H{
-			"total_points":    0,
-			"tasks_completed": 0,
-		})
+	staffPoints, err := fc.Feedback.StaffPoints(c.Request.Context(), user.ID)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch points"))
 		return
 	}
 
 	c.JSON(200, staffPoints)
 }
 
-func CheckPendingFeedback(c *gin.Context) {
+func (fc *FeedbackController) CheckPendingFeedback(c *gin.Context) {
 	user := c.MustGet("user").(*models.User)
 
 	var body struct {
@@ -102,68 +72,27 @@ func CheckPendingFeedback(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(400, gin.H{"error": "invalid request"})
+		apierror.Write(c, apierror.Validation(err))
 		return
 	}
 
-	// Get complaint IDs that already have feedback
-	var existingFeedbacks []models.Feedback
-	database.DB.Where("complaint_id IN ? AND user_id = ?", body.ComplaintIDs, user.ID).Find(&existingFeedbacks)
-
-	// Create map of complaint IDs with feedback
-	feedbackExists := make(map[uint]bool)
-	for _, fb := range existingFeedbacks {
-		feedbackExists[fb.ComplaintID] = true
-	}
-
-	// Find complaint IDs that need feedback
-	var pending []uint
-	for _, id := range body.ComplaintIDs {
-		if !feedbackExists[id] {
-			pending = append(pending, id)
-		}
+	pending, err := fc.Feedback.Pending(c.Request.Context(), user.ID, body.ComplaintIDs)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to check feedback"))
+		return
 	}
 
 	c.JSON(200, gin.H{"pending": pending})
 }
 
-func GetFeedbackForAdmin(c *gin.Context) {
+func (fc *FeedbackController) GetFeedbackForAdmin(c *gin.Context) {
 	user := c.MustGet("user").(*models.User)
 
-	type FeedbackResponse struct {
-		models.Feedback
-		ComplaintTitle string `json:"complaint_title"`
-		UserName       string `json:"user_name"`
-		StaffName      string `json:"staff_name"`
-	}
-
-	var feedbacks []models.Feedback
-	query := database.DB.Joins("JOIN complaints ON feedbacks.complaint_id = complaints.id").
-		Where("complaints.society_id = ?", user.SocietyID).
-		Order("feedbacks.created_at DESC")
-
-	if err := query.Find(&feedbacks).Error; err != nil {
-		c.JSON(500, gin.H{"error": "failed to fetch feedbacks"})
+	response, err := fc.Feedback.ListForSociety(c.Request.Context(), user.SocietyID)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch feedbacks"))
 		return
 	}
 
-	var response []FeedbackResponse
-	for _, fb := range feedbacks {
-		var complaint models.Complaint
-		var resident models.User
-		var staff models.User
-
-		database.DB.Select("title").First(&complaint, fb.ComplaintID)
-		database.DB.Select("name").First(&resident, fb.UserID)
-		database.DB.Select("name").First(&staff, fb.StaffID)
-
-		response = append(response, FeedbackResponse{
-			Feedback:       fb,
-			ComplaintTitle: complaint.Title,
-			UserName:       resident.Name,
-			StaffName:      staff.Name,
-		})
-	}
-
 	c.JSON(200, response)
 }
@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/VinVorteX/flashtrack/internal/apierror"
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ImportController bulk loads society data from csv and completes the invites it sends
+type ImportController struct {
+	Imports *services.ImportService
+	Invites *services.InviteService
+}
+
+// importSource returns the csv from a multipart "file" field or, failing that, the raw request
+// body. The router caps the body size, so reading past the limit fails with http.MaxBytesError.
+func importSource(c *gin.Context) (io.ReadCloser, error) {
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, errors.New("missing csv file in field \"file\"")
+		}
+		return header.Open()
+	}
+	return c.Request.Body, nil
+}
+
+type importFunc func(ctx context.Context, societyID uint, r io.Reader, opts services.ImportOptions) (*services.ImportReport, error)
+
+// runImport validates the upload, applies it unless ?dry_run=true and returns the per-row report
+func runImport(c *gin.Context, run importFunc) {
+	user := c.MustGet("user").(*models.User)
+
+	source, err := importSource(c)
+	if err != nil {
+		apierror.Write(c, apierror.Wrap(apierror.CodeInvalidRequest, err))
+		return
+	}
+	defer source.Close()
+
+	opts := services.ImportOptions{
+		DryRun:      c.Query("dry_run") == "true",
+		SendInvites: c.Query("send_invites") == "true",
+		Actor:       user,
+	}
+
+	report, err := run(c.Request.Context(), user.SocietyID, source, opts)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			apierror.Write(c, apierror.New(apierror.CodePayloadTooLarge, "csv file is too large"))
+			return
+		}
+		if planError(c, err) {
+			return
+		}
+		apierror.Write(c, apierror.Wrap(apierror.CodeInvalidRequest, err))
+		return
+	}
+
+	// Nothing was written when any row failed, so the admin can fix the file and retry
+	if report.Failed > 0 && !report.DryRun {
+		c.JSON(422, report)
+		return
+	}
+	c.JSON(200, report)
+}
+
+// ImportUsers bulk creates or updates residents and staff from csv
+func (ic *ImportController) ImportUsers(c *gin.Context) {
+	runImport(c, ic.Imports.ImportUsers)
+}
+
+// ImportCategories bulk creates or updates the society's categories from csv
+func (ic *ImportController) ImportCategories(c *gin.Context) {
+	runImport(c, ic.Imports.ImportCategories)
+}
+
+// AcceptInvite lets an invited user set their password
+func (ic *ImportController) AcceptInvite(c *gin.Context) {
+	var body struct {
+		Token    string `json:"token" binding:"required"`
+		Password string `json:"password" binding:"required,min=8"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	if err := ic.Invites.Accept(c.Request.Context(), body.Token, body.Password); err != nil {
+		if errors.Is(err, services.ErrInvalidInvite) {
+			apierror.Write(c, apierror.Wrap(apierror.CodeInvalidRequest, err))
+			return
+		}
+		apierror.Write(c, apierror.Internal(err, "failed to accept invite"))
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "password set, you can now log in"})
+}
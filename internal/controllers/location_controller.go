@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/VinVorteX/flashtrack/internal/apierror"
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// LocationController serves the society's buildings, floors, units and common areas
+type LocationController struct {
+	Locations *services.LocationService
+}
+
+// GetLocations lists the locations of the user's society, optionally filtered by ?type= or ?parent_id=
+func (lc *LocationController) GetLocations(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+	db := c.MustGet("db").(*gorm.DB)
+
+	query := db.Where("society_id = ?", user.SocietyID)
+	if locationType := c.Query("type"); locationType != "" {
+		query = query.Where("type = ?", locationType)
+	}
+	if parent := c.Query("parent_id"); parent != "" {
+		parentID, err := strconv.ParseUint(parent, 10, 32)
+		if err != nil {
+			apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "invalid parent_id"))
+			return
+		}
+		query = query.Where("parent_id = ?", parentID)
+	}
+
+	locations := []models.Location{}
+	if err := query.Order("parent_id NULLS FIRST, name").Find(&locations).Error; err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch locations"))
+		return
+	}
+
+	c.JSON(200, locations)
+}
+
+// CreateLocation adds a building, floor, unit or common area to the admin's society
+func (lc *LocationController) CreateLocation(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	var body struct {
+		Type     string `json:"type" binding:"required"`
+		Name     string `json:"name" binding:"required"`
+		ParentID *uint  `json:"parent_id"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	location := models.Location{
+		SocietyID: user.SocietyID,
+		ParentID:  body.ParentID,
+		Type:      body.Type,
+		Name:      body.Name,
+	}
+
+	if err := lc.Locations.Create(c.Request.Context(), &location); err != nil {
+		apierror.Write(c, apierror.Wrap(apierror.CodeInvalidRequest, err))
+		return
+	}
+
+	c.JSON(200, location)
+}
+
+// DeleteLocation removes an unused location from the admin's society
+func (lc *LocationController) DeleteLocation(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	locationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "invalid location ID"))
+		return
+	}
+
+	err = lc.Locations.Delete(c.Request.Context(), user.SocietyID, uint(locationID))
+	switch {
+	case errors.Is(err, services.ErrInvalidLocation):
+		apierror.Write(c, apierror.Wrap(apierror.CodeNotFound, err))
+		return
+	case errors.Is(err, services.ErrLocationInUse):
+		apierror.Write(c, apierror.Wrap(apierror.CodeInvalidRequest, err))
+		return
+	case err != nil:
+		apierror.Write(c, apierror.Internal(err, "failed to delete location"))
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "location deleted"})
+}
+
+// SetUserUnit links a resident of the admin's society to a unit
+func (lc *LocationController) SetUserUnit(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+	db := c.MustGet("db").(*gorm.DB)
+
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "invalid user ID"))
+		return
+	}
+
+	var body struct {
+		UnitID *uint `json:"unit_id"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	var resident models.User
+	if err := db.Where("id = ? AND society_id = ?", userID, user.SocietyID).First(&resident).Error; err != nil {
+		apierror.Write(c, apierror.New(apierror.CodeNotFound, "user not found"))
+		return
+	}
+
+	if body.UnitID != nil {
+		if err := lc.Locations.ValidateUnit(c.Request.Context(), user.SocietyID, *body.UnitID); err != nil {
+			apierror.Write(c, apierror.Wrap(apierror.CodeInvalidRequest, err))
+			return
+		}
+	}
+
+	if err := db.Model(&resident).Update("unit_id", body.UnitID).Error; err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to update unit"))
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"user_id": resident.ID,
+		"unit_id": body.UnitID,
+	})
+}
+
+// GetLocationStats returns complaint counts per building and common area to highlight recurring problems
+func (lc *LocationController) GetLocationStats(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	stats, err := lc.Locations.Stats(c.Request.Context(), user.SocietyID)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to compute location stats"))
+		return
+	}
+	if stats == nil {
+		stats = []services.LocationStat{}
+	}
+
+	c.JSON(200, stats)
+}
@@ -1,52 +1,68 @@
 package controllers
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
+	"slices"
 
+	"github.com/VinVorteX/flashtrack/internal/apierror"
+	"github.com/VinVorteX/flashtrack/internal/dto"
+	"github.com/VinVorteX/flashtrack/internal/logging"
 	"github.com/VinVorteX/flashtrack/internal/models"
 	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/VinVorteX/flashtrack/pkg/database"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 )
 
-var (
-	notificationService = &services.NotificationService{}
-	upgrader            = websocket.Upgrader{
+// NotificationController serves the signed-in user's notifications over HTTP and WebSocket
+type NotificationController struct {
+	Notifications *services.NotificationService
+
+	// AllowedOrigins are the frontend origins that may open a WebSocket
+	AllowedOrigins []string
+}
+
+// upgrader accepts WebSocket handshakes only from the allowed frontend origins
+func (nc *NotificationController) upgrader() *websocket.Upgrader {
+	return &websocket.Upgrader{
 		CheckOrigin: func(r *http.Request) bool {
-			// Allow connections from frontend origins
-			origin := r.Header.Get("Origin")
-			return origin == "http://localhost:3000" || origin == "http://localhost:5173"
+			return slices.Contains(nc.AllowedOrigins, r.Header.Get("Origin"))
 		},
 	}
-)
+}
 
 // WebSocketHandler handles WebSocket connections for real-time notifications
-func WebSocketHandler(c *gin.Context) {
+func (nc *NotificationController) WebSocketHandler(c *gin.Context) {
 	user := c.MustGet("user").(*models.User)
 
-	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	// The handshake response is written by the upgrader, so echo the request ID explicitly
+	header := http.Header{logging.RequestIDHeader: {c.GetString("request_id")}}
+	conn, err := nc.upgrader().Upgrade(c.Writer, c.Request, header)
 	if err != nil {
-		log.Printf("WebSocket upgrade failed: %v", err)
+		slog.WarnContext(c.Request.Context(), "websocket upgrade failed", "user_id", user.ID, "error", err)
 		return
 	}
 
-	// Register connection
-	notificationService.RegisterConnection(user.ID, conn)
+	// Register connection under this request's ID, which then identifies the session in the log.
+	// The socket has no tenant session, so scope its reads to the user's society instead.
+	ctx := database.WithSession(c.Request.Context(), database.ForSociety(user.SocietyID))
+	nc.Notifications.RegisterConnection(ctx, user.ID, conn)
 
-	// Send unread notifications immediately
-	notifications, err := notificationService.GetUserNotifications(user.ID)
+	// Send unread notifications immediately, through the service so they queue behind any
+	// notification already being written to the socket
+	notifications, err := nc.Notifications.GetUserNotifications(ctx, user.ID)
 	if err == nil {
-		for _, notif := range notifications {
-			if !notif.IsRead {
-				conn.WriteJSON(notif)
+		for i := range notifications {
+			if !notifications[i].IsRead {
+				nc.Notifications.SendWebSocketNotification(ctx, user.ID, &notifications[i])
 			}
 		}
 	}
 
 	// Keep connection alive and handle disconnect
 	defer func() {
-		notificationService.RemoveConnection(user.ID)
+		nc.Notifications.RemoveConnection(ctx, user.ID)
 		conn.Close()
 	}()
 
@@ -59,20 +75,20 @@ func WebSocketHandler(c *gin.Context) {
 }
 
 // GetNotifications retrieves all notifications for logged-in user
-func GetNotifications(c *gin.Context) {
+func (nc *NotificationController) GetNotifications(c *gin.Context) {
 	user := c.MustGet("user").(*models.User)
 
-	notifications, err := notificationService.GetUserNotifications(user.ID)
+	notifications, err := nc.Notifications.GetUserNotifications(c.Request.Context(), user.ID)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "failed to fetch notifications"})
+		apierror.Write(c, apierror.Internal(err, "failed to fetch notifications"))
 		return
 	}
 
-	c.JSON(200, gin.H{"notifications": notifications})
+	c.JSON(200, gin.H{"notifications": dto.NewNotificationResponses(notifications)})
 }
 
 // MarkNotificationRead marks a notification as read
-func MarkNotificationRead(c *gin.Context) {
+func (nc *NotificationController) MarkNotificationRead(c *gin.Context) {
 	user := c.MustGet("user").(*models.User)
 
 	var body struct {
@@ -80,12 +96,12 @@ func MarkNotificationRead(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(400, gin.H{"error": "invalid request"})
+		apierror.Write(c, apierror.Validation(err))
 		return
 	}
 
-	if err := notificationService.MarkAsRead(body.NotificationID, user.ID); err != nil {
-		c.JSON(500, gin.H{"error": "failed to mark notification as read"})
+	if err := nc.Notifications.MarkAsRead(c.Request.Context(), body.NotificationID, user.ID); err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to mark notification as read"))
 		return
 	}
 
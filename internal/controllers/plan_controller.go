@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/VinVorteX/flashtrack/internal/apierror"
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// PlanController serves the subscription plans and a society's use of its own
+type PlanController struct {
+	Plans *services.PlanService
+}
+
+// planError writes a plan_limit_reached problem when err is a plan limit error and reports
+// whether it did
+func planError(c *gin.Context, err error) bool {
+	var limitErr *services.LimitError
+	if !errors.As(err, &limitErr) {
+		return false
+	}
+	apierror.Write(c, apierror.New(apierror.CodePlanLimitReached, err.Error()).
+		With("limit", limitErr.Limit).
+		With("plan", limitErr.Plan).
+		With("max", limitErr.Max).
+		With("current", limitErr.Current))
+	return true
+}
+
+// GetPlanUsage returns the society's plan, its features and how close the society is to each limit
+func (pc *PlanController) GetPlanUsage(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	usage, err := pc.Plans.Usage(c.Request.Context(), user.SocietyID)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch plan usage"))
+		return
+	}
+
+	c.JSON(200, usage)
+}
+
+// GetPlans lists every plan with its limits and features
+func (pc *PlanController) GetPlans(c *gin.Context) {
+	plans := make([]services.Plan, len(services.PlanNames))
+	for i, name := range services.PlanNames {
+		plans[i] = services.Plans[name]
+	}
+	c.JSON(200, plans)
+}
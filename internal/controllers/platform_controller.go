@@ -0,0 +1,261 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/apierror"
+	"github.com/VinVorteX/flashtrack/internal/dto"
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// PlatformController serves the super-admin's society management
+type PlatformController struct {
+	Platform *services.PlatformService
+}
+
+// societyIDParam parses the :id route parameter, writing a 400 when it is invalid
+func societyIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "invalid society ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// platformError maps society management errors to responses
+func platformError(c *gin.Context, err error, action string) {
+	switch {
+	case errors.Is(err, services.ErrSocietyNotFound):
+		apierror.Write(c, apierror.Wrap(apierror.CodeNotFound, err))
+	case errors.Is(err, services.ErrSocietySuspended), errors.Is(err, services.ErrSocietyActive),
+		errors.Is(err, services.ErrSocietyNotSuspended), errors.Is(err, services.ErrNoSocietyAdmin):
+		apierror.Write(c, apierror.Wrap(apierror.CodeConflict, err))
+	default:
+		apierror.Write(c, apierror.Wrap(apierror.CodeInvalidRequest, fmt.Errorf("failed to %s: %w", action, err)))
+	}
+}
+
+// ListSocieties lists every society on the platform
+func (pc *PlatformController) ListSocieties(c *gin.Context) {
+	societies, err := pc.Platform.List(c.Request.Context())
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch societies"))
+		return
+	}
+
+	c.JSON(200, dto.NewSocietyResponses(societies))
+}
+
+// CreateSociety adds a society, optionally inviting its first admin
+func (pc *PlatformController) CreateSociety(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	var body struct {
+		Name       string `json:"name" binding:"required"`
+		Address    string `json:"address"`
+		Plan       string `json:"plan"`
+		AdminName  string `json:"admin_name"`
+		AdminEmail string `json:"admin_email"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	society, admin, err := pc.Platform.Create(c.Request.Context(), user, services.NewSociety{
+		Name:       body.Name,
+		Address:    body.Address,
+		Plan:       body.Plan,
+		AdminName:  body.AdminName,
+		AdminEmail: body.AdminEmail,
+	})
+	if err != nil {
+		platformError(c, err, "create society")
+		return
+	}
+
+	response := gin.H{"society": dto.NewSocietyResponse(society)}
+	if admin != nil {
+		response["admin"] = dto.NewUserResponse(admin)
+	}
+	c.JSON(201, response)
+}
+
+// SuspendSociety locks a society's members out
+func (pc *PlatformController) SuspendSociety(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	societyID, ok := societyIDParam(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Reason string `json:"reason"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	society, err := pc.Platform.Suspend(c.Request.Context(), user, societyID, body.Reason)
+	if err != nil {
+		platformError(c, err, "suspend society")
+		return
+	}
+
+	c.JSON(200, dto.NewSocietyResponse(society))
+}
+
+// ResumeSociety lifts a society's suspension
+func (pc *PlatformController) ResumeSociety(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	societyID, ok := societyIDParam(c)
+	if !ok {
+		return
+	}
+
+	society, err := pc.Platform.Resume(c.Request.Context(), user, societyID)
+	if err != nil {
+		platformError(c, err, "resume society")
+		return
+	}
+
+	c.JSON(200, dto.NewSocietyResponse(society))
+}
+
+// SetSocietyPlan moves a society to another plan
+func (pc *PlatformController) SetSocietyPlan(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	societyID, ok := societyIDParam(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Plan string `json:"plan" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	society, err := pc.Platform.SetPlan(c.Request.Context(), user, societyID, body.Plan)
+	if err != nil {
+		platformError(c, err, "change plan")
+		return
+	}
+
+	c.JSON(200, dto.NewSocietyResponse(society))
+}
+
+// DeleteSociety removes a suspended society with all of its data
+func (pc *PlatformController) DeleteSociety(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	societyID, ok := societyIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := pc.Platform.Delete(c.Request.Context(), user, societyID); err != nil {
+		platformError(c, err, "delete society")
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "society deleted"})
+}
+
+// GetPlatformUsage reports size and activity per society. Supports ?society_id=.
+func (pc *PlatformController) GetPlatformUsage(c *gin.Context) {
+	var societyID uint
+	if param := c.Query("society_id"); param != "" {
+		id, err := strconv.ParseUint(param, 10, 32)
+		if err != nil {
+			apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "invalid society_id"))
+			return
+		}
+		societyID = uint(id)
+	}
+
+	usage, err := pc.Platform.Usage(c.Request.Context(), societyID)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch usage"))
+		return
+	}
+
+	c.JSON(200, usage)
+}
+
+// ImpersonateSocietyAdmin issues a short-lived token for the society's admin. A reason is required
+// and the session is written to the society's audit log.
+func (pc *PlatformController) ImpersonateSocietyAdmin(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	societyID, ok := societyIDParam(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	token, admin, err := pc.Platform.Impersonate(c.Request.Context(), user, societyID, body.Reason)
+	if err != nil {
+		platformError(c, err, "impersonate")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"token":      token,
+		"user":       dto.NewUserResponse(admin),
+		"expires_in": int(services.ImpersonationTTL / time.Second),
+	})
+}
+
+// GetPlatformAuditLogs lists platform actions and actions taken while impersonating, newest first.
+// Supports ?society_id=, ?action=, ?actor_id=, ?since=YYYY-MM-DD and ?limit=.
+func (pc *PlatformController) GetPlatformAuditLogs(c *gin.Context) {
+	var filter services.AuditFilter
+	filter.Action = c.Query("action")
+	if society, err := strconv.ParseUint(c.Query("society_id"), 10, 32); err == nil {
+		filter.SocietyID = uint(society)
+	}
+	if actor, err := strconv.ParseUint(c.Query("actor_id"), 10, 32); err == nil {
+		filter.ActorID = uint(actor)
+	}
+	if since := c.Query("since"); since != "" {
+		t, err := time.Parse("2006-01-02", since)
+		if err != nil {
+			apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "since must be a date like 2025-01-31"))
+			return
+		}
+		filter.Since = &t
+	}
+	filter.Limit, _ = strconv.Atoi(c.Query("limit"))
+
+	entries, err := services.ListPlatformAudit(c.Request.Context(), filter)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch audit logs"))
+		return
+	}
+
+	c.JSON(200, entries)
+}
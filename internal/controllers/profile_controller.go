@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/VinVorteX/flashtrack/internal/apierror"
+	"github.com/VinVorteX/flashtrack/internal/dto"
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ProfileController serves the signed-in user's own profile
+type ProfileController struct {
+	Profiles *services.ProfileService
+}
+
+// GetMe returns the signed-in user's profile
+func (pc *ProfileController) GetMe(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+	c.JSON(200, dto.NewUserResponse(user))
+}
+
+// UpdateMe changes the signed-in user's name, phone, avatar, language or time zone
+func (pc *ProfileController) UpdateMe(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	var body struct {
+		Name      *string `json:"name"`
+		Phone     *string `json:"phone"`
+		AvatarURL *string `json:"avatar_url"`
+		Language  *string `json:"language"`
+		Timezone  *string `json:"timezone"`
+		FCMToken  *string `json:"fcm_token"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	updated, err := pc.Profiles.Update(c.Request.Context(), user, services.ProfileChanges{
+		Name:      body.Name,
+		Phone:     body.Phone,
+		AvatarURL: body.AvatarURL,
+		Language:  body.Language,
+		Timezone:  body.Timezone,
+		FCMToken:  body.FCMToken,
+	})
+	if err != nil {
+		apierror.Write(c, apierror.Wrap(apierror.CodeInvalidRequest, err))
+		return
+	}
+
+	c.JSON(200, dto.NewUserResponse(updated))
+}
+
+// ChangeMyPassword replaces the signed-in user's password after checking the current one
+func (pc *ProfileController) ChangeMyPassword(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	var body struct {
+		CurrentPassword string `json:"current_password" binding:"required"`
+		NewPassword     string `json:"new_password" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	if err := pc.Profiles.ChangePassword(c.Request.Context(), user, body.CurrentPassword, body.NewPassword); err != nil {
+		if errors.Is(err, services.ErrWrongPassword) {
+			apierror.Write(c, apierror.Wrap(apierror.CodeForbidden, err))
+			return
+		}
+		apierror.Write(c, apierror.Wrap(apierror.CodeInvalidRequest, err))
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "password changed"})
+}
+
+// DeleteMe records an account deletion request for the society's admins to complete
+func (pc *ProfileController) DeleteMe(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	var body struct {
+		Password string `json:"password" binding:"required"`
+		Reason   string `json:"reason"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	err := pc.Profiles.RequestDeletion(c.Request.Context(), user, body.Password, body.Reason)
+	switch {
+	case errors.Is(err, services.ErrWrongPassword):
+		apierror.Write(c, apierror.Wrap(apierror.CodeForbidden, err))
+		return
+	case errors.Is(err, services.ErrDeletionAlreadyRequested):
+		apierror.Write(c, apierror.Wrap(apierror.CodeConflict, err))
+		return
+	case err != nil:
+		apierror.Write(c, apierror.Internal(err, "failed to request account deletion"))
+		return
+	}
+
+	c.JSON(202, gin.H{
+		"message":               "deletion requested, an admin will remove your account",
+		"deletion_requested_at": user.DeletionRequestedAt,
+	})
+}
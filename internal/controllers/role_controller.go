@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/VinVorteX/flashtrack/internal/apierror"
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// RoleController serves the society's built-in and custom roles
+type RoleController struct {
+	Roles *services.RoleService
+}
+
+// roleError maps role management errors to responses
+func roleError(c *gin.Context, err error, action string) {
+	switch {
+	case errors.Is(err, services.ErrRoleNotFound):
+		apierror.Write(c, apierror.Wrap(apierror.CodeNotFound, err))
+	case errors.Is(err, services.ErrRoleInUse):
+		apierror.Write(c, apierror.Wrap(apierror.CodeConflict, err))
+	default:
+		apierror.Write(c, apierror.Wrap(apierror.CodeInvalidRequest, fmt.Errorf("failed to %s: %w", action, err)))
+	}
+}
+
+// GetPermissions lists every permission a role can grant
+func (rc *RoleController) GetPermissions(c *gin.Context) {
+	c.JSON(200, services.AllPermissions)
+}
+
+// GetRoles lists the built-in roles and the society's custom roles with how many users hold each
+func (rc *RoleController) GetRoles(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	roles, err := rc.Roles.List(c.Request.Context(), user.SocietyID)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch roles"))
+		return
+	}
+
+	c.JSON(200, roles)
+}
+
+// CreateRole adds a custom role to the society
+func (rc *RoleController) CreateRole(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	var body struct {
+		Name        string   `json:"name" binding:"required"`
+		Description string   `json:"description"`
+		Permissions []string `json:"permissions"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	role, err := rc.Roles.Create(c.Request.Context(), user.SocietyID, body.Name, body.Description, body.Permissions)
+	if err != nil {
+		roleError(c, err, "create role")
+		return
+	}
+
+	c.JSON(201, role)
+}
+
+// UpdateRole changes a custom role's description or permissions
+func (rc *RoleController) UpdateRole(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	roleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "invalid role ID"))
+		return
+	}
+
+	var body struct {
+		Description *string  `json:"description"`
+		Permissions []string `json:"permissions"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	role, err := rc.Roles.Update(c.Request.Context(), user.SocietyID, uint(roleID), body.Description, body.Permissions)
+	if err != nil {
+		roleError(c, err, "update role")
+		return
+	}
+
+	c.JSON(200, role)
+}
+
+// DeleteRole removes a custom role that no user holds
+func (rc *RoleController) DeleteRole(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	roleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "invalid role ID"))
+		return
+	}
+
+	if err := rc.Roles.Delete(c.Request.Context(), user.SocietyID, uint(roleID)); err != nil {
+		roleError(c, err, "delete role")
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "role deleted"})
+}
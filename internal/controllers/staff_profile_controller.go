@@ -0,0 +1,274 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/apierror"
+	"github.com/VinVorteX/flashtrack/internal/dto"
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+const leaveDateLayout = "2006-01-02"
+
+// StaffProfileController serves staff schedules, skills and leave
+type StaffProfileController struct {
+	Staff *services.StaffService
+}
+
+// findSocietyStaff loads the staff member from the :id param, scoped to the admin's society
+func (sc *StaffProfileController) findSocietyStaff(c *gin.Context, user *models.User) (*models.User, bool) {
+	staffID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "invalid staff ID"))
+		return nil, false
+	}
+
+	staff, err := sc.Staff.FindMember(c.Request.Context(), user.SocietyID, uint(staffID))
+	if errors.Is(err, services.ErrStaffNotFound) {
+		apierror.Write(c, apierror.Wrap(apierror.CodeNotFound, err))
+		return nil, false
+	}
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch staff"))
+		return nil, false
+	}
+
+	return staff, true
+}
+
+// GetStaffProfile returns a staff member's schedule, skills and current availability
+func (sc *StaffProfileController) GetStaffProfile(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	staff, ok := sc.findSocietyStaff(c, user)
+	if !ok {
+		return
+	}
+
+	profile, err := sc.Staff.GetProfile(c.Request.Context(), staff.ID)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch staff profile"))
+		return
+	}
+
+	skills, err := sc.Staff.GetCategoryIDs(c.Request.Context(), []uint{staff.ID})
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch staff skills"))
+		return
+	}
+
+	availability, err := sc.Staff.Availability(c.Request.Context(), user.SocietyID, []uint{staff.ID}, time.Now())
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch staff availability"))
+		return
+	}
+
+	leaves, err := sc.Staff.Leaves(c.Request.Context(), user.SocietyID, &staff.ID)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch leaves"))
+		return
+	}
+
+	categoryIDs := skills[staff.ID]
+	if categoryIDs == nil {
+		categoryIDs = []uint{}
+	}
+
+	c.JSON(200, gin.H{
+		"staff":        dto.NewUserResponse(staff),
+		"profile":      profile,
+		"category_ids": categoryIDs,
+		"availability": availability[staff.ID],
+		"available":    availability[staff.ID].Available(),
+		"leaves":       leaves,
+	})
+}
+
+// UpdateStaffProfile sets a staff member's shift, working days, capacity and optionally skills
+func (sc *StaffProfileController) UpdateStaffProfile(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	staff, ok := sc.findSocietyStaff(c, user)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		ShiftStart    string `json:"shift_start"`
+		ShiftEnd      string `json:"shift_end"`
+		WorkingDays   []int  `json:"working_days"`
+		MaxConcurrent int    `json:"max_concurrent"`
+		CategoryIDs   []uint `json:"category_ids"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	profile := models.StaffProfile{
+		StaffID:       staff.ID,
+		SocietyID:     user.SocietyID,
+		ShiftStart:    body.ShiftStart,
+		ShiftEnd:      body.ShiftEnd,
+		WorkingDays:   services.FormatWorkingDays(body.WorkingDays),
+		MaxConcurrent: body.MaxConcurrent,
+	}
+
+	if err := sc.Staff.SaveProfile(c.Request.Context(), &profile); err != nil {
+		apierror.Write(c, apierror.Wrap(apierror.CodeInvalidRequest, err))
+		return
+	}
+
+	if body.CategoryIDs != nil {
+		if err := sc.Staff.SetCategories(c.Request.Context(), user.SocietyID, staff.ID, body.CategoryIDs); err != nil {
+			if errors.Is(err, services.ErrCategoryNotInSociety) {
+				apierror.Write(c, apierror.Wrap(apierror.CodeInvalidRequest, err))
+				return
+			}
+			apierror.Write(c, apierror.Internal(err, "failed to update staff categories"))
+			return
+		}
+	}
+
+	sc.GetStaffProfile(c)
+}
+
+// parseLeaveDates parses an inclusive YYYY-MM-DD date range
+func parseLeaveDates(start, end string) (time.Time, time.Time, error) {
+	startDate, err := time.ParseInLocation(leaveDateLayout, start, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("start_date must use YYYY-MM-DD format")
+	}
+
+	endDate := startDate
+	if end != "" {
+		endDate, err = time.ParseInLocation(leaveDateLayout, end, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("end_date must use YYYY-MM-DD format")
+		}
+	}
+
+	if endDate.Before(startDate) {
+		return time.Time{}, time.Time{}, errors.New("end_date cannot be before start_date")
+	}
+
+	return startDate, endDate, nil
+}
+
+// CreateStaffLeave records a leave period for a staff member
+func (sc *StaffProfileController) CreateStaffLeave(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	staff, ok := sc.findSocietyStaff(c, user)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		StartDate string `json:"start_date" binding:"required"`
+		EndDate   string `json:"end_date"`
+		Reason    string `json:"reason"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	startDate, endDate, err := parseLeaveDates(body.StartDate, body.EndDate)
+	if err != nil {
+		apierror.Write(c, apierror.Wrap(apierror.CodeInvalidRequest, err))
+		return
+	}
+
+	leave := models.StaffLeave{
+		StaffID:   &staff.ID,
+		SocietyID: user.SocietyID,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Reason:    body.Reason,
+	}
+
+	if err := sc.Staff.CreateLeave(c.Request.Context(), &leave); err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to create leave"))
+		return
+	}
+
+	c.JSON(200, leave)
+}
+
+// CreateHoliday records a society-wide holiday during which no staff are on duty
+func (sc *StaffProfileController) CreateHoliday(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	var body struct {
+		StartDate string `json:"start_date" binding:"required"`
+		EndDate   string `json:"end_date"`
+		Reason    string `json:"reason"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	startDate, endDate, err := parseLeaveDates(body.StartDate, body.EndDate)
+	if err != nil {
+		apierror.Write(c, apierror.Wrap(apierror.CodeInvalidRequest, err))
+		return
+	}
+
+	holiday := models.StaffLeave{
+		SocietyID: user.SocietyID,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Reason:    body.Reason,
+	}
+
+	if err := sc.Staff.CreateLeave(c.Request.Context(), &holiday); err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to create holiday"))
+		return
+	}
+
+	c.JSON(200, holiday)
+}
+
+// GetLeaves lists leave and holiday entries for the admin's society that have not ended yet
+func (sc *StaffProfileController) GetLeaves(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	leaves, err := sc.Staff.Leaves(c.Request.Context(), user.SocietyID, nil)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch leaves"))
+		return
+	}
+
+	c.JSON(200, leaves)
+}
+
+// DeleteLeave removes a leave or holiday entry from the admin's society
+func (sc *StaffProfileController) DeleteLeave(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	leaveID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "invalid leave ID"))
+		return
+	}
+
+	if err := sc.Staff.DeleteLeave(c.Request.Context(), user.SocietyID, uint(leaveID)); err != nil {
+		if errors.Is(err, services.ErrLeaveNotFound) {
+			apierror.Write(c, apierror.Wrap(apierror.CodeNotFound, err))
+			return
+		}
+		apierror.Write(c, apierror.Internal(err, "failed to delete leave"))
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "leave deleted"})
+}
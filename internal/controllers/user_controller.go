@@ -0,0 +1,243 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/apierror"
+	"github.com/VinVorteX/flashtrack/internal/dto"
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// UserController serves the admin's management of their society's users
+type UserController struct {
+	Users *services.UserService
+}
+
+// userIDParam parses the :id route parameter, writing a 400 when it is invalid
+func userIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "invalid user ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// userError maps user management errors to responses
+func userError(c *gin.Context, err error, action string) {
+	if planError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		apierror.Write(c, apierror.Wrap(apierror.CodeNotFound, err))
+	case errors.Is(err, services.ErrManageSelf):
+		apierror.Write(c, apierror.Wrap(apierror.CodeForbidden, err))
+	case errors.Is(err, services.ErrAlreadyActive), errors.Is(err, services.ErrAlreadyInactive):
+		apierror.Write(c, apierror.Wrap(apierror.CodeConflict, err))
+	case errors.Is(err, services.ErrInvalidRole), errors.Is(err, services.ErrInvalidLocation):
+		apierror.Write(c, apierror.Wrap(apierror.CodeInvalidRequest, err))
+	default:
+		apierror.Write(c, apierror.Wrap(apierror.CodeInvalidRequest, fmt.Errorf("failed to %s: %w", action, err)))
+	}
+}
+
+// ListUsers lists the accounts of the admin's society.
+// Supports ?role=, ?status=active|deactivated, ?q= (name or email), ?limit= and ?offset=.
+func (uc *UserController) ListUsers(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	filter := services.UserFilter{
+		Role:   c.Query("role"),
+		Status: c.Query("status"),
+		Search: c.Query("q"),
+	}
+	filter.Limit, _ = strconv.Atoi(c.Query("limit"))
+	filter.Offset, _ = strconv.Atoi(c.Query("offset"))
+
+	users, total, err := uc.Users.List(c.Request.Context(), user.SocietyID, filter)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch users"))
+		return
+	}
+
+	c.JSON(200, gin.H{"users": dto.NewUserResponses(users), "total": total})
+}
+
+// GetUser returns one account of the admin's society
+func (uc *UserController) GetUser(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	target, err := uc.Users.Find(c.Request.Context(), user.SocietyID, userID)
+	if err != nil {
+		userError(c, err, "fetch user")
+		return
+	}
+
+	c.JSON(200, dto.NewUserResponse(target))
+}
+
+// UpdateUser changes a user's name, role or unit
+func (uc *UserController) UpdateUser(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Name      *string `json:"name"`
+		Role      *string `json:"role"`
+		UnitID    *uint   `json:"unit_id"`
+		ClearUnit bool    `json:"clear_unit"`
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apierror.Write(c, apierror.Validation(err))
+		return
+	}
+
+	updated, released, err := uc.Users.Update(c.Request.Context(), user, userID, services.UserChanges{
+		Name:      body.Name,
+		Role:      body.Role,
+		UnitID:    body.UnitID,
+		ClearUnit: body.ClearUnit,
+	})
+	if err != nil {
+		userError(c, err, "update user")
+		return
+	}
+
+	c.JSON(200, gin.H{"user": dto.NewUserResponse(updated), "complaints": released})
+}
+
+// DeactivateUser blocks a user from signing in; a staff member's open complaints are reassigned
+func (uc *UserController) DeactivateUser(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	updated, released, err := uc.Users.Deactivate(c.Request.Context(), user, userID)
+	if err != nil {
+		userError(c, err, "deactivate user")
+		return
+	}
+
+	c.JSON(200, gin.H{"user": dto.NewUserResponse(updated), "complaints": released})
+}
+
+// ReactivateUser lets a deactivated user sign in again
+func (uc *UserController) ReactivateUser(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	updated, err := uc.Users.Reactivate(c.Request.Context(), user, userID)
+	if err != nil {
+		userError(c, err, "reactivate user")
+		return
+	}
+
+	c.JSON(200, dto.NewUserResponse(updated))
+}
+
+// ResetUserPassword sets a new password, or emails a reset link when no password is given
+func (uc *UserController) ResetUserPassword(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Password string `json:"password" binding:"omitempty,min=8"`
+	}
+
+	// An empty body means "send a reset link"
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			apierror.Write(c, apierror.Validation(err))
+			return
+		}
+	}
+
+	sent, err := uc.Users.ResetPassword(c.Request.Context(), user, userID, body.Password)
+	if err != nil {
+		userError(c, err, "reset password")
+		return
+	}
+
+	if sent {
+		c.JSON(200, gin.H{"message": "password reset link sent"})
+		return
+	}
+	c.JSON(200, gin.H{"message": "password updated"})
+}
+
+// DeleteUser removes an account; a staff member's open complaints are reassigned
+func (uc *UserController) DeleteUser(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	released, err := uc.Users.Delete(c.Request.Context(), user, userID)
+	if err != nil {
+		userError(c, err, "delete user")
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "user deleted", "complaints": released})
+}
+
+// GetAuditLogs lists admin actions in the society, newest first.
+// Supports ?action=, ?actor_id=, ?target_id=, ?since=YYYY-MM-DD and ?limit=.
+func (uc *UserController) GetAuditLogs(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	var filter services.AuditFilter
+	filter.Action = c.Query("action")
+	if actor, err := strconv.ParseUint(c.Query("actor_id"), 10, 32); err == nil {
+		filter.ActorID = uint(actor)
+	}
+	if target, err := strconv.ParseUint(c.Query("target_id"), 10, 32); err == nil {
+		filter.TargetID = uint(target)
+	}
+	if since := c.Query("since"); since != "" {
+		t, err := time.Parse("2006-01-02", since)
+		if err != nil {
+			apierror.Write(c, apierror.New(apierror.CodeInvalidRequest, "since must be a date like 2025-01-31"))
+			return
+		}
+		filter.Since = &t
+	}
+	filter.Limit, _ = strconv.Atoi(c.Query("limit"))
+
+	entries, err := services.ListAudit(c.Request.Context(), user.SocietyID, filter)
+	if err != nil {
+		apierror.Write(c, apierror.Internal(err, "failed to fetch audit logs"))
+		return
+	}
+
+	c.JSON(200, entries)
+}
@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+)
+
+// ComplaintResponse is a complaint as returned by the API
+type ComplaintResponse struct {
+	ID               uint       `json:"id"`
+	Title            string     `json:"title"`
+	Description      string     `json:"description"`
+	Status           string     `json:"status"`
+	ResidentID       uint       `json:"resident_id"`
+	StaffID          *uint      `json:"staff_id,omitempty"`
+	SocietyID        uint       `json:"society_id"`
+	CategoryID       uint       `json:"category_id"`
+	LocationID       *uint      `json:"location_id,omitempty"`
+	Priority         string     `json:"priority"`
+	ProposedPriority string     `json:"proposed_priority,omitempty"`
+	IsEmergency      bool       `json:"is_emergency"`
+	DueAt            *time.Time `json:"due_at,omitempty"`
+	AssignedAt       *time.Time `json:"assigned_at,omitempty"`
+	FirstAssignedAt  *time.Time `json:"first_assigned_at,omitempty"`
+	EscalationLevel  int        `json:"escalation_level"`
+	EscalatedAt      *time.Time `json:"escalated_at,omitempty"`
+	ResolvedAt       *time.Time `json:"resolved_at,omitempty"`
+	ReopenCount      int        `json:"reopen_count"`
+	ReopenedAt       *time.Time `json:"reopened_at,omitempty"`
+	DuplicateOfID    *uint      `json:"duplicate_of_id,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// ComplaintDetails is a complaint with the names of the people and category involved
+type ComplaintDetails struct {
+	ComplaintResponse
+	ResidentName string  `json:"resident_name"`
+	StaffName    *string `json:"staff_name,omitempty"`
+	CategoryName string  `json:"category_name"`
+}
+
+// NewComplaintResponse maps a complaint model to its API shape
+func NewComplaintResponse(c *models.Complaint) ComplaintResponse {
+	return ComplaintResponse{
+		ID:               c.ID,
+		Title:            c.Title,
+		Description:      c.Description,
+		Status:           c.Status,
+		ResidentID:       c.ResidentID,
+		StaffID:          c.StaffID,
+		SocietyID:        c.SocietyID,
+		CategoryID:       c.CategoryID,
+		LocationID:       c.LocationID,
+		Priority:         c.Priority,
+		ProposedPriority: c.ProposedPriority,
+		IsEmergency:      c.IsEmergency,
+		DueAt:            c.DueAt,
+		AssignedAt:       c.AssignedAt,
+		FirstAssignedAt:  c.FirstAssignedAt,
+		EscalationLevel:  c.EscalationLevel,
+		EscalatedAt:      c.EscalatedAt,
+		ResolvedAt:       c.ResolvedAt,
+		ReopenCount:      c.ReopenCount,
+		ReopenedAt:       c.ReopenedAt,
+		DuplicateOfID:    c.DuplicateOfID,
+		CreatedAt:        c.CreatedAt,
+		UpdatedAt:        c.UpdatedAt,
+	}
+}
+
+// NewComplaintResponses maps a list of complaints, never returning nil
+func NewComplaintResponses(complaints []models.Complaint) []ComplaintResponse {
+	response := make([]ComplaintResponse, len(complaints))
+	for i := range complaints {
+		response[i] = NewComplaintResponse(&complaints[i])
+	}
+	return response
+}
@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+)
+
+// responseTypes lists every response DTO; add new ones here
+var responseTypes = []interface{}{
+	UserResponse{},
+	ComplaintResponse{},
+	ComplaintDetails{},
+	FeedbackResponse{},
+	FeedbackDetails{},
+	NotificationResponse{},
+}
+
+func TestResponseTypesHaveNoPasswordField(t *testing.T) {
+	for _, v := range responseTypes {
+		checkNoPasswordField(t, reflect.TypeOf(v), reflect.TypeOf(v).Name())
+	}
+}
+
+func checkNoPasswordField(t *testing.T, typ reflect.Type, path string) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			checkNoPasswordField(t, field.Type, path+"."+field.Name)
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if strings.Contains(strings.ToLower(field.Name+" "+name), "password") {
+			t.Errorf("%s.%s exposes a password field", path, field.Name)
+		}
+	}
+}
+
+func TestNewUserResponseDropsPassword(t *testing.T) {
+	now := time.Now()
+	user := models.User{ID: 7, Name: "Asha", Email: "asha@example.com", Password: "$2a$12$secret", Role: "user", DeactivatedAt: &now}
+
+	encoded, err := json.Marshal(NewUserResponse(&user))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(encoded), "secret") || strings.Contains(string(encoded), "password") {
+		t.Fatalf("user response leaks the password: %s", encoded)
+	}
+	if !strings.Contains(string(encoded), `"deactivated_at"`) {
+		t.Fatalf("user response lost deactivated_at: %s", encoded)
+	}
+}
+
+func TestRegisterRequestKeepsPasswordForHashing(t *testing.T) {
+	var req RegisterRequest
+	if err := json.Unmarshal([]byte(`{"name":"Asha","email":"asha@example.com","password":"hunter22","society_id":3}`), &req); err != nil {
+		t.Fatal(err)
+	}
+	if user := req.ToModel(); user.Password != "hunter22" || user.SocietyID != 3 {
+		t.Fatalf("ToModel() = %+v", user)
+	}
+}
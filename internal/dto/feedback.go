@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+)
+
+// FeedbackResponse is a feedback entry as returned by the API
+type FeedbackResponse struct {
+	ID          uint      `json:"id"`
+	ComplaintID uint      `json:"complaint_id"`
+	UserID      uint      `json:"user_id"`
+	StaffID     uint      `json:"staff_id"`
+	Rating      int       `json:"rating"`
+	Comment     string    `json:"comment"`
+	Points      int       `json:"points"`
+	Reversed    bool      `json:"reversed"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// FeedbackDetails is feedback with the complaint title and the names of the people involved
+type FeedbackDetails struct {
+	FeedbackResponse
+	ComplaintTitle string `json:"complaint_title"`
+	UserName       string `json:"user_name"`
+	StaffName      string `json:"staff_name"`
+}
+
+// NewFeedbackResponse maps a feedback model to its API shape
+func NewFeedbackResponse(f *models.Feedback) FeedbackResponse {
+	return FeedbackResponse{
+		ID:          f.ID,
+		ComplaintID: f.ComplaintID,
+		UserID:      f.UserID,
+		StaffID:     f.StaffID,
+		Rating:      f.Rating,
+		Comment:     f.Comment,
+		Points:      f.Points,
+		Reversed:    f.Reversed,
+		CreatedAt:   f.CreatedAt,
+	}
+}
@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+)
+
+// NotificationResponse is a notification as returned by the API and pushed over WebSocket
+type NotificationResponse struct {
+	ID          uint      `json:"id"`
+	UserID      uint      `json:"user_id"`
+	Title       string    `json:"title"`
+	Message     string    `json:"message"`
+	Type        string    `json:"type"`
+	IsRead      bool      `json:"is_read"`
+	ComplaintID *uint     `json:"complaint_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NewNotificationResponse maps a notification model to its API shape
+func NewNotificationResponse(n *models.Notification) NotificationResponse {
+	return NotificationResponse{
+		ID:          n.ID,
+		UserID:      n.UserID,
+		Title:       n.Title,
+		Message:     n.Message,
+		Type:        n.Type,
+		IsRead:      n.IsRead,
+		ComplaintID: n.ComplaintID,
+		CreatedAt:   n.CreatedAt,
+	}
+}
+
+// NewNotificationResponses maps a list of notifications, never returning nil
+func NewNotificationResponses(notifications []models.Notification) []NotificationResponse {
+	response := make([]NotificationResponse, len(notifications))
+	for i := range notifications {
+		response[i] = NewNotificationResponse(&notifications[i])
+	}
+	return response
+}
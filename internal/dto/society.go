@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+)
+
+// SocietyResponse is a society as returned by the platform API
+type SocietyResponse struct {
+	ID          uint       `json:"id"`
+	Name        string     `json:"name"`
+	Address     string     `json:"address"`
+	Plan        string     `json:"plan"`
+	Suspended   bool       `json:"suspended"`
+	SuspendedAt *time.Time `json:"suspended_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// NewSocietyResponse maps a society model to its API shape
+func NewSocietyResponse(s *models.Society) SocietyResponse {
+	return SocietyResponse{
+		ID:          s.ID,
+		Name:        s.Name,
+		Address:     s.Address,
+		Plan:        s.Plan,
+		Suspended:   s.IsSuspended(),
+		SuspendedAt: s.SuspendedAt,
+		CreatedAt:   s.CreatedAt,
+	}
+}
+
+// NewSocietyResponses maps a list of societies, never returning nil
+func NewSocietyResponses(societies []models.Society) []SocietyResponse {
+	response := make([]SocietyResponse, len(societies))
+	for i := range societies {
+		response[i] = NewSocietyResponse(&societies[i])
+	}
+	return response
+}
@@ -0,0 +1,76 @@
+// Package dto holds the request and response shapes of the HTTP API. They are kept apart
+// from the database models so internal columns such as password hashes never reach a client.
+package dto
+
+import (
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+)
+
+// RegisterRequest is the body of POST /auth/register
+type RegisterRequest struct {
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Password  string `json:"password"`
+	Role      string `json:"role"`
+	SocietyID uint   `json:"society_id"`
+	UnitID    *uint  `json:"unit_id"`
+	FCMToken  string `json:"fcm_token"`
+}
+
+// ToModel builds the user to register; the password is hashed by AuthService.Register
+func (r RegisterRequest) ToModel() models.User {
+	return models.User{
+		Name:      r.Name,
+		Email:     r.Email,
+		Password:  r.Password,
+		Role:      r.Role,
+		SocietyID: r.SocietyID,
+		UnitID:    r.UnitID,
+		FCMToken:  r.FCMToken,
+	}
+}
+
+// UserResponse is a user as returned by the API
+type UserResponse struct {
+	ID                  uint       `json:"id"`
+	Name                string     `json:"name"`
+	Email               string     `json:"email"`
+	Role                string     `json:"role"`
+	SocietyID           uint       `json:"society_id"`
+	UnitID              *uint      `json:"unit_id,omitempty"`
+	Phone               string     `json:"phone,omitempty"`
+	AvatarURL           string     `json:"avatar_url,omitempty"`
+	Language            string     `json:"language,omitempty"`
+	Timezone            string     `json:"timezone,omitempty"`
+	DeactivatedAt       *time.Time `json:"deactivated_at,omitempty"`
+	DeletionRequestedAt *time.Time `json:"deletion_requested_at,omitempty"`
+}
+
+// NewUserResponse maps a user model to its API shape
+func NewUserResponse(u *models.User) UserResponse {
+	return UserResponse{
+		ID:                  u.ID,
+		Name:                u.Name,
+		Email:               u.Email,
+		Role:                u.Role,
+		SocietyID:           u.SocietyID,
+		UnitID:              u.UnitID,
+		Phone:               u.Phone,
+		AvatarURL:           u.AvatarURL,
+		Language:            u.Language,
+		Timezone:            u.Timezone,
+		DeactivatedAt:       u.DeactivatedAt,
+		DeletionRequestedAt: u.DeletionRequestedAt,
+	}
+}
+
+// NewUserResponses maps a list of users, never returning nil
+func NewUserResponses(users []models.User) []UserResponse {
+	response := make([]UserResponse, len(users))
+	for i := range users {
+		response[i] = NewUserResponse(&users[i])
+	}
+	return response
+}
@@ -0,0 +1,94 @@
+package export
+
+import (
+	"io"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Summary is the content of a PDF summary report
+type Summary struct {
+	Title       string
+	Subtitle    string
+	GeneratedAt time.Time
+	Sections    []SummarySection
+	Table       *SummaryTable
+}
+
+// SummarySection is a titled list of label/value pairs
+type SummarySection struct {
+	Heading string
+	Rows    [][2]string
+}
+
+// SummaryTable is a small table of detail rows printed after the sections
+type SummaryTable struct {
+	Heading string
+	Header  []string
+	Widths  []float64 // column widths in mm, should add up to 190
+	Rows    [][]string
+}
+
+// WritePDF renders the summary as an A4 PDF report
+func WritePDF(w io.Writer, summary Summary) error {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetMargins(10, 12, 10)
+	pdf.SetAutoPageBreak(true, 12)
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, tr(summary.Title), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	if summary.Subtitle != "" {
+		pdf.CellFormat(0, 5, tr(summary.Subtitle), "", 1, "L", false, 0, "")
+	}
+	pdf.CellFormat(0, 5, "Generated "+summary.GeneratedAt.Format("2006-01-02 15:04 MST"), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	for _, section := range summary.Sections {
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.CellFormat(0, 7, tr(section.Heading), "B", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		for _, row := range section.Rows {
+			pdf.CellFormat(80, 6, tr(row[0]), "", 0, "L", false, 0, "")
+			pdf.CellFormat(0, 6, tr(row[1]), "", 1, "L", false, 0, "")
+		}
+		pdf.Ln(3)
+	}
+
+	if table := summary.Table; table != nil && len(table.Rows) > 0 {
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.CellFormat(0, 7, tr(table.Heading), "", 1, "L", false, 0, "")
+
+		pdf.SetFont("Helvetica", "B", 8)
+		pdf.SetFillColor(230, 230, 230)
+		for i, h := range table.Header {
+			pdf.CellFormat(table.Widths[i], 6, tr(h), "1", 0, "L", true, 0, "")
+		}
+		pdf.Ln(-1)
+
+		pdf.SetFont("Helvetica", "", 8)
+		for _, row := range table.Rows {
+			for i, v := range row {
+				pdf.CellFormat(table.Widths[i], 5, tr(truncate(pdf, v, table.Widths[i]-2)), "1", 0, "L", false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+	}
+
+	return pdf.Output(w)
+}
+
+// truncate shortens s so it fits into width mm at the current font
+func truncate(pdf *gofpdf.Fpdf, s string, width float64) string {
+	if pdf.GetStringWidth(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
@@ -0,0 +1,86 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Export formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+	FormatPDF  = "pdf"
+)
+
+// ErrUnknownFormat is returned for formats that cannot be streamed row by row
+var ErrUnknownFormat = errors.New("format must be csv, xlsx or pdf")
+
+// RowWriter streams tabular rows to an output without holding them in memory
+type RowWriter interface {
+	WriteRow(values []string) error
+	Close() error
+}
+
+// safeCell neutralises a value a spreadsheet would run as a formula by prefixing it with a
+// quote. Values starting with =, +, -, @, a tab or a carriage return are escaped, except plain
+// numbers such as negative points.
+func safeCell(value string) string {
+	if value == "" || !strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return "'" + value
+}
+
+// ContentType returns the MIME type of a format
+func ContentType(format string) string {
+	switch format {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatPDF:
+		return "application/pdf"
+	}
+	return "text/csv"
+}
+
+// NewRowWriter returns a streaming writer for csv or xlsx output
+func NewRowWriter(format string, w io.Writer, sheetName string) (RowWriter, error) {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w, sheetName)
+	}
+	return nil, ErrUnknownFormat
+}
+
+type csvWriter struct {
+	w *csv.Writer
+	n int
+}
+
+func (cw *csvWriter) WriteRow(values []string) error {
+	cells := make([]string, len(values))
+	for i, v := range values {
+		cells[i] = safeCell(v)
+	}
+	if err := cw.w.Write(cells); err != nil {
+		return err
+	}
+	// Flush periodically so large exports reach the client as they are produced
+	cw.n++
+	if cw.n%500 == 0 {
+		cw.w.Flush()
+	}
+	return cw.w.Error()
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
@@ -0,0 +1,161 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSafeCell(t *testing.T) {
+	tests := map[string]string{
+		"":                        "",
+		"Leaking tap":             "Leaking tap",
+		"=HYPERLINK(\"x\",\"y\")": "'=HYPERLINK(\"x\",\"y\")",
+		"+cmd|' /C calc'!A0":      "'+cmd|' /C calc'!A0",
+		"-2+3":                    "'-2+3",
+		"@SUM(A1:A2)":             "'@SUM(A1:A2)",
+		"\tindented":              "'\tindented",
+		"\rreturn":                "'\rreturn",
+		"-8":                      "-8",
+		"+4.5":                    "+4.5",
+		"a=b":                     "a=b",
+	}
+	for in, want := range tests {
+		if got := safeCell(in); got != want {
+			t.Errorf("safeCell(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNewRowWriterUnknownFormat(t *testing.T) {
+	for _, format := range []string{FormatPDF, "json", ""} {
+		if _, err := NewRowWriter(format, io.Discard, "Sheet"); !errors.Is(err, ErrUnknownFormat) {
+			t.Errorf("NewRowWriter(%q) error = %v, want ErrUnknownFormat", format, err)
+		}
+	}
+}
+
+func TestCSVStreamsRows(t *testing.T) {
+	var out bytes.Buffer
+	writer, err := NewRowWriter(FormatCSV, &out, "Complaints")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.WriteRow([]string{"ID", "Title"}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < 500; i++ {
+		if err := writer.WriteRow([]string{strconv.Itoa(i), "=1+1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Rows are flushed to the output in batches, before the export finishes
+	if out.Len() == 0 {
+		t.Fatal("expected the first batch of rows to be flushed before Close")
+	}
+	if err := writer.WriteRow([]string{"500", "last"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 501 {
+		t.Fatalf("expected 501 records, got %d", len(records))
+	}
+	if records[1][1] != "'=1+1" || records[500][1] != "last" {
+		t.Errorf("unexpected records %q and %q", records[1], records[500])
+	}
+}
+
+func TestXLSXWritesWorkbook(t *testing.T) {
+	var out bytes.Buffer
+	writer, err := NewRowWriter(FormatXLSX, &out, "Feedback & Ratings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := [][]string{{"ID", "Comment"}, {"1", "<b>great</b>"}, {"2", "@SUM(A1:A9)"}, {"3", "-5"}}
+	for _, row := range rows {
+		if err := writer.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := map[string]string{}
+	for _, f := range archive.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(r)
+		r.Close()
+		parts[f.Name] = string(content)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/_rels/workbook.xml.rels", "xl/workbook.xml", "xl/worksheets/sheet1.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("workbook has no %s", name)
+		}
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `name="Feedback &amp; Ratings"`) {
+		t.Errorf("sheet name not escaped: %s", parts["xl/workbook.xml"])
+	}
+
+	var sheet struct {
+		Rows []struct {
+			R     int      `xml:"r,attr"`
+			Cells []string `xml:"c>is>t"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.Unmarshal([]byte(parts["xl/worksheets/sheet1.xml"]), &sheet); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"ID", "Comment"}, {"1", "<b>great</b>"}, {"2", "'@SUM(A1:A9)"}, {"3", "-5"}}
+	if len(sheet.Rows) != len(want) {
+		t.Fatalf("expected %d rows, got %d", len(want), len(sheet.Rows))
+	}
+	for i, row := range sheet.Rows {
+		if row.R != i+1 || strings.Join(row.Cells, "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d = %d %q, want %d %q", i, row.R, row.Cells, i+1, want[i])
+		}
+	}
+}
+
+func TestWritePDF(t *testing.T) {
+	var out bytes.Buffer
+	err := WritePDF(&out, Summary{
+		Title:       "Complaints",
+		GeneratedAt: time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC),
+		Sections:    []SummarySection{{Heading: "By Status", Rows: [][2]string{{"pending", "3"}}}},
+		Table: &SummaryTable{
+			Heading: "Latest",
+			Header:  []string{"ID", "Title"},
+			Widths:  []float64{20, 170},
+			Rows:    [][]string{{"1", strings.Repeat("a very long title ", 30)}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(out.Bytes(), []byte("%PDF-")) {
+		t.Errorf("expected a PDF document, got %q", out.Bytes()[:min(out.Len(), 16)])
+	}
+}
@@ -0,0 +1,101 @@
+// Package logging sets up the structured JSON logger and carries the request ID through
+// contexts, so every line logged while serving a request, including the notifications it
+// triggers and the SQL it runs, can be traced back to it.
+//
+// Log through the slog package functions with the request's context, for example
+//
+//	slog.ErrorContext(ctx, "failed to notify staff", "staff_id", id, "error", err)
+//
+// and the request ID is added automatically, together with the trace and span IDs when the
+// request is traced.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Redacted replaces the value of sensitive attributes
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are matched against the lower-cased attribute key; any key containing one of
+// them is redacted, so "password", "new_password" and "smtp_password" are all covered
+var sensitiveKeys = []string{"password", "secret", "token", "authorization", "cookie"}
+
+// IsSensitive reports whether a value logged or echoed under key must be redacted
+func IsSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// New returns a logger writing one JSON object per line to w, from level up. Attributes with
+// sensitive keys are redacted and the request ID in the context is added to every record.
+func New(w io.Writer, level slog.Leveler) *slog.Logger {
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if IsSensitive(a.Key) {
+				return slog.String(a.Key, Redacted)
+			}
+			return a
+		},
+	})
+	return slog.New(contextHandler{handler})
+}
+
+// contextHandler adds the request ID and the trace span carried by the context to each record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		r.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// RequestIDHeader carries the request ID in both directions
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or "" outside a request
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID returns a random 128-bit ID in hex
+func NewRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
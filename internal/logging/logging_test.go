@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestRedactsSensitiveAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo)
+
+	logger.Info("login", "email", "a@example.com", "password", "hunter2", "new_password", "hunter3",
+		slog.Group("smtp", "smtp_password", "s3cret"), "Authorization", "Bearer abc", "invite_token", "xyz")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("log line is not JSON: %v\n%s", err, buf.String())
+	}
+	for _, key := range []string{"password", "new_password", "Authorization", "invite_token"} {
+		if record[key] != Redacted {
+			t.Errorf("%s = %v, want it redacted", key, record[key])
+		}
+	}
+	if smtp := record["smtp"].(map[string]any); smtp["smtp_password"] != Redacted {
+		t.Errorf("grouped smtp_password = %v, want it redacted", smtp["smtp_password"])
+	}
+	if record["email"] != "a@example.com" {
+		t.Errorf("email = %v, want it logged as is", record["email"])
+	}
+	for _, secret := range []string{"hunter2", "hunter3", "s3cret", "abc", "xyz"} {
+		if bytes.Contains(buf.Bytes(), []byte(secret)) {
+			t.Errorf("log line contains %q: %s", secret, buf.String())
+		}
+	}
+}
+
+func TestAddsRequestIDFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo).With("component", "test")
+
+	logger.InfoContext(WithRequestID(context.Background(), "req-1"), "inside")
+	logger.InfoContext(context.Background(), "outside")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %s", len(lines), buf.String())
+	}
+	var inside, outside map[string]any
+	json.Unmarshal(lines[0], &inside)
+	json.Unmarshal(lines[1], &outside)
+	if inside["request_id"] != "req-1" || inside["component"] != "test" {
+		t.Errorf("expected request_id and component on %v", inside)
+	}
+	if _, ok := outside["request_id"]; ok {
+		t.Errorf("expected no request_id outside a request, got %v", outside)
+	}
+}
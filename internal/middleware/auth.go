@@ -1,31 +1,60 @@
 package middleware
 
 import (
+    "github.com/VinVorteX/flashtrack/internal/apierror"
     "github.com/VinVorteX/flashtrack/internal/repository"
+    "github.com/VinVorteX/flashtrack/internal/services"
     "github.com/VinVorteX/flashtrack/internal/utils"
     "github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware authenticates the bearer token, signed with secret, and loads the user and
+// their permissions, resolved by roles, into the context
+func AuthMiddleware(secret string, roles *services.RoleService) gin.HandlerFunc {
     return func(c *gin.Context) {
         token := c.GetHeader("Authorization")
 
-        userFromToken, err := utils.ParseJWT(token)
+        userFromToken, err := utils.ParseJWT(token, secret)
         if err != nil {
-            c.JSON(401, gin.H{"error": "invalid token"})
-            c.Abort()
+            apierror.Write(c, apierror.New(apierror.CodeUnauthorized, "invalid token"))
             return
         }
 
         // Fetch full user data from database to get role
         user, err := repository.FindUserByEmail(userFromToken.Email)
         if err != nil {
-            c.JSON(401, gin.H{"error": "user not found"})
-            c.Abort()
+            apierror.Write(c, apierror.New(apierror.CodeUnauthorized, "user not found"))
+            return
+        }
+
+        // Deactivation takes effect immediately, even for tokens issued earlier
+        if !user.IsActive() {
+            apierror.Write(c, apierror.New(apierror.CodeAccountDeactivated, services.ErrAccountDeactivated.Error()))
+            return
+        }
+
+        // Suspended societies are locked out, except for platform support sessions
+        if user.ImpersonatorID = userFromToken.ImpersonatorID; user.ImpersonatorID == nil {
+            suspended, err := services.IsSocietySuspended(user.SocietyID)
+            if err != nil {
+                apierror.Write(c, apierror.Internal(err, "failed to check society status"))
+                return
+            }
+            if suspended {
+                apierror.Write(c, apierror.New(apierror.CodeSocietySuspended, services.ErrSocietySuspended.Error()))
+                return
+            }
+        }
+
+        // Resolve the role once so handlers and RequirePermission can check permissions
+        permissions, err := roles.Permissions(c.Request.Context(), &user)
+        if err != nil {
+            apierror.Write(c, apierror.Internal(err, "failed to load permissions"))
             return
         }
 
         c.Set("user", &user)
+        c.Set("permissions", permissions)
         c.Next()
     }
 }
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"runtime/debug"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/apierror"
+	"github.com/VinVorteX/flashtrack/internal/logging"
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// validRequestID accepts IDs from a proxy or client that are safe to log and echo
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID takes the request ID from the X-Request-ID header, or generates one, stores it in
+// the request context for logging and echoes it in the response
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(logging.RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = logging.NewRequestID()
+		}
+
+		c.Header(logging.RequestIDHeader, id)
+		c.Set("request_id", id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// AccessLog writes one record per request once it has been served: the route, status, latency
+// and, for authenticated requests, who made it. Server errors are logged at error level.
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("route", c.FullPath()),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", c.Writer.Status()),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", c.Writer.Size()),
+			slog.String("client_ip", c.ClientIP()),
+		}
+		if query := redactQuery(c.Request.URL.Query()); query != "" {
+			attrs = append(attrs, slog.String("query", query))
+		}
+		if value, ok := c.Get("user"); ok {
+			user := value.(*models.User)
+			attrs = append(attrs, slog.Uint64("user_id", uint64(user.ID)), slog.Uint64("society_id", uint64(user.SocietyID)))
+			if user.ImpersonatorID != nil {
+				attrs = append(attrs, slog.Uint64("impersonator_id", uint64(*user.ImpersonatorID)))
+			}
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", c.Errors.String()))
+		}
+
+		level := slog.LevelInfo
+		if c.Writer.Status() >= 500 {
+			level = slog.LevelError
+		}
+		slog.LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// Recovery answers a panicking request with a generic internal_error problem and logs the panic
+// with its stack, in place of gin's plain-text recovery output. Neither the panic value nor the
+// stack reaches the client.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, err any) {
+		slog.ErrorContext(c.Request.Context(), "panic serving request", "error", err, "stack", string(debug.Stack()))
+		apierror.Write(c, apierror.Internal(fmt.Errorf("panic: %v", err), "internal server error"))
+	})
+}
+
+// redactQuery encodes the query string with the values of sensitive parameters redacted
+func redactQuery(query url.Values) string {
+	for key := range query {
+		if logging.IsSensitive(key) {
+			query[key] = []string{logging.Redacted}
+		}
+	}
+	return query.Encode()
+}
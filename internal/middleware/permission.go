@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/VinVorteX/flashtrack/internal/apierror"
+	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission restricts access to users whose role grants every one of perms
+func RequirePermission(perms ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granted := c.MustGet("permissions").(services.PermissionSet)
+
+		if !granted.Has(perms...) {
+			apierror.Write(c, apierror.New(apierror.CodePermissionDenied, "access denied: insufficient permissions"))
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"errors"
+
+	"github.com/VinVorteX/flashtrack/internal/apierror"
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireFeature restricts access to societies whose plan, looked up through plans, includes feature
+func RequireFeature(plans *services.PlanService, feature string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := c.MustGet("user").(*models.User)
+
+		if err := plans.RequireFeature(c.Request.Context(), user.SocietyID, feature); err != nil {
+			var featureErr *services.FeatureError
+			if errors.As(err, &featureErr) {
+				apierror.Write(c, apierror.New(apierror.CodeFeatureNotInPlan, err.Error()).
+					With("feature", featureErr.Feature).
+					With("plan", featureErr.Plan))
+			} else {
+				apierror.Write(c, apierror.Internal(err, "failed to check plan"))
+			}
+			return
+		}
+
+		c.Next()
+	}
+}
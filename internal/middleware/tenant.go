@@ -1,14 +1,29 @@
 package middleware
 
 import (
+	"github.com/VinVorteX/flashtrack/internal/apierror"
 	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/pkg/database"
 	"github.com/gin-gonic/gin"
 )
 
+// TenantMiddleware stores the user's society and a database session scoped to it.
+// Handlers read and write through c.MustGet("db") so another society's rows stay out of reach,
+// both through the query filters and through Postgres row-level security.
 func TenantMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		u := c.MustGet("user").(*models.User)
 		c.Set("society_id", u.SocietyID)
+
+		db, release, err := database.TenantSession(c.Request.Context(), u.SocietyID)
+		if err != nil {
+			apierror.Write(c, apierror.Internal(err, "failed to open database session"))
+			return
+		}
+		defer release()
+
+		c.Set("db", db)
+		c.Request = c.Request.WithContext(database.WithSession(c.Request.Context(), db))
 		c.Next()
 	}
 }
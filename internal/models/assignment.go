@@ -0,0 +1,18 @@
+package models
+
+// StaffCategory maps a staff member to a category they are skilled to handle
+type StaffCategory struct {
+	ID         uint `gorm:"primaryKey" json:"id"`
+	StaffID    uint `gorm:"uniqueIndex:idx_staff_category" json:"staff_id"`
+	CategoryID uint `gorm:"uniqueIndex:idx_staff_category" json:"category_id"`
+	SocietyID  uint `gorm:"index" json:"society_id"`
+}
+
+// AssignmentCursor remembers the last staff member picked by round-robin
+// for a category so the next complaint goes to the following staff member
+type AssignmentCursor struct {
+	ID          uint `gorm:"primaryKey"`
+	SocietyID   uint `gorm:"uniqueIndex:idx_assignment_cursor"`
+	CategoryID  uint `gorm:"uniqueIndex:idx_assignment_cursor"`
+	LastStaffID uint
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// AuditLog records an administrative action for later review
+type AuditLog struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	SocietyID      uint      `gorm:"index" json:"society_id"`
+	ActorID        uint      `gorm:"index" json:"actor_id"`
+	ImpersonatorID *uint     `json:"impersonator_id,omitempty"` // platform super-admin acting as the actor
+	Action         string    `json:"action"`                    // e.g. user.role_changed, user.deactivated
+	TargetType     string    `json:"target_type"`
+	TargetID       uint      `json:"target_id"`
+	Details        string    `json:"details,omitempty"` // JSON encoded before/after values
+	CreatedAt      time.Time `gorm:"index" json:"created_at"`
+}
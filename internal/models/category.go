@@ -1,8 +1,9 @@
 package models
 
 type Category struct {
-    ID        uint `gorm:"primaryKey"`
-    Name      string
-    SocietyID uint
-    SLAHours  int
+    ID              uint `gorm:"primaryKey"`
+    Name            string
+    SocietyID       uint
+    SLAHours        int
+    DefaultPriority string // priority given to new complaints in this category
 }
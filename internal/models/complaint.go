@@ -3,14 +3,37 @@ package models
 import "time"
 
 type Complaint struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Status      string    `json:"status"`
-	ResidentID  uint      `json:"resident_id"`
-	StaffID     *uint     `json:"staff_id,omitempty"`
-	SocietyID   uint      `json:"society_id"`
-	CategoryID  uint      `json:"category_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	Title            string     `json:"title"`
+	Description      string     `json:"description"`
+	Status           string     `json:"status"`
+	ResidentID       uint       `json:"resident_id"`
+	StaffID          *uint      `json:"staff_id,omitempty"`
+	SocietyID        uint       `json:"society_id"`
+	CategoryID       uint       `json:"category_id"`
+	LocationID       *uint      `gorm:"index" json:"location_id,omitempty"`   // unit or common area
+	Priority         string     `gorm:"default:medium;index" json:"priority"` // low, medium, high, urgent
+	ProposedPriority string     `json:"proposed_priority,omitempty"`          // priority suggested by the resident
+	IsEmergency      bool       `gorm:"default:false" json:"is_emergency"`
+	DueAt            *time.Time `json:"due_at,omitempty"` // SLA deadline for resolution
+	AssignedAt       *time.Time `json:"assigned_at,omitempty"`
+	FirstAssignedAt  *time.Time `json:"first_assigned_at,omitempty"`       // kept through reassignment, for time-to-assign
+	EscalationLevel  int        `gorm:"default:0" json:"escalation_level"` // highest escalation policy level reached since the last assignment or reopening
+	EscalatedAt      *time.Time `json:"escalated_at,omitempty"`
+	ResolvedAt       *time.Time `json:"resolved_at,omitempty"`
+	ReopenCount      int        `gorm:"default:0" json:"reopen_count"`
+	ReopenedAt       *time.Time `json:"reopened_at,omitempty"`
+	DuplicateOfID    *uint      `gorm:"index" json:"duplicate_of_id,omitempty"` // primary complaint this one was merged into
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// ComplaintReopen records a resident reopening a resolved complaint
+type ComplaintReopen struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	ComplaintID    uint      `gorm:"index" json:"complaint_id"`
+	UserID         uint      `json:"user_id"`
+	Reason         string    `json:"reason"`
+	PointsReversed int       `json:"points_reversed"` // staff points taken back from the earlier feedback
+	CreatedAt      time.Time `json:"created_at"`
 }
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// EscalationPolicy fires when an open complaint has been unassigned or unresolved
+// for longer than AfterHours. Policies raise a complaint to Level in ascending order.
+type EscalationPolicy struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	SocietyID  uint      `gorm:"index" json:"society_id"`
+	CategoryID *uint     `json:"category_id,omitempty"` // nil applies to every category
+	Trigger    string    `json:"trigger"`               // unassigned, unresolved
+	AfterHours int       `json:"after_hours"`
+	Level      int       `json:"level"`
+	Action     string    `json:"action"`                // notify_admin, notify_role, reassign
+	TargetRole string    `json:"target_role,omitempty"` // role notified by notify_role
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ComplaintEscalation records each time a policy escalated a complaint
+type ComplaintEscalation struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ComplaintID uint      `gorm:"index" json:"complaint_id"`
+	PolicyID    uint      `json:"policy_id"`
+	Level       int       `json:"level"`
+	Action      string    `json:"action"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -10,6 +10,7 @@ type Feedback struct {
 	Rating      int       `json:"rating"` // 1-5 stars
 	Comment     string    `json:"comment"`
 	Points      int       `json:"points"` // Points awarded to staff
+	Reversed    bool      `gorm:"default:false" json:"reversed"` // Points taken back after the complaint was reopened
 	CreatedAt   time.Time `json:"created_at"`
 }
 
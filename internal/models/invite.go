@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Invite lets an imported user pick their own password instead of receiving one in plaintext
+type Invite struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"index" json:"user_id"`
+	TokenHash  string     `gorm:"uniqueIndex" json:"-"` // sha256 of the token sent by email
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Location is a node in a society's location tree: building -> floor -> unit,
+// with common areas attached to the society, a building or a floor
+type Location struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	SocietyID uint      `gorm:"index" json:"society_id"`
+	ParentID  *uint     `gorm:"index" json:"parent_id,omitempty"`
+	Type      string    `json:"type"` // building, floor, unit, common_area
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Role is a society-defined set of permissions, such as a committee member or security head.
+// The built-in user, staff and admin roles are defined in code; User.Role holds the role name.
+type Role struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	SocietyID   uint      `gorm:"uniqueIndex:idx_society_role" json:"society_id"`
+	Name        string    `gorm:"uniqueIndex:idx_society_role" json:"name"`
+	Description string    `json:"description"`
+	Permissions string    `json:"permissions"` // comma separated permission names
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
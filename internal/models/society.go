@@ -1,8 +1,29 @@
 package models
 
+import "time"
+
 type Society struct {
-    ID      uint   `gorm:"primaryKey"`
-    Name    string
-    Address string
-    Plan    string
-}
\ No newline at end of file
+    ID                 uint   `gorm:"primaryKey"`
+    Name               string
+    Address            string
+    Plan               string
+    AssignmentStrategy string // manual, round_robin, least_workload, highest_rating, skill_based
+    ReopenWindowHours  int    // how long residents may reopen a resolved complaint, 0 uses the default
+    Timezone           string // IANA zone staff shifts are read in, empty uses UTC
+    SuspendedAt        *time.Time // set by a platform super-admin; members cannot sign in while suspended
+    CreatedAt          time.Time
+}
+
+// IsSuspended reports whether the society has been suspended by the platform
+func (s *Society) IsSuspended() bool {
+    return s.SuspendedAt != nil
+}
+
+// Location returns the society's time zone, or UTC when none or an unknown one is set
+func (s *Society) Location() *time.Location {
+    loc, err := time.LoadLocation(s.Timezone)
+    if err != nil {
+        return time.UTC
+    }
+    return loc
+}
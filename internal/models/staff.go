@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// StaffProfile holds the working schedule and capacity of a staff member.
+// Category skills are stored as StaffCategory rows.
+type StaffProfile struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	StaffID       uint   `gorm:"uniqueIndex" json:"staff_id"`
+	SocietyID     uint   `gorm:"index" json:"society_id"`
+	ShiftStart    string `json:"shift_start"`    // "HH:MM", empty means on duty all day
+	ShiftEnd      string `json:"shift_end"`      // "HH:MM", may be earlier than ShiftStart for night shifts
+	WorkingDays   string `json:"working_days"`   // comma separated weekdays (0 = Sunday), empty means every day
+	MaxConcurrent int    `json:"max_concurrent"` // max open complaints, 0 means unlimited
+}
+
+// StaffLeave is a leave entry for one staff member, or a society-wide holiday when StaffID is nil
+type StaffLeave struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	StaffID   *uint     `gorm:"index" json:"staff_id,omitempty"`
+	SocietyID uint      `gorm:"index" json:"society_id"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"` // inclusive
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
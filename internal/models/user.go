@@ -1,11 +1,30 @@
 package models
 
+import "time"
+
 type User struct {
 	ID        uint   `gorm:"primaryKey" json:"id"`
 	Name      string `json:"name"`
 	Email     string `gorm:"unique" json:"email"`
-	Password  string `json:"password,omitempty"`
+	Password  string `json:"-"` // bcrypt hash, never serialised
 	Role      string `json:"role"`
 	SocietyID uint   `json:"society_id"`
+	UnitID    *uint  `json:"unit_id,omitempty"`   // flat the resident lives in
 	FCMToken  string `json:"fcm_token,omitempty"` // For push notifications
+
+	Phone     string `json:"phone,omitempty"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+	Language  string `json:"language,omitempty"` // preferred language, e.g. "en" or "hi-IN"
+	Timezone  string `json:"timezone,omitempty"` // IANA zone, e.g. "Asia/Kolkata"
+
+	DeactivatedAt       *time.Time `json:"deactivated_at,omitempty"`        // set when an admin disables the account
+	DeletionRequestedAt *time.Time `json:"deletion_requested_at,omitempty"` // set when the user asks for their account to be deleted
+
+	// ImpersonatorID is the platform super-admin acting as this user, taken from the token
+	ImpersonatorID *uint `gorm:"-" json:"-"`
+}
+
+// IsActive reports whether the user may sign in
+func (u *User) IsActive() bool {
+	return u.DeactivatedAt == nil
 }
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AssignmentRepository keeps the round-robin position of each society and category
+type AssignmentRepository interface {
+	// AdvanceCursor locks the cursor, passes next the staff member picked last (0 the first
+	// time) and stores the one it returns, so concurrent complaints never pick the same person
+	AdvanceCursor(ctx context.Context, societyID, categoryID uint, next func(lastStaffID uint) uint) error
+}
+
+// NewAssignmentRepository returns the GORM AssignmentRepository
+func NewAssignmentRepository() AssignmentRepository {
+	return assignmentRepo{}
+}
+
+type assignmentRepo struct{}
+
+func (assignmentRepo) AdvanceCursor(ctx context.Context, societyID, categoryID uint, next func(lastStaffID uint) uint) error {
+	return database.Session(ctx).Transaction(func(tx *gorm.DB) error {
+		cursor := models.AssignmentCursor{SocietyID: societyID, CategoryID: categoryID}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("society_id = ? AND category_id = ?", societyID, categoryID).
+			FirstOrCreate(&cursor).Error; err != nil {
+			return err
+		}
+
+		cursor.LastStaffID = next(cursor.LastStaffID)
+		return tx.Save(&cursor).Error
+	})
+}
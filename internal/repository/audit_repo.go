@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+)
+
+// AuditRepository stores the audit log. Entries written with a transaction's context are only
+// kept when the audited action commits.
+type AuditRepository interface {
+	Create(ctx context.Context, entry *models.AuditLog) error
+}
+
+// NewAuditRepository returns the GORM AuditRepository
+func NewAuditRepository() AuditRepository {
+	return auditRepo{}
+}
+
+type auditRepo struct{}
+
+func (auditRepo) Create(ctx context.Context, entry *models.AuditLog) error {
+	return database.Session(ctx).Create(entry).Error
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+)
+
+// CategoryRepository reads and updates complaint categories. Categories without a society are
+// shared by every society; they are available to all but owned by none.
+type CategoryRepository interface {
+	// ListAvailable returns the society's own and the shared categories
+	ListAvailable(ctx context.Context, societyID uint) ([]models.Category, error)
+	FindAvailable(ctx context.Context, societyID, id uint) (*models.Category, error)
+	// CountAvailable counts how many of ids are available to the society
+	CountAvailable(ctx context.Context, societyID uint, ids []uint) (int64, error)
+	// ListOwned returns only the society's own categories
+	ListOwned(ctx context.Context, societyID uint) ([]models.Category, error)
+	// FindOwned only finds the society's own categories
+	FindOwned(ctx context.Context, societyID, id uint) (*models.Category, error)
+	Save(ctx context.Context, category *models.Category) error
+}
+
+// NewCategoryRepository returns the GORM CategoryRepository
+func NewCategoryRepository() CategoryRepository {
+	return categoryRepo{}
+}
+
+type categoryRepo struct{}
+
+const availableCategory = "society_id = ? OR society_id = 0 OR society_id IS NULL"
+
+func (categoryRepo) ListAvailable(ctx context.Context, societyID uint) ([]models.Category, error) {
+	var categories []models.Category
+	err := database.Session(ctx).Where(availableCategory, societyID).Order("id").Find(&categories).Error
+	return categories, err
+}
+
+func (categoryRepo) FindAvailable(ctx context.Context, societyID, id uint) (*models.Category, error) {
+	var category models.Category
+	if err := database.Session(ctx).Where("id = ?", id).Where(availableCategory, societyID).First(&category).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &category, nil
+}
+
+func (categoryRepo) CountAvailable(ctx context.Context, societyID uint, ids []uint) (int64, error) {
+	var count int64
+	err := database.Session(ctx).Model(&models.Category{}).Where("id IN ?", ids).Where(availableCategory, societyID).Count(&count).Error
+	return count, err
+}
+
+func (categoryRepo) ListOwned(ctx context.Context, societyID uint) ([]models.Category, error) {
+	var categories []models.Category
+	err := database.Session(ctx).Where("society_id = ?", societyID).Find(&categories).Error
+	return categories, err
+}
+
+func (categoryRepo) FindOwned(ctx context.Context, societyID, id uint) (*models.Category, error) {
+	var category models.Category
+	if err := database.Session(ctx).Where("id = ? AND society_id = ?", id, societyID).First(&category).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &category, nil
+}
+
+func (categoryRepo) Save(ctx context.Context, category *models.Category) error {
+	return database.Session(ctx).Save(category).Error
+}
@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+	"gorm.io/gorm"
+)
+
+// ComplaintFilter selects complaints for listings and exports. Unless All is set only complaints
+// assigned to AssignedTo or reported by ReportedBy match, and nothing matches when both are nil.
+type ComplaintFilter struct {
+	SocietyID         uint
+	All               bool
+	AssignedTo        *uint
+	ReportedBy        *uint
+	ExcludeDuplicates bool
+	LocationIDs       []uint // nil matches every location
+	Order             string // ORDER BY applied before newest first, e.g. services.PriorityOrderSQL
+}
+
+// ComplaintRepository stores complaints, their reopen history and duplicate links
+type ComplaintRepository interface {
+	Find(ctx context.Context, societyID, id uint) (*models.Complaint, error)
+	List(ctx context.Context, filter ComplaintFilter) ([]models.Complaint, error)
+	Titles(ctx context.Context, ids []uint) (map[uint]string, error)
+	Create(ctx context.Context, complaint *models.Complaint) error
+	Save(ctx context.Context, complaint *models.Complaint) error
+
+	// FindUnmerged returns the society's complaints among ids that are not merged and have one of statuses
+	FindUnmerged(ctx context.Context, societyID uint, ids []uint, statuses []string) ([]models.Complaint, error)
+	Duplicates(ctx context.Context, primaryID uint) ([]models.Complaint, error)
+	CountDuplicates(ctx context.Context, primaryID uint) (int64, error)
+	// Repoint moves the duplicates of each of fromIDs onto primaryID
+	Repoint(ctx context.Context, fromIDs []uint, primaryID uint) error
+	// SyncDuplicates copies the primary's status and assignment onto its duplicates
+	SyncDuplicates(ctx context.Context, primary *models.Complaint) error
+
+	// ListUnmerged returns the society's complaints that are not merged and have one of statuses
+	ListUnmerged(ctx context.Context, societyID uint, statuses []string) ([]models.Complaint, error)
+	// Workloads counts the unmerged complaints with one of statuses assigned to each staff member
+	Workloads(ctx context.Context, staffIDs []uint, statuses []string) (map[uint]int64, error)
+	// Release unassigns the staff member's complaints with one of statuses, duplicates included,
+	// puts them back to pending and returns the primaries as they are now
+	Release(ctx context.Context, staffID uint, statuses []string) ([]models.Complaint, error)
+	// SetEscalation stores the complaint's escalation level and time
+	SetEscalation(ctx context.Context, complaint *models.Complaint) error
+
+	CreateReopen(ctx context.Context, reopen *models.ComplaintReopen) error
+}
+
+// NewComplaintRepository returns the GORM ComplaintRepository
+func NewComplaintRepository() ComplaintRepository {
+	return complaintRepo{}
+}
+
+type complaintRepo struct{}
+
+// ComplaintQuery applies the filter to a complaints query on db. Exports build on it to
+// stream rows with their own columns.
+func ComplaintQuery(db *gorm.DB, filter ComplaintFilter) *gorm.DB {
+	query := db.Model(&models.Complaint{}).Where("complaints.society_id = ?", filter.SocietyID)
+
+	if !filter.All {
+		switch {
+		case filter.AssignedTo != nil && filter.ReportedBy != nil:
+			query = query.Where("complaints.staff_id = ? OR complaints.resident_id = ?", *filter.AssignedTo, *filter.ReportedBy)
+		case filter.AssignedTo != nil:
+			query = query.Where("complaints.staff_id = ?", *filter.AssignedTo)
+		case filter.ReportedBy != nil:
+			query = query.Where("complaints.resident_id = ?", *filter.ReportedBy)
+		default:
+			query = query.Where("1 = 0")
+		}
+	}
+
+	if filter.ExcludeDuplicates {
+		query = query.Where("complaints.duplicate_of_id IS NULL")
+	}
+	if filter.LocationIDs != nil {
+		query = query.Where("complaints.location_id IN ?", filter.LocationIDs)
+	}
+	if filter.Order != "" {
+		query = query.Order(filter.Order)
+	}
+
+	return query.Order("complaints.created_at DESC")
+}
+
+func (complaintRepo) Find(ctx context.Context, societyID, id uint) (*models.Complaint, error) {
+	var complaint models.Complaint
+	if err := database.Session(ctx).Where("id = ? AND society_id = ?", id, societyID).First(&complaint).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &complaint, nil
+}
+
+func (complaintRepo) List(ctx context.Context, filter ComplaintFilter) ([]models.Complaint, error) {
+	var complaints []models.Complaint
+	err := ComplaintQuery(database.Session(ctx), filter).Find(&complaints).Error
+	return complaints, err
+}
+
+func (complaintRepo) Titles(ctx context.Context, ids []uint) (map[uint]string, error) {
+	titles := make(map[uint]string, len(ids))
+	if len(ids) == 0 {
+		return titles, nil
+	}
+	var complaints []models.Complaint
+	if err := database.Session(ctx).Select("id", "title").Where("id IN ?", ids).Find(&complaints).Error; err != nil {
+		return nil, err
+	}
+	for _, complaint := range complaints {
+		titles[complaint.ID] = complaint.Title
+	}
+	return titles, nil
+}
+
+func (complaintRepo) Create(ctx context.Context, complaint *models.Complaint) error {
+	return database.Session(ctx).Create(complaint).Error
+}
+
+func (complaintRepo) Save(ctx context.Context, complaint *models.Complaint) error {
+	return database.Session(ctx).Save(complaint).Error
+}
+
+func (complaintRepo) FindUnmerged(ctx context.Context, societyID uint, ids []uint, statuses []string) ([]models.Complaint, error) {
+	var complaints []models.Complaint
+	err := database.Session(ctx).
+		Where("id IN ? AND society_id = ? AND duplicate_of_id IS NULL AND status IN ?", ids, societyID, statuses).
+		Find(&complaints).Error
+	return complaints, err
+}
+
+func (complaintRepo) Duplicates(ctx context.Context, primaryID uint) ([]models.Complaint, error) {
+	var duplicates []models.Complaint
+	err := database.Session(ctx).Where("duplicate_of_id = ?", primaryID).Find(&duplicates).Error
+	return duplicates, err
+}
+
+func (complaintRepo) CountDuplicates(ctx context.Context, primaryID uint) (int64, error) {
+	var count int64
+	err := database.Session(ctx).Model(&models.Complaint{}).Where("duplicate_of_id = ?", primaryID).Count(&count).Error
+	return count, err
+}
+
+func (complaintRepo) Repoint(ctx context.Context, fromIDs []uint, primaryID uint) error {
+	return database.Session(ctx).Model(&models.Complaint{}).Where("duplicate_of_id IN ?", fromIDs).
+		Update("duplicate_of_id", primaryID).Error
+}
+
+func (complaintRepo) SyncDuplicates(ctx context.Context, primary *models.Complaint) error {
+	return database.Session(ctx).Model(&models.Complaint{}).Where("duplicate_of_id = ?", primary.ID).Updates(map[string]interface{}{
+		"status":      primary.Status,
+		"staff_id":    primary.StaffID,
+		"assigned_at": primary.AssignedAt,
+		"resolved_at": primary.ResolvedAt,
+	}).Error
+}
+
+func (complaintRepo) ListUnmerged(ctx context.Context, societyID uint, statuses []string) ([]models.Complaint, error) {
+	var complaints []models.Complaint
+	err := database.Session(ctx).Where("society_id = ? AND status IN ? AND duplicate_of_id IS NULL", societyID, statuses).
+		Find(&complaints).Error
+	return complaints, err
+}
+
+func (complaintRepo) Workloads(ctx context.Context, staffIDs []uint, statuses []string) (map[uint]int64, error) {
+	var rows []struct {
+		StaffID uint
+		Count   int64
+	}
+	err := database.Session(ctx).Model(&models.Complaint{}).
+		Select("staff_id, COUNT(*) AS count").
+		Where("staff_id IN ? AND status IN ? AND duplicate_of_id IS NULL", staffIDs, statuses).
+		Group("staff_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	workloads := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		workloads[row.StaffID] = row.Count
+	}
+	return workloads, nil
+}
+
+func (complaintRepo) Release(ctx context.Context, staffID uint, statuses []string) ([]models.Complaint, error) {
+	var complaints []models.Complaint
+	if err := database.Session(ctx).Where("staff_id = ? AND status IN ? AND duplicate_of_id IS NULL", staffID, statuses).
+		Find(&complaints).Error; err != nil {
+		return nil, err
+	}
+
+	err := database.Session(ctx).Model(&models.Complaint{}).Where("staff_id = ? AND status IN ?", staffID, statuses).
+		Updates(map[string]interface{}{"staff_id": nil, "status": "pending", "assigned_at": nil}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range complaints {
+		complaints[i].StaffID = nil
+		complaints[i].Status = "pending"
+		complaints[i].AssignedAt = nil
+	}
+	return complaints, nil
+}
+
+func (complaintRepo) SetEscalation(ctx context.Context, complaint *models.Complaint) error {
+	return database.Session(ctx).Model(complaint).Updates(map[string]interface{}{
+		"escalation_level": complaint.EscalationLevel,
+		"escalated_at":     complaint.EscalatedAt,
+	}).Error
+}
+
+func (complaintRepo) CreateReopen(ctx context.Context, reopen *models.ComplaintReopen) error {
+	return database.Session(ctx).Create(reopen).Error
+}
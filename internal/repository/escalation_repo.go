@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+)
+
+// EscalationRepository stores escalation policies and the escalations they raised
+type EscalationRepository interface {
+	// Policies returns the society's policies ordered by level
+	Policies(ctx context.Context, societyID uint) ([]models.EscalationPolicy, error)
+	// AllPolicies returns the policies of every society ordered by society and level
+	AllPolicies(ctx context.Context) ([]models.EscalationPolicy, error)
+	CreatePolicy(ctx context.Context, policy *models.EscalationPolicy) error
+	DeletePolicy(ctx context.Context, societyID, id uint) error
+
+	// History returns the complaint's escalations, oldest first
+	History(ctx context.Context, complaintID uint) ([]models.ComplaintEscalation, error)
+	Record(ctx context.Context, escalation *models.ComplaintEscalation) error
+}
+
+// NewEscalationRepository returns the GORM EscalationRepository
+func NewEscalationRepository() EscalationRepository {
+	return escalationRepo{}
+}
+
+type escalationRepo struct{}
+
+func (escalationRepo) Policies(ctx context.Context, societyID uint) ([]models.EscalationPolicy, error) {
+	policies := []models.EscalationPolicy{}
+	err := database.Session(ctx).Where("society_id = ?", societyID).Order("level").Find(&policies).Error
+	return policies, err
+}
+
+func (escalationRepo) AllPolicies(ctx context.Context) ([]models.EscalationPolicy, error) {
+	var policies []models.EscalationPolicy
+	err := database.Session(ctx).Order("society_id, level").Find(&policies).Error
+	return policies, err
+}
+
+func (escalationRepo) CreatePolicy(ctx context.Context, policy *models.EscalationPolicy) error {
+	return database.Session(ctx).Create(policy).Error
+}
+
+func (escalationRepo) DeletePolicy(ctx context.Context, societyID, id uint) error {
+	result := database.Session(ctx).Where("id = ? AND society_id = ?", id, societyID).Delete(&models.EscalationPolicy{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (escalationRepo) History(ctx context.Context, complaintID uint) ([]models.ComplaintEscalation, error) {
+	escalations := []models.ComplaintEscalation{}
+	err := database.Session(ctx).Where("complaint_id = ?", complaintID).Order("created_at").Find(&escalations).Error
+	return escalations, err
+}
+
+func (escalationRepo) Record(ctx context.Context, escalation *models.ComplaintEscalation) error {
+	return database.Session(ctx).Create(escalation).Error
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+	"gorm.io/gorm"
+)
+
+// FeedbackRepository stores residents' ratings and the staff points they award
+type FeedbackRepository interface {
+	Create(ctx context.Context, feedback *models.Feedback) error
+	// FindActive returns the feedback on the complaint's current resolution
+	FindActive(ctx context.Context, complaintID uint) (*models.Feedback, error)
+	// RatedComplaintIDs returns which of complaintIDs the user has active feedback on
+	RatedComplaintIDs(ctx context.Context, userID uint, complaintIDs []uint) ([]uint, error)
+	ListBySociety(ctx context.Context, societyID uint) ([]models.Feedback, error)
+	// Reverse marks the feedback reversed and takes its points back from the staff member
+	Reverse(ctx context.Context, feedback *models.Feedback) error
+
+	// AverageRatings maps each of staffIDs with active feedback to their average rating
+	AverageRatings(ctx context.Context, staffIDs []uint) (map[uint]float64, error)
+
+	StaffPoints(ctx context.Context, staffID uint) (*models.StaffPoints, error)
+	// AwardPoints adds points and a completed task to the staff member's total
+	AwardPoints(ctx context.Context, staffID uint, points int) error
+}
+
+// NewFeedbackRepository returns the GORM FeedbackRepository
+func NewFeedbackRepository() FeedbackRepository {
+	return feedbackRepo{}
+}
+
+type feedbackRepo struct{}
+
+func (feedbackRepo) Create(ctx context.Context, feedback *models.Feedback) error {
+	return database.Session(ctx).Create(feedback).Error
+}
+
+func (feedbackRepo) FindActive(ctx context.Context, complaintID uint) (*models.Feedback, error) {
+	var feedback models.Feedback
+	if err := database.Session(ctx).Where("complaint_id = ? AND reversed = ?", complaintID, false).First(&feedback).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &feedback, nil
+}
+
+func (feedbackRepo) RatedComplaintIDs(ctx context.Context, userID uint, complaintIDs []uint) ([]uint, error) {
+	var ids []uint
+	err := database.Session(ctx).Model(&models.Feedback{}).
+		Where("complaint_id IN ? AND user_id = ? AND reversed = ?", complaintIDs, userID, false).
+		Pluck("complaint_id", &ids).Error
+	return ids, err
+}
+
+func (feedbackRepo) ListBySociety(ctx context.Context, societyID uint) ([]models.Feedback, error) {
+	var feedbacks []models.Feedback
+	err := database.Session(ctx).Joins("JOIN complaints ON feedbacks.complaint_id = complaints.id").
+		Where("complaints.society_id = ?", societyID).
+		Order("feedbacks.created_at DESC").
+		Find(&feedbacks).Error
+	return feedbacks, err
+}
+
+func (feedbackRepo) Reverse(ctx context.Context, feedback *models.Feedback) error {
+	db := database.Session(ctx)
+	if err := db.Model(&models.StaffPoints{}).Where("staff_id = ?", feedback.StaffID).Updates(map[string]interface{}{
+		"total_points":    gorm.Expr("total_points - ?", feedback.Points),
+		"tasks_completed": gorm.Expr("GREATEST(tasks_completed - 1, 0)"),
+	}).Error; err != nil {
+		return err
+	}
+	return db.Model(feedback).Update("reversed", true).Error
+}
+
+func (feedbackRepo) StaffPoints(ctx context.Context, staffID uint) (*models.StaffPoints, error) {
+	var points models.StaffPoints
+	if err := database.Session(ctx).Where("staff_id = ?", staffID).First(&points).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &points, nil
+}
+
+func (feedbackRepo) AwardPoints(ctx context.Context, staffID uint, points int) error {
+	db := database.Session(ctx)
+	var staffPoints models.StaffPoints
+	if err := db.Where("staff_id = ?", staffID).FirstOrCreate(&staffPoints, models.StaffPoints{StaffID: staffID}).Error; err != nil {
+		return err
+	}
+	staffPoints.TotalPoints += points
+	staffPoints.TasksCompleted++
+	return db.Save(&staffPoints).Error
+}
+
+func (feedbackRepo) AverageRatings(ctx context.Context, staffIDs []uint) (map[uint]float64, error) {
+	var rows []struct {
+		StaffID uint
+		Rating  float64
+	}
+	if err := database.Session(ctx).Model(&models.Feedback{}).
+		Select("staff_id, AVG(rating) AS rating").
+		Where("staff_id IN ? AND reversed = ?", staffIDs, false).
+		Group("staff_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	ratings := make(map[uint]float64, len(rows))
+	for _, row := range rows {
+		ratings[row.StaffID] = row.Rating
+	}
+	return ratings, nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+	"gorm.io/gorm"
+)
+
+// InviteRepository stores password-setup invites, which also carry password resets
+type InviteRepository interface {
+	Create(ctx context.Context, invite *models.Invite) error
+	// FindPending returns the unused invite with tokenHash that is still valid at now
+	FindPending(ctx context.Context, tokenHash string, now time.Time) (*models.Invite, error)
+	// Accept marks the invite accepted at now and sets its user's password. It returns
+	// ErrNotFound when the invite was accepted in the meantime.
+	Accept(ctx context.Context, invite *models.Invite, passwordHash string, now time.Time) error
+}
+
+// NewInviteRepository returns the GORM InviteRepository
+func NewInviteRepository() InviteRepository {
+	return inviteRepo{}
+}
+
+type inviteRepo struct{}
+
+func (inviteRepo) Create(ctx context.Context, invite *models.Invite) error {
+	return database.Session(ctx).Create(invite).Error
+}
+
+func (inviteRepo) FindPending(ctx context.Context, tokenHash string, now time.Time) (*models.Invite, error) {
+	var invite models.Invite
+	if err := database.Session(ctx).Where("token_hash = ? AND accepted_at IS NULL AND expires_at > ?", tokenHash, now).
+		First(&invite).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &invite, nil
+}
+
+func (inviteRepo) Accept(ctx context.Context, invite *models.Invite, passwordHash string, now time.Time) error {
+	return database.Session(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Invite{}).Where("id = ? AND accepted_at IS NULL", invite.ID).Update("accepted_at", &now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return tx.Model(&models.User{}).Where("id = ?", invite.UserID).Update("password", passwordHash).Error
+	})
+}
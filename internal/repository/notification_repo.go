@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+)
+
+// NotificationRepository stores users' in-app notifications
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *models.Notification) error
+	// ListForUser returns the user's notifications, newest first
+	ListForUser(ctx context.Context, userID uint) ([]models.Notification, error)
+	MarkRead(ctx context.Context, id, userID uint) error
+}
+
+// NewNotificationRepository returns the GORM NotificationRepository
+func NewNotificationRepository() NotificationRepository {
+	return notificationRepo{}
+}
+
+type notificationRepo struct{}
+
+func (notificationRepo) Create(ctx context.Context, notification *models.Notification) error {
+	return database.Session(ctx).Create(notification).Error
+}
+
+func (notificationRepo) ListForUser(ctx context.Context, userID uint) ([]models.Notification, error) {
+	var notifications []models.Notification
+	err := database.Session(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&notifications).Error
+	return notifications, err
+}
+
+func (notificationRepo) MarkRead(ctx context.Context, id, userID uint) error {
+	return database.Session(ctx).Model(&models.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("is_read", true).Error
+}
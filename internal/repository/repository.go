@@ -0,0 +1,42 @@
+// Package repository holds the data access for the services. Every repository method takes the
+// request context and runs on the database session it carries (see database.WithSession), so
+// handlers that pass c.Request.Context() stay within the user's society and any open transaction.
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/VinVorteX/flashtrack/pkg/database"
+	"gorm.io/gorm"
+)
+
+// ErrNotFound is returned when a lookup matches no row
+var ErrNotFound = errors.New("record not found")
+
+// Transactor runs fn in a database transaction. Repositories called with the context passed
+// to fn take part in the transaction.
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// NewTransactor returns the Transactor backed by the context's database session
+func NewTransactor() Transactor {
+	return gormTransactor{}
+}
+
+type gormTransactor struct{}
+
+func (gormTransactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return database.Session(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(database.WithSession(ctx, tx))
+	})
+}
+
+// notFound maps GORM's missing row error onto ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
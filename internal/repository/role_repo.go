@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+)
+
+// RoleRepository stores the custom roles societies define on top of the built-in ones
+type RoleRepository interface {
+	// Find returns the society's custom role called name
+	Find(ctx context.Context, societyID uint, name string) (*models.Role, error)
+	FindByID(ctx context.Context, societyID, id uint) (*models.Role, error)
+	// List returns the society's custom roles ordered by name
+	List(ctx context.Context, societyID uint) ([]models.Role, error)
+	Create(ctx context.Context, role *models.Role) error
+	Save(ctx context.Context, role *models.Role) error
+	Delete(ctx context.Context, role *models.Role) error
+}
+
+// NewRoleRepository returns the GORM RoleRepository
+func NewRoleRepository() RoleRepository {
+	return roleRepo{}
+}
+
+type roleRepo struct{}
+
+func (roleRepo) Find(ctx context.Context, societyID uint, name string) (*models.Role, error) {
+	var role models.Role
+	if err := database.Session(ctx).Where("society_id = ? AND name = ?", societyID, name).First(&role).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &role, nil
+}
+
+func (roleRepo) FindByID(ctx context.Context, societyID, id uint) (*models.Role, error) {
+	var role models.Role
+	if err := database.Session(ctx).Where("id = ? AND society_id = ?", id, societyID).First(&role).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &role, nil
+}
+
+func (roleRepo) List(ctx context.Context, societyID uint) ([]models.Role, error) {
+	var roles []models.Role
+	err := database.Session(ctx).Where("society_id = ?", societyID).Order("name").Find(&roles).Error
+	return roles, err
+}
+
+func (roleRepo) Create(ctx context.Context, role *models.Role) error {
+	return database.Session(ctx).Create(role).Error
+}
+
+func (roleRepo) Save(ctx context.Context, role *models.Role) error {
+	return database.Session(ctx).Save(role).Error
+}
+
+func (roleRepo) Delete(ctx context.Context, role *models.Role) error {
+	return database.Session(ctx).Delete(role).Error
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+)
+
+// SocietyRepository reads society settings
+type SocietyRepository interface {
+	Find(ctx context.Context, id uint) (*models.Society, error)
+}
+
+// NewSocietyRepository returns the GORM SocietyRepository
+func NewSocietyRepository() SocietyRepository {
+	return societyRepo{}
+}
+
+type societyRepo struct{}
+
+func (societyRepo) Find(ctx context.Context, id uint) (*models.Society, error) {
+	var society models.Society
+	if err := database.Session(ctx).First(&society, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &society, nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+	"gorm.io/gorm"
+)
+
+// StaffRepository stores staff schedules, leave and the categories staff are skilled in.
+// Leave entries without a staff member are society-wide holidays.
+type StaffRepository interface {
+	// Profiles returns the saved profiles of the given staff members; those without one are left out
+	Profiles(ctx context.Context, staffIDs []uint) ([]models.StaffProfile, error)
+	// SaveProfile creates the staff member's profile or replaces the saved one
+	SaveProfile(ctx context.Context, profile *models.StaffProfile) error
+
+	// Leaves returns the society's leave entries ending on or after since, oldest first.
+	// A staffID limits them to that staff member's own leave.
+	Leaves(ctx context.Context, societyID uint, staffID *uint, since time.Time) ([]models.StaffLeave, error)
+	// LeavesAt returns the society's holidays and the given staff members' leave covering at,
+	// where day is the start of at's day
+	LeavesAt(ctx context.Context, societyID uint, staffIDs []uint, at, day time.Time) ([]models.StaffLeave, error)
+	CreateLeave(ctx context.Context, leave *models.StaffLeave) error
+	DeleteLeave(ctx context.Context, societyID, id uint) error
+
+	Skills(ctx context.Context, staffIDs []uint) ([]models.StaffCategory, error)
+	// SkilledStaffIDs returns the society's staff members skilled in the category
+	SkilledStaffIDs(ctx context.Context, societyID, categoryID uint) ([]uint, error)
+	// SetSkills replaces the categories the staff member is skilled in
+	SetSkills(ctx context.Context, societyID, staffID uint, categoryIDs []uint) error
+}
+
+// NewStaffRepository returns the GORM StaffRepository
+func NewStaffRepository() StaffRepository {
+	return staffRepo{}
+}
+
+type staffRepo struct{}
+
+func (staffRepo) Profiles(ctx context.Context, staffIDs []uint) ([]models.StaffProfile, error) {
+	var profiles []models.StaffProfile
+	err := database.Session(ctx).Where("staff_id IN ?", staffIDs).Find(&profiles).Error
+	return profiles, err
+}
+
+func (staffRepo) SaveProfile(ctx context.Context, profile *models.StaffProfile) error {
+	var existing models.StaffProfile
+	if err := database.Session(ctx).Where("staff_id = ?", profile.StaffID).Limit(1).Find(&existing).Error; err != nil {
+		return err
+	}
+	profile.ID = existing.ID
+	return database.Session(ctx).Save(profile).Error
+}
+
+func (staffRepo) Leaves(ctx context.Context, societyID uint, staffID *uint, since time.Time) ([]models.StaffLeave, error) {
+	query := database.Session(ctx).Where("society_id = ? AND end_date >= ?", societyID, since)
+	if staffID != nil {
+		query = query.Where("staff_id = ?", *staffID)
+	}
+	leaves := []models.StaffLeave{}
+	err := query.Order("start_date").Find(&leaves).Error
+	return leaves, err
+}
+
+func (staffRepo) LeavesAt(ctx context.Context, societyID uint, staffIDs []uint, at, day time.Time) ([]models.StaffLeave, error) {
+	var leaves []models.StaffLeave
+	err := database.Session(ctx).Where("society_id = ? AND (staff_id IN ? OR staff_id IS NULL)", societyID, staffIDs).
+		Where("start_date <= ? AND end_date >= ?", at, day).
+		Find(&leaves).Error
+	return leaves, err
+}
+
+func (staffRepo) CreateLeave(ctx context.Context, leave *models.StaffLeave) error {
+	return database.Session(ctx).Create(leave).Error
+}
+
+func (staffRepo) DeleteLeave(ctx context.Context, societyID, id uint) error {
+	result := database.Session(ctx).Where("id = ? AND society_id = ?", id, societyID).Delete(&models.StaffLeave{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (staffRepo) Skills(ctx context.Context, staffIDs []uint) ([]models.StaffCategory, error) {
+	var skills []models.StaffCategory
+	err := database.Session(ctx).Where("staff_id IN ?", staffIDs).Order("category_id").Find(&skills).Error
+	return skills, err
+}
+
+func (staffRepo) SkilledStaffIDs(ctx context.Context, societyID, categoryID uint) ([]uint, error) {
+	var ids []uint
+	err := database.Session(ctx).Model(&models.StaffCategory{}).
+		Where("category_id = ? AND society_id = ?", categoryID, societyID).
+		Pluck("staff_id", &ids).Error
+	return ids, err
+}
+
+func (staffRepo) SetSkills(ctx context.Context, societyID, staffID uint, categoryIDs []uint) error {
+	return database.Session(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("staff_id = ?", staffID).Delete(&models.StaffCategory{}).Error; err != nil {
+			return err
+		}
+		for _, categoryID := range categoryIDs {
+			skill := models.StaffCategory{StaffID: staffID, CategoryID: categoryID, SocietyID: societyID}
+			if err := tx.Create(&skill).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -1,22 +1,221 @@
 package repository
 
 import (
+    "context"
+    "strings"
+
     "github.com/VinVorteX/flashtrack/internal/models"
     "github.com/VinVorteX/flashtrack/pkg/database"
+    "github.com/lib/pq"
 )
 
 func CreateUser(user models.User) error {
     return database.DB.Create(&user).Error
 }
 
+// FindUserByEmail finds the account with email in any case, as emails are unique regardless of case
 func FindUserByEmail(email string) (models.User, error) {
     var user models.User
-    result := database.DB.Where("email = ?", email).First(&user)
+    result := database.DB.Where("LOWER(email) = ?", strings.ToLower(strings.TrimSpace(email))).First(&user)
     return user, result.Error
 }
 
-func FindAdminBySociety(societyID uint) (models.User, error) {
+func FindAdminBySociety(ctx context.Context, societyID uint) (models.User, error) {
     var user models.User
-    result := database.DB.Where("society_id = ? AND role = ?", societyID, "admin").First(&user)
+    result := database.Session(ctx).Where("society_id = ? AND role = ?", societyID, "admin").First(&user)
     return user, result.Error
 }
+
+// UserFilter selects users for the society's user listing
+type UserFilter struct {
+    SocietyID uint
+    Role      string
+    Status    string // active, deactivated, deletion_requested or empty for all
+    Search    string // matched against name and email
+    Limit     int
+    Offset    int
+}
+
+// UserRepository stores the accounts of a society
+type UserRepository interface {
+    Find(ctx context.Context, id uint) (*models.User, error)
+    // FindInSociety only finds users of the society
+    FindInSociety(ctx context.Context, societyID, id uint) (*models.User, error)
+    // FindByEmails returns the society's users whose email matches one of emails in any case
+    FindByEmails(ctx context.Context, societyID uint, emails []string) ([]models.User, error)
+    // EmailsInOtherSocieties returns which of emails, lowercased, belong to an account outside the society
+    EmailsInOtherSocieties(ctx context.Context, societyID uint, emails []string) ([]string, error)
+    // List returns a page of the users matching the filter, without passwords, and the number of matches
+    List(ctx context.Context, filter UserFilter) ([]models.User, int64, error)
+    // Names maps each of ids to the user's name
+    Names(ctx context.Context, ids []uint) (map[uint]string, error)
+    // ActiveIDs returns the IDs of the society's active users with role
+    ActiveIDs(ctx context.Context, societyID uint, role string) ([]uint, error)
+    // ActiveByRoles returns the society's active users holding one of roles, ordered by ID
+    ActiveByRoles(ctx context.Context, societyID uint, roles []string) ([]models.User, error)
+    // CountByRole counts the society's users per role, deactivated ones included
+    CountByRole(ctx context.Context, societyID uint) (map[string]int64, error)
+
+    Create(ctx context.Context, user *models.User) error
+    // Update sets the given columns of the user
+    Update(ctx context.Context, id uint, fields map[string]interface{}) error
+    // Delete removes the user and the rows that only make sense while they exist
+    Delete(ctx context.Context, id uint) error
+}
+
+// NewUserRepository returns the GORM UserRepository
+func NewUserRepository() UserRepository {
+    return userRepo{}
+}
+
+type userRepo struct{}
+
+func (userRepo) Find(ctx context.Context, id uint) (*models.User, error) {
+    var user models.User
+    if err := database.Session(ctx).First(&user, id).Error; err != nil {
+        return nil, notFound(err)
+    }
+    return &user, nil
+}
+
+func (userRepo) FindInSociety(ctx context.Context, societyID, id uint) (*models.User, error) {
+    var user models.User
+    if err := database.Session(ctx).Where("id = ? AND society_id = ?", id, societyID).First(&user).Error; err != nil {
+        return nil, notFound(err)
+    }
+    return &user, nil
+}
+
+func (userRepo) List(ctx context.Context, filter UserFilter) ([]models.User, int64, error) {
+    query := database.Session(ctx).Model(&models.User{}).Where("society_id = ?", filter.SocietyID)
+    if filter.Role != "" {
+        query = query.Where("role = ?", filter.Role)
+    }
+    switch filter.Status {
+    case "active":
+        query = query.Where("deactivated_at IS NULL")
+    case "deactivated":
+        query = query.Where("deactivated_at IS NOT NULL")
+    case "deletion_requested":
+        query = query.Where("deletion_requested_at IS NOT NULL")
+    }
+    if filter.Search != "" {
+        pattern := "%" + strings.ToLower(filter.Search) + "%"
+        query = query.Where("LOWER(name) LIKE ? OR LOWER(email) LIKE ?", pattern, pattern)
+    }
+
+    var total int64
+    if err := query.Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    users := []models.User{}
+    err := query.Omit("password").Order("name, id").Limit(filter.Limit).Offset(filter.Offset).Find(&users).Error
+    return users, total, err
+}
+
+func (userRepo) FindByEmails(ctx context.Context, societyID uint, emails []string) ([]models.User, error) {
+    users := []models.User{}
+    if len(emails) == 0 {
+        return users, nil
+    }
+    err := database.Session(ctx).Where("society_id = ? AND LOWER(email) IN ?", societyID, lowerAll(emails)).Find(&users).Error
+    return users, err
+}
+
+// EmailsInOtherSocieties asks the database function that looks past row-level security, since
+// emails are unique across societies but a tenant session only sees its own society's users
+func (userRepo) EmailsInOtherSocieties(ctx context.Context, societyID uint, emails []string) ([]string, error) {
+    taken := []string{}
+    if len(emails) == 0 {
+        return taken, nil
+    }
+    err := database.Session(ctx).
+        Raw("SELECT email FROM "+database.EmailsInOtherSocieties+"(?, ?)", societyID, pq.Array(lowerAll(emails))).
+        Scan(&taken).Error
+    return taken, err
+}
+
+func lowerAll(values []string) []string {
+    lowered := make([]string, len(values))
+    for i, v := range values {
+        lowered[i] = strings.ToLower(v)
+    }
+    return lowered
+}
+
+func (userRepo) Names(ctx context.Context, ids []uint) (map[uint]string, error) {
+    names := make(map[uint]string, len(ids))
+    if len(ids) == 0 {
+        return names, nil
+    }
+    var users []models.User
+    if err := database.Session(ctx).Select("id", "name").Where("id IN ?", ids).Find(&users).Error; err != nil {
+        return nil, err
+    }
+    for _, user := range users {
+        names[user.ID] = user.Name
+    }
+    return names, nil
+}
+
+func (userRepo) ActiveIDs(ctx context.Context, societyID uint, role string) ([]uint, error) {
+    var ids []uint
+    err := database.Session(ctx).Model(&models.User{}).
+        Where("society_id = ? AND role = ? AND deactivated_at IS NULL", societyID, role).
+        Pluck("id", &ids).Error
+    return ids, err
+}
+
+func (userRepo) ActiveByRoles(ctx context.Context, societyID uint, roles []string) ([]models.User, error) {
+    var users []models.User
+    err := database.Session(ctx).Where("role IN ? AND society_id = ? AND deactivated_at IS NULL", roles, societyID).
+        Select("id", "name", "email", "role", "society_id").
+        Order("id").
+        Find(&users).Error
+    return users, err
+}
+
+func (userRepo) CountByRole(ctx context.Context, societyID uint) (map[string]int64, error) {
+    var rows []struct {
+        Role  string
+        Count int64
+    }
+    if err := database.Session(ctx).Model(&models.User{}).Select("role, COUNT(*) AS count").
+        Where("society_id = ?", societyID).Group("role").Scan(&rows).Error; err != nil {
+        return nil, err
+    }
+    counts := make(map[string]int64, len(rows))
+    for _, row := range rows {
+        counts[row.Role] = row.Count
+    }
+    return counts, nil
+}
+
+func (userRepo) Create(ctx context.Context, user *models.User) error {
+    return database.Session(ctx).Create(user).Error
+}
+
+func (userRepo) Update(ctx context.Context, id uint, fields map[string]interface{}) error {
+    return database.Session(ctx).Model(&models.User{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (userRepo) Delete(ctx context.Context, id uint) error {
+    cleanups := []struct {
+        model interface{}
+        where string
+    }{
+        {&models.Notification{}, "user_id = ?"},
+        {&models.Invite{}, "user_id = ?"},
+        {&models.StaffCategory{}, "staff_id = ?"},
+        {&models.StaffProfile{}, "staff_id = ?"},
+        {&models.StaffLeave{}, "staff_id = ?"},
+    }
+    db := database.Session(ctx)
+    for _, cleanup := range cleanups {
+        if err := db.Where(cleanup.where, id).Delete(cleanup.model).Error; err != nil {
+            return err
+        }
+    }
+    return db.Delete(&models.User{}, id).Error
+}
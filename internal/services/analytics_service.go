@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/pkg/database"
+)
+
+// Granularities accepted for time buckets
+var validGranularities = map[string]bool{"day": true, "week": true, "month": true}
+
+// IsValidGranularity reports whether g can be used as a time bucket size
+func IsValidGranularity(g string) bool {
+	return validGranularities[g]
+}
+
+// StatusCount is the number of complaints in a status
+type StatusCount struct {
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
+}
+
+// CategoryCount is the number of complaints in a category
+type CategoryCount struct {
+	CategoryID   uint   `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	Count        int64  `json:"count"`
+}
+
+// PeriodCount is the complaint volume of a time bucket
+type PeriodCount struct {
+	Period   time.Time `json:"period"`
+	Created  int64     `json:"created"`
+	Resolved int64     `json:"resolved"`
+}
+
+// DurationStats holds the mean and median of a duration in hours
+type DurationStats struct {
+	Count       int64    `json:"count"`
+	MeanHours   *float64 `json:"mean_hours"`
+	MedianHours *float64 `json:"median_hours"`
+}
+
+// SLAStats describes how many resolved complaints met their deadline
+type SLAStats struct {
+	Resolved       int64    `json:"resolved"`
+	WithinSLA      int64    `json:"within_sla"`
+	ComplianceRate *float64 `json:"compliance_rate"`
+	OpenBreached   int64    `json:"open_breached"`
+}
+
+// ReopenStats describes how often resolutions were reopened
+type ReopenStats struct {
+	EverResolved int64    `json:"ever_resolved"`
+	Reopened     int64    `json:"reopened"`
+	ReopenRate   *float64 `json:"reopen_rate"`
+}
+
+// RatingCount is the number of feedback entries with a rating
+type RatingCount struct {
+	Rating int   `json:"rating"`
+	Count  int64 `json:"count"`
+}
+
+// AgeingBucket is the number of open complaints within an age range
+type AgeingBucket struct {
+	Bucket string `json:"bucket"`
+	Count  int64  `json:"count"`
+}
+
+// AnalyticsReport is the complaint analytics of a society over a date range
+type AnalyticsReport struct {
+	From          time.Time       `json:"from"`
+	To            time.Time       `json:"to"`
+	Granularity   string          `json:"granularity"`
+	Total         int64           `json:"total"`
+	ByStatus      []StatusCount   `json:"by_status"`
+	ByCategory    []CategoryCount `json:"by_category"`
+	ByPeriod      []PeriodCount   `json:"by_period"`
+	TimeToAssign  DurationStats   `json:"time_to_assign"`
+	TimeToResolve DurationStats   `json:"time_to_resolve"`
+	SLA           SLAStats        `json:"sla"`
+	Reopens       ReopenStats     `json:"reopens"`
+	Ratings       []RatingCount   `json:"ratings"`
+	AverageRating *float64        `json:"average_rating"`
+	BacklogAgeing []AgeingBucket  `json:"backlog_ageing"`
+}
+
+// AnalyticsService computes complaint metrics in SQL
+type AnalyticsService struct {
+	Roles *RoleService
+}
+
+// Report computes complaint analytics for complaints created in [from, to); the resolved counts
+// of ByPeriod cover resolutions in that range instead. Merged duplicates are excluded so each
+// problem is counted once.
+func (as *AnalyticsService) Report(ctx context.Context, societyID uint, from, to time.Time, granularity string) (*AnalyticsReport, error) {
+	report := &AnalyticsReport{From: from, To: to, Granularity: granularity}
+	var averageRating sql.NullFloat64
+	params := map[string]interface{}{
+		"society":     societyID,
+		"from":        from,
+		"to":          to,
+		"granularity": granularity,
+		"open":        openStatuses,
+	}
+
+	const scope = `FROM complaints c WHERE c.society_id = @society AND c.duplicate_of_id IS NULL
+		AND c.created_at >= @from AND c.created_at < @to`
+	// Feedback taken back by a reopen no longer rates the complaint
+	const ratings = `FROM feedbacks f JOIN complaints c ON c.id = f.complaint_id
+		WHERE c.society_id = @society AND c.duplicate_of_id IS NULL AND NOT f.reversed
+		AND f.created_at >= @from AND f.created_at < @to`
+
+	queries := []struct {
+		sql  string
+		dest interface{}
+	}{
+		{`SELECT COUNT(*) ` + scope, &report.Total},
+		{`SELECT c.status, COUNT(*) AS count ` + scope + ` GROUP BY c.status ORDER BY count DESC`, &report.ByStatus},
+		{`SELECT c.category_id, COALESCE(MAX(cat.name), 'General') AS category_name, COUNT(*) AS count
+			FROM complaints c LEFT JOIN categories cat ON cat.id = c.category_id
+			WHERE c.society_id = @society AND c.duplicate_of_id IS NULL AND c.created_at >= @from AND c.created_at < @to
+			GROUP BY c.category_id ORDER BY count DESC`, &report.ByCategory},
+		// Complaints count in the period they were raised and resolutions in the period they happened
+		{`SELECT period, SUM(created) AS created, SUM(resolved) AS resolved FROM (
+				SELECT date_trunc(@granularity, c.created_at) AS period, 1 AS created, 0 AS resolved ` + scope + `
+				UNION ALL
+				SELECT date_trunc(@granularity, c.resolved_at), 0, 1 FROM complaints c
+				WHERE c.society_id = @society AND c.duplicate_of_id IS NULL
+					AND c.resolved_at >= @from AND c.resolved_at < @to
+			) volume GROUP BY period ORDER BY period`, &report.ByPeriod},
+		// Reassignment moves assigned_at, so time to assign runs to the first assignment
+		{`SELECT COUNT(*) AS count,
+			AVG(EXTRACT(EPOCH FROM c.first_assigned_at - c.created_at) / 3600) AS mean_hours,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM c.first_assigned_at - c.created_at) / 3600) AS median_hours
+			` + scope + ` AND c.first_assigned_at IS NOT NULL`, &report.TimeToAssign},
+		{`SELECT COUNT(*) AS count,
+			AVG(EXTRACT(EPOCH FROM c.resolved_at - c.created_at) / 3600) AS mean_hours,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM c.resolved_at - c.created_at) / 3600) AS median_hours
+			` + scope + ` AND c.resolved_at IS NOT NULL`, &report.TimeToResolve},
+		{`SELECT COUNT(*) FILTER (WHERE c.resolved_at IS NOT NULL) AS resolved,
+			COUNT(*) FILTER (WHERE c.resolved_at IS NOT NULL AND c.resolved_at <= c.due_at) AS within_sla,
+			COUNT(*) FILTER (WHERE c.resolved_at IS NOT NULL AND c.resolved_at <= c.due_at)::float
+				/ NULLIF(COUNT(*) FILTER (WHERE c.resolved_at IS NOT NULL), 0) AS compliance_rate,
+			COUNT(*) FILTER (WHERE c.status IN @open AND c.due_at < NOW()) AS open_breached
+			` + scope + ` AND c.due_at IS NOT NULL`, &report.SLA},
+		{`SELECT COUNT(*) FILTER (WHERE c.resolved_at IS NOT NULL OR c.reopen_count > 0) AS ever_resolved,
+			COUNT(*) FILTER (WHERE c.reopen_count > 0) AS reopened,
+			COUNT(*) FILTER (WHERE c.reopen_count > 0)::float
+				/ NULLIF(COUNT(*) FILTER (WHERE c.resolved_at IS NOT NULL OR c.reopen_count > 0), 0) AS reopen_rate
+			` + scope, &report.Reopens},
+		{`SELECT f.rating, COUNT(*) AS count ` + ratings + ` GROUP BY f.rating ORDER BY f.rating`, &report.Ratings},
+		{`SELECT AVG(f.rating) ` + ratings, &averageRating},
+		// Backlog ageing looks at everything open right now, regardless of the date range
+		{`SELECT bucket, COUNT(*) AS count FROM (
+				SELECT CASE
+					WHEN NOW() - c.created_at < INTERVAL '1 day' THEN '0-1d'
+					WHEN NOW() - c.created_at < INTERVAL '3 days' THEN '1-3d'
+					WHEN NOW() - c.created_at < INTERVAL '7 days' THEN '3-7d'
+					WHEN NOW() - c.created_at < INTERVAL '30 days' THEN '7-30d'
+					ELSE '30d+'
+				END AS bucket
+				FROM complaints c
+				WHERE c.society_id = @society AND c.duplicate_of_id IS NULL AND c.status IN @open
+			) aged GROUP BY bucket`, &report.BacklogAgeing},
+	}
+
+	for _, q := range queries {
+		if err := database.Session(ctx).Raw(q.sql, params).Scan(q.dest).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if averageRating.Valid {
+		report.AverageRating = &averageRating.Float64
+	}
+	report.BacklogAgeing = orderAgeing(report.BacklogAgeing)
+	if report.ByStatus == nil {
+		report.ByStatus = []StatusCount{}
+	}
+	if report.ByCategory == nil {
+		report.ByCategory = []CategoryCount{}
+	}
+	if report.ByPeriod == nil {
+		report.ByPeriod = []PeriodCount{}
+	}
+	if report.Ratings == nil {
+		report.Ratings = []RatingCount{}
+	}
+
+	return report, nil
+}
+
+// StaffPerformance summarises a staff member's workload, speed and ratings
+type StaffPerformance struct {
+	StaffID          uint     `json:"staff_id"`
+	Name             string   `json:"name"`
+	Email            string   `json:"email"`
+	Assigned         int64    `json:"assigned"`
+	Open             int64    `json:"open"`
+	Resolved         int64    `json:"resolved"`
+	Reopens          int64    `json:"reopens"`
+	MeanResolveHours *float64 `json:"mean_resolve_hours"`
+	FeedbackCount    int64    `json:"feedback_count"`
+	AverageRating    *float64 `json:"average_rating"`
+	TotalPoints      int64    `json:"total_points"`
+}
+
+// StaffPerformance returns one row per member of the society whose role works complaints.
+// Merged duplicates and feedback reversed by a reopen are left out.
+func (as *AnalyticsService) StaffPerformance(ctx context.Context, societyID uint) ([]StaffPerformance, error) {
+	roles, err := as.Roles.StaffRoles(ctx, societyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []StaffPerformance
+	err = database.Session(ctx).Raw(`
+		SELECT u.id AS staff_id, u.name, u.email,
+			COUNT(c.id) AS assigned,
+			COUNT(c.id) FILTER (WHERE c.status IN @open) AS open,
+			COUNT(c.id) FILTER (WHERE c.status = 'resolved') AS resolved,
+			COALESCE(SUM(c.reopen_count), 0) AS reopens,
+			AVG(EXTRACT(EPOCH FROM c.resolved_at - c.created_at) / 3600) FILTER (WHERE c.resolved_at IS NOT NULL) AS mean_resolve_hours,
+			(SELECT COUNT(*) FROM feedbacks f WHERE f.staff_id = u.id AND NOT f.reversed) AS feedback_count,
+			(SELECT AVG(f.rating) FROM feedbacks f WHERE f.staff_id = u.id AND NOT f.reversed) AS average_rating,
+			COALESCE(MAX(sp.total_points), 0) AS total_points
+		FROM users u
+		LEFT JOIN complaints c ON c.staff_id = u.id AND c.duplicate_of_id IS NULL
+		LEFT JOIN staff_points sp ON sp.staff_id = u.id
+		WHERE u.role IN @staff AND u.society_id = @society
+		GROUP BY u.id, u.name, u.email
+		ORDER BY u.name`, map[string]interface{}{"society": societyID, "open": openStatuses, "staff": roles}).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// orderAgeing returns every ageing bucket in age order, filling in empty ones
+func orderAgeing(rows []AgeingBucket) []AgeingBucket {
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Bucket] = row.Count
+	}
+
+	buckets := []string{"0-1d", "1-3d", "3-7d", "7-30d", "30d+"}
+	ordered := make([]AgeingBucket, len(buckets))
+	for i, b := range buckets {
+		ordered[i] = AgeingBucket{Bucket: b, Count: counts[b]}
+	}
+	return ordered
+}
@@ -0,0 +1,17 @@
+package services
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestOrderAgeingFillsEveryBucket(t *testing.T) {
+	got := orderAgeing([]AgeingBucket{{Bucket: "30d+", Count: 4}, {Bucket: "0-1d", Count: 2}, {Bucket: "3-7d", Count: 1}})
+	want := []AgeingBucket{{"0-1d", 2}, {"1-3d", 0}, {"3-7d", 1}, {"7-30d", 0}, {"30d+", 4}}
+	if !slices.Equal(got, want) {
+		t.Errorf("orderAgeing = %v, want %v", got, want)
+	}
+	if got := orderAgeing(nil); len(got) != 5 {
+		t.Errorf("orderAgeing(nil) has %d buckets, want all 5", len(got))
+	}
+}
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/repository"
+)
+
+// Assignment strategy names a society can pick from
+const (
+	StrategyManual        = "manual"
+	StrategyRoundRobin    = "round_robin"
+	StrategyLeastWorkload = "least_workload"
+	StrategyHighestRating = "highest_rating"
+	StrategySkillBased    = "skill_based"
+)
+
+// openStatuses are the complaint statuses that count towards a staff member's workload
+var openStatuses = []string{"pending", "in-progress"}
+
+// AssignmentStrategy picks a staff member for a complaint from the given candidates, reading
+// anything else it needs through the service's repositories. Returning nil without an error
+// leaves the complaint unassigned.
+type AssignmentStrategy interface {
+	Pick(ctx context.Context, as *AssignmentService, complaint *models.Complaint, candidates []models.User) (*models.User, error)
+}
+
+var assignmentStrategies = map[string]AssignmentStrategy{
+	StrategyRoundRobin:    roundRobinStrategy{},
+	StrategyLeastWorkload: leastWorkloadStrategy{},
+	StrategyHighestRating: highestRatingStrategy{},
+	StrategySkillBased:    skillBasedStrategy{},
+}
+
+// RegisterAssignmentStrategy adds or replaces a named assignment strategy
+func RegisterAssignmentStrategy(name string, strategy AssignmentStrategy) {
+	assignmentStrategies[name] = strategy
+}
+
+// IsValidAssignmentStrategy reports whether name can be stored as a society's strategy
+func IsValidAssignmentStrategy(name string) bool {
+	if name == "" || name == StrategyManual {
+		return true
+	}
+	_, ok := assignmentStrategies[name]
+	return ok
+}
+
+// AssignmentService automatically assigns new complaints to staff
+type AssignmentService struct {
+	Complaints    repository.ComplaintRepository
+	Users         repository.UserRepository
+	Societies     repository.SocietyRepository
+	Feedback      repository.FeedbackRepository
+	Cursors       repository.AssignmentRepository
+	Notifications *NotificationService
+	Staff         *StaffService
+	Roles         *RoleService
+}
+
+// AutoAssign assigns the complaint using its society's strategy and notifies the chosen staff.
+// It returns nil when the society assigns manually or no staff member qualifies.
+// Emergencies skip the strategy and go straight to on-duty staff.
+func (as *AssignmentService) AutoAssign(ctx context.Context, complaint *models.Complaint) (*models.User, error) {
+	if complaint.IsEmergency {
+		return as.assignEmergency(ctx, complaint)
+	}
+
+	society, err := as.Societies.Find(ctx, complaint.SocietyID)
+	if err != nil {
+		return nil, err
+	}
+
+	strategy, ok := assignmentStrategies[society.AssignmentStrategy]
+	if !ok {
+		// Empty or "manual" strategy - wait for an admin
+		return nil, nil
+	}
+
+	candidates, err := as.candidates(ctx, complaint)
+	if err != nil || len(candidates) == 0 {
+		return nil, err
+	}
+
+	staff, err := strategy.Pick(ctx, as, complaint, candidates)
+	if err != nil || staff == nil {
+		return nil, err
+	}
+
+	assignComplaint(complaint, staff.ID, time.Now())
+	if err := as.Complaints.Save(ctx, complaint); err != nil {
+		return nil, err
+	}
+
+	if as.Notifications != nil {
+		if err := as.Notifications.NotifyStaffAssignment(ctx, staff.ID, complaint); err != nil {
+			slog.ErrorContext(ctx, "failed to notify staff of auto-assignment", "staff_id", staff.ID, "error", err)
+		}
+	}
+
+	return staff, nil
+}
+
+// assignEmergency alerts every on-duty staff member and everyone who can assign complaints, then
+// hands the complaint to the least loaded on-duty staff member even if they are at capacity
+func (as *AssignmentService) assignEmergency(ctx context.Context, complaint *models.Complaint) (*models.User, error) {
+	staff, err := as.staffMembers(ctx, complaint.SocietyID)
+	if err != nil {
+		return nil, err
+	}
+
+	onDuty := staff
+	if as.Staff != nil {
+		if onDuty, err = as.Staff.FilterOnDuty(ctx, complaint.SocietyID, staff, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	// Everyone who can dispatch staff hears about it, admins and custom roles alike
+	dispatcherRoles, err := as.Roles.RolesWith(ctx, complaint.SocietyID, PermComplaintAssign)
+	if err != nil {
+		return nil, err
+	}
+	dispatchers, err := as.Users.ActiveByRoles(ctx, complaint.SocietyID, dispatcherRoles)
+	if err != nil {
+		return nil, err
+	}
+
+	if as.Notifications != nil {
+		as.Notifications.NotifyEmergency(ctx, uniqueIDs(append(staffIDs(onDuty), staffIDs(dispatchers)...)), complaint)
+	}
+
+	if len(onDuty) == 0 {
+		return nil, nil
+	}
+
+	picked, err := as.leastLoaded(ctx, onDuty)
+	if err != nil {
+		return nil, err
+	}
+
+	assignComplaint(complaint, picked.ID, time.Now())
+	if err := as.Complaints.Save(ctx, complaint); err != nil {
+		return nil, err
+	}
+
+	return picked, nil
+}
+
+// Reassign moves the complaint to the least loaded available staff member other than the
+// current assignee and notifies them. It returns nil when nobody else is available.
+func (as *AssignmentService) Reassign(ctx context.Context, complaint *models.Complaint) (*models.User, error) {
+	candidates, err := as.candidates(ctx, complaint)
+	if err != nil {
+		return nil, err
+	}
+
+	var others []models.User
+	for _, staff := range candidates {
+		if complaint.StaffID == nil || staff.ID != *complaint.StaffID {
+			others = append(others, staff)
+		}
+	}
+	if len(others) == 0 {
+		return nil, nil
+	}
+
+	staff, err := as.leastLoaded(ctx, others)
+	if err != nil {
+		return nil, err
+	}
+
+	assignComplaint(complaint, staff.ID, time.Now())
+	if err := as.Complaints.Save(ctx, complaint); err != nil {
+		return nil, err
+	}
+
+	if as.Notifications != nil {
+		if err := as.Notifications.NotifyStaffAssignment(ctx, staff.ID, complaint); err != nil {
+			slog.ErrorContext(ctx, "failed to notify staff of reassignment", "staff_id", staff.ID, "error", err)
+		}
+	}
+
+	return staff, nil
+}
+
+// staffMembers returns the active members of the society whose role lets them be assigned
+// complaints, built-in staff or a custom role, ordered by ID
+func (as *AssignmentService) staffMembers(ctx context.Context, societyID uint) ([]models.User, error) {
+	roles, err := as.Roles.StaffRoles(ctx, societyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return as.Users.ActiveByRoles(ctx, societyID, roles)
+}
+
+// candidates returns the available staff members of the complaint's society ordered by ID
+func (as *AssignmentService) candidates(ctx context.Context, complaint *models.Complaint) ([]models.User, error) {
+	staff, err := as.staffMembers(ctx, complaint.SocietyID)
+	if err != nil || as.Staff == nil {
+		return staff, err
+	}
+
+	// Skip staff who are off shift, on leave or at capacity
+	return as.Staff.FilterAvailable(ctx, complaint.SocietyID, staff, time.Now())
+}
+
+func staffIDs(candidates []models.User) []uint {
+	ids := make([]uint, len(candidates))
+	for i, staff := range candidates {
+		ids[i] = staff.ID
+	}
+	return ids
+}
+
+// leastLoaded returns the candidate with the fewest open complaints, preferring the lowest ID on ties
+func (as *AssignmentService) leastLoaded(ctx context.Context, candidates []models.User) (*models.User, error) {
+	workloads, err := as.Complaints.Workloads(ctx, staffIDs(candidates), openStatuses)
+	if err != nil {
+		return nil, err
+	}
+
+	best := 0
+	for i := range candidates {
+		if workloads[candidates[i].ID] < workloads[candidates[best].ID] {
+			best = i
+		}
+	}
+	return &candidates[best], nil
+}
+
+// roundRobinStrategy rotates through staff members separately for each category
+type roundRobinStrategy struct{}
+
+func (roundRobinStrategy) Pick(ctx context.Context, as *AssignmentService, complaint *models.Complaint, candidates []models.User) (*models.User, error) {
+	var picked *models.User
+
+	err := as.Cursors.AdvanceCursor(ctx, complaint.SocietyID, complaint.CategoryID, func(last uint) uint {
+		// Candidates are ordered by ID, so take the first one after the last pick and wrap around
+		picked = &candidates[0]
+		for i := range candidates {
+			if candidates[i].ID > last {
+				picked = &candidates[i]
+				break
+			}
+		}
+		return picked.ID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return picked, nil
+}
+
+// leastWorkloadStrategy picks the staff member with the fewest open complaints
+type leastWorkloadStrategy struct{}
+
+func (leastWorkloadStrategy) Pick(ctx context.Context, as *AssignmentService, complaint *models.Complaint, candidates []models.User) (*models.User, error) {
+	return as.leastLoaded(ctx, candidates)
+}
+
+// highestRatingStrategy picks the staff member with the best average feedback rating,
+// falling back to workload to break ties. Feedback reversed by a reopen is not counted.
+type highestRatingStrategy struct{}
+
+func (highestRatingStrategy) Pick(ctx context.Context, as *AssignmentService, complaint *models.Complaint, candidates []models.User) (*models.User, error) {
+	ratings, err := as.Feedback.AverageRatings(ctx, staffIDs(candidates))
+	if err != nil {
+		return nil, err
+	}
+
+	var best float64
+	var top []models.User
+	for _, staff := range candidates {
+		rating := ratings[staff.ID]
+		switch {
+		case len(top) == 0 || rating > best:
+			best = rating
+			top = []models.User{staff}
+		case rating == best:
+			top = append(top, staff)
+		}
+	}
+
+	return as.leastLoaded(ctx, top)
+}
+
+// skillBasedStrategy picks the least loaded staff member mapped to the complaint's category
+type skillBasedStrategy struct{}
+
+func (skillBasedStrategy) Pick(ctx context.Context, as *AssignmentService, complaint *models.Complaint, candidates []models.User) (*models.User, error) {
+	skilledIDs, err := as.Staff.SkilledStaffIDs(ctx, complaint.SocietyID, complaint.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	skilled := make(map[uint]bool, len(skilledIDs))
+	for _, id := range skilledIDs {
+		skilled[id] = true
+	}
+
+	var matching []models.User
+	for _, staff := range candidates {
+		if skilled[staff.ID] {
+			matching = append(matching, staff)
+		}
+	}
+
+	if len(matching) == 0 {
+		return nil, nil
+	}
+	return as.leastLoaded(ctx, matching)
+}
+
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	var unique []uint
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
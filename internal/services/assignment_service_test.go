@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+)
+
+// assignmentFixture is an AssignmentService over three staff members of the test society
+type assignmentFixture struct {
+	service    *AssignmentService
+	complaints *fakeComplaints
+	feedback   *fakeFeedback
+	staff      *fakeStaff
+	societies  *fakeSocieties
+}
+
+var testThirdStaff = models.User{ID: 22, Name: "Third Staff", Role: "staff", SocietyID: testSociety}
+
+func newAssignmentFixture(strategy string) *assignmentFixture {
+	f := &assignmentFixture{
+		complaints: newFakeComplaints(),
+		feedback:   newFakeFeedback(),
+		staff:      newFakeStaff(),
+		societies: &fakeSocieties{rows: map[uint]*models.Society{
+			testSociety: {ID: testSociety, AssignmentStrategy: strategy},
+		}},
+	}
+	users := newFakeUsers(testAdmin, testStaff, testOtherStaff, testThirdStaff, testResident)
+	f.service = &AssignmentService{
+		Complaints: f.complaints,
+		Users:      users,
+		Societies:  f.societies,
+		Feedback:   f.feedback,
+		Cursors:    &fakeCursors{},
+		Roles:      &RoleService{Roles: &fakeRoles{}, Users: users},
+		Staff:      &StaffService{Staff: f.staff, Complaints: f.complaints, Societies: f.societies},
+	}
+	return f
+}
+
+// assign files a new complaint in the category and auto-assigns it, returning the picked staff ID or 0
+func (f *assignmentFixture) assign(t *testing.T, categoryID uint) uint {
+	t.Helper()
+	complaint := &models.Complaint{Title: "Broken light", Status: "pending", SocietyID: testSociety,
+		ResidentID: testResident.ID, CategoryID: categoryID}
+	f.complaints.Create(context.Background(), complaint)
+
+	staff, err := f.service.AutoAssign(context.Background(), complaint)
+	if err != nil {
+		t.Fatalf("AutoAssign: %v", err)
+	}
+	if staff == nil {
+		return 0
+	}
+	if stored := f.complaints.rows[complaint.ID]; stored.StaffID == nil || *stored.StaffID != staff.ID || stored.Status != "in-progress" {
+		t.Errorf("complaint %d stored as %+v, want it in progress with staff %d", complaint.ID, *stored, staff.ID)
+	}
+	return staff.ID
+}
+
+func TestRoundRobinRotatesPerCategory(t *testing.T) {
+	f := newAssignmentFixture(StrategyRoundRobin)
+
+	var plumbing, electrical []uint
+	for i := 0; i < 4; i++ {
+		plumbing = append(plumbing, f.assign(t, 1))
+	}
+	electrical = append(electrical, f.assign(t, 2), f.assign(t, 2))
+
+	if want := []uint{20, 21, 22, 20}; !slices.Equal(plumbing, want) {
+		t.Errorf("plumbing went to %v, want %v wrapping around", plumbing, want)
+	}
+	if want := []uint{20, 21}; !slices.Equal(electrical, want) {
+		t.Errorf("electrical went to %v, want its own rotation %v", electrical, want)
+	}
+}
+
+func TestRoundRobinSkipsUnavailableStaff(t *testing.T) {
+	f := newAssignmentFixture(StrategyRoundRobin)
+	f.staff.profiles[testOtherStaff.ID] = models.StaffProfile{StaffID: testOtherStaff.ID, MaxConcurrent: 1}
+
+	got := []uint{f.assign(t, 1), f.assign(t, 1), f.assign(t, 1)}
+	// The second staff member is full after their first complaint
+	if want := []uint{20, 21, 22}; !slices.Equal(got, want) {
+		t.Fatalf("assigned %v, want %v", got, want)
+	}
+	if next := f.assign(t, 1); next != testStaff.ID {
+		t.Errorf("after wrapping, assigned %d, want %d past the full staff member", next, testStaff.ID)
+	}
+}
+
+func TestLeastWorkloadPicksFewestOpenComplaints(t *testing.T) {
+	f := newAssignmentFixture(StrategyLeastWorkload)
+	busy, other := testStaff.ID, testOtherStaff.ID
+	f.complaints.Save(context.Background(), &models.Complaint{ID: 100, Status: "in-progress", SocietyID: testSociety, StaffID: &busy})
+	f.complaints.Save(context.Background(), &models.Complaint{ID: 101, Status: "pending", SocietyID: testSociety, StaffID: &other})
+	// Resolved complaints no longer count
+	f.complaints.Save(context.Background(), &models.Complaint{ID: 102, Status: "resolved", SocietyID: testSociety, StaffID: &busy})
+
+	if got := f.assign(t, 1); got != testThirdStaff.ID {
+		t.Errorf("assigned %d, want the idle staff member %d", got, testThirdStaff.ID)
+	}
+	// Ties go to the lowest ID
+	if got := f.assign(t, 1); got != testStaff.ID {
+		t.Errorf("assigned %d, want %d on a tie", got, testStaff.ID)
+	}
+}
+
+func TestHighestRatingBreaksTiesByWorkload(t *testing.T) {
+	f := newAssignmentFixture(StrategyHighestRating)
+	ctx := context.Background()
+	f.feedback.Create(ctx, &models.Feedback{ComplaintID: 90, StaffID: testStaff.ID, Rating: 5})
+	f.feedback.Create(ctx, &models.Feedback{ComplaintID: 91, StaffID: testOtherStaff.ID, Rating: 5})
+	f.feedback.Create(ctx, &models.Feedback{ComplaintID: 92, StaffID: testThirdStaff.ID, Rating: 3})
+	// A reversed rating no longer lifts the third staff member
+	f.feedback.Create(ctx, &models.Feedback{ComplaintID: 93, StaffID: testThirdStaff.ID, Rating: 5, Reversed: true})
+	busy := testStaff.ID
+	f.complaints.Save(ctx, &models.Complaint{ID: 100, Status: "in-progress", SocietyID: testSociety, StaffID: &busy})
+
+	if got := f.assign(t, 1); got != testOtherStaff.ID {
+		t.Errorf("assigned %d, want the less loaded of the two best rated, %d", got, testOtherStaff.ID)
+	}
+}
+
+func TestSkillBasedOnlyPicksSkilledStaff(t *testing.T) {
+	f := newAssignmentFixture(StrategySkillBased)
+	f.staff.skills = []models.StaffCategory{
+		{StaffID: testOtherStaff.ID, CategoryID: 1, SocietyID: testSociety},
+		{StaffID: testThirdStaff.ID, CategoryID: 1, SocietyID: testSociety},
+	}
+
+	if got := f.assign(t, 1); got != testOtherStaff.ID {
+		t.Errorf("assigned %d, want the first skilled staff member %d", got, testOtherStaff.ID)
+	}
+	if got := f.assign(t, 1); got != testThirdStaff.ID {
+		t.Errorf("assigned %d, want the less loaded skilled staff member %d", got, testThirdStaff.ID)
+	}
+	if got := f.assign(t, 2); got != 0 {
+		t.Errorf("assigned %d, want a complaint nobody is skilled for left unassigned", got)
+	}
+}
+
+func TestManualStrategyLeavesComplaintsUnassigned(t *testing.T) {
+	for _, strategy := range []string{"", StrategyManual} {
+		f := newAssignmentFixture(strategy)
+		if got := f.assign(t, 1); got != 0 {
+			t.Errorf("strategy %q assigned staff %d, want none", strategy, got)
+		}
+	}
+}
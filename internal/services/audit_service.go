@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+	"gorm.io/gorm"
+)
+
+// Audited admin actions
+const (
+	AuditUserUpdated       = "user.updated"
+	AuditUserDeactivated   = "user.deactivated"
+	AuditUserReactivated   = "user.reactivated"
+	AuditUserPasswordReset = "user.password_reset"
+	AuditUserDeleted       = "user.deleted"
+
+	// Self-service actions, recorded so admins can follow up
+	AuditPasswordChanged   = "user.password_changed"
+	AuditDeletionRequested = "user.deletion_requested"
+
+	// Platform super-admin actions, recorded against the society they affect
+	AuditSocietyCreated     = "platform.society_created"
+	AuditSocietySuspended   = "platform.society_suspended"
+	AuditSocietyResumed     = "platform.society_resumed"
+	AuditSocietyDeleted     = "platform.society_deleted"
+	AuditSocietyPlanChanged = "platform.plan_changed"
+	AuditImpersonation      = "platform.impersonation_started"
+)
+
+// RecordAudit stores an audit entry for an action the actor took on a target, using tx
+// so the entry is only kept when the action itself commits
+func RecordAudit(tx *gorm.DB, actor *models.User, action, targetType string, targetID uint, details interface{}) error {
+	return recordAuditIn(tx, actor.SocietyID, actor, action, targetType, targetID, details)
+}
+
+// recordAuditIn stores an audit entry in the given society, for actors such as platform
+// super-admins who do not belong to the society they act on
+func recordAuditIn(tx *gorm.DB, societyID uint, actor *models.User, action, targetType string, targetID uint, details interface{}) error {
+	entry, err := newAuditEntry(societyID, actor, action, targetType, targetID, details)
+	if err != nil {
+		return err
+	}
+	return tx.Create(entry).Error
+}
+
+// newAuditEntry builds the audit entry for an action the actor took on a target in the society
+func newAuditEntry(societyID uint, actor *models.User, action, targetType string, targetID uint, details interface{}) (*models.AuditLog, error) {
+	entry := &models.AuditLog{
+		SocietyID:      societyID,
+		ActorID:        actor.ID,
+		ImpersonatorID: actor.ImpersonatorID,
+		Action:         action,
+		TargetType:     targetType,
+		TargetID:       targetID,
+	}
+	if details != nil {
+		encoded, err := json.Marshal(details)
+		if err != nil {
+			return nil, err
+		}
+		entry.Details = string(encoded)
+	}
+	return entry, nil
+}
+
+// AuditFilter narrows an audit log listing
+type AuditFilter struct {
+	SocietyID uint // only used by the platform listing
+	Action    string
+	ActorID   uint
+	TargetID  uint
+	Since     *time.Time
+	Limit     int
+}
+
+// ListAudit returns the society's audit entries, newest first
+func ListAudit(ctx context.Context, societyID uint, filter AuditFilter) ([]models.AuditLog, error) {
+	return listAudit(database.Session(ctx).Where("society_id = ?", societyID), filter)
+}
+
+// ListPlatformAudit returns platform actions and impersonated actions across every society, newest first
+func ListPlatformAudit(ctx context.Context, filter AuditFilter) ([]models.AuditLog, error) {
+	query := database.Session(ctx).Where("action LIKE ? OR impersonator_id IS NOT NULL", "platform.%")
+	if filter.SocietyID != 0 {
+		query = query.Where("society_id = ?", filter.SocietyID)
+	}
+	return listAudit(query, filter)
+}
+
+func listAudit(query *gorm.DB, filter AuditFilter) ([]models.AuditLog, error) {
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.TargetID != 0 {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", *filter.Since)
+	}
+	if filter.Limit <= 0 || filter.Limit > 500 {
+		filter.Limit = 100
+	}
+
+	entries := []models.AuditLog{}
+	err := query.Order("created_at DESC, id DESC").Limit(filter.Limit).Find(&entries).Error
+	return entries, err
+}
@@ -1,41 +1,78 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"time"
 
-	"github.com/VinVorteX/flashtrack/config"
 	"github.com/VinVorteX/flashtrack/internal/models"
 	"github.com/VinVorteX/flashtrack/internal/repository"
 	"github.com/VinVorteX/flashtrack/internal/utils"
 )
 
-func Register(user models.User) error {
-	// Check if admin already exists for this society
-	if user.Role == "admin" {
-		existingAdmin, err := repository.FindAdminBySociety(user.SocietyID)
-		if err == nil && existingAdmin.ID != 0 {
-			return errors.New("admin already exists for this society")
-		}
+var (
+	ErrAccountDeactivated = errors.New("account is deactivated")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrEmailTaken         = errors.New("email is already registered")
+)
+
+// normalizeEmail is the form emails are stored and looked up in, since they are unique regardless of case
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// AuthService registers and signs in users, issuing tokens signed with JWTSecret that last TokenTTL
+type AuthService struct {
+	JWTSecret string
+	TokenTTL  time.Duration
+	Users     *UserService
+	Plans     *PlanService
+}
+
+// Register creates an account for someone joining a society on their own
+func (as *AuthService) Register(ctx context.Context, user models.User) error {
+	// Custom roles carry extra permissions, so only an admin can grant them
+	if user.Role != "" && !IsBuiltinRole(user.Role) {
+		return ErrInvalidRole
+	}
+	user.Email = normalizeEmail(user.Email)
+	if _, err := repository.FindUserByEmail(user.Email); err == nil {
+		return ErrEmailTaken
+	}
+	if err := as.Users.ValidateRegistration(ctx, user); err != nil {
+		return err
 	}
-	
+	if err := as.Plans.CheckSeats(ctx, user.SocietyID, user.Role, 1); err != nil {
+		return err
+	}
+
 	hashed := utils.HashPassword(user.Password)
 	user.Password = hashed
 	return repository.CreateUser(user)
 }
 
-func Login(email, password string) (string, models.User, error) {
-	user, err := repository.FindUserByEmail(email)
+func (as *AuthService) Login(email, password string) (string, models.User, error) {
+	user, err := repository.FindUserByEmail(normalizeEmail(email))
 	if err != nil {
-		return "", models.User{}, errors.New("user not found")
+		return "", models.User{}, ErrInvalidCredentials
 	}
 
 	if !utils.CheckPassword(user.Password, password) {
-		return "", models.User{}, errors.New("wrong password")
+		return "", models.User{}, ErrInvalidCredentials
+	}
+
+	if !user.IsActive() {
+		return "", models.User{}, ErrAccountDeactivated
+	}
+
+	if suspended, err := IsSocietySuspended(user.SocietyID); err != nil {
+		return "", models.User{}, err
+	} else if suspended {
+		return "", models.User{}, ErrSocietySuspended
 	}
 
-	cfg := config.LoadConfig()
-	token, err := utils.GenerateJWT(&user, cfg.JWTSecret, 24*time.Hour)
+	token, err := utils.GenerateJWT(&user, as.JWTSecret, as.TokenTTL)
 	if err != nil {
 		return "", models.User{}, err
 	}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/repository"
+)
+
+var (
+	// ErrCategoryNotFound is returned for categories that are neither the society's own nor shared
+	ErrCategoryNotFound = errors.New("category not found")
+	// ErrCategoryNotInSociety is returned when a category does not belong to the society
+	ErrCategoryNotInSociety = errors.New("category does not belong to this society")
+)
+
+// CategoryChanges are the category settings an admin may change; nil fields are left alone
+type CategoryChanges struct {
+	SLAHours        *int
+	DefaultPriority *string
+}
+
+// CategoryService manages complaint categories and their SLA defaults
+type CategoryService struct {
+	Categories repository.CategoryRepository
+}
+
+// List returns the categories available to the society, including shared ones
+func (cs *CategoryService) List(ctx context.Context, societyID uint) ([]models.Category, error) {
+	return cs.Categories.ListAvailable(ctx, societyID)
+}
+
+// Update changes one of the society's own categories. Shared categories are not owned by any
+// one society, so they cannot be changed and are reported as not found.
+func (cs *CategoryService) Update(ctx context.Context, societyID, categoryID uint, changes CategoryChanges) (*models.Category, error) {
+	category, err := cs.Categories.FindOwned(ctx, societyID, categoryID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if changes.SLAHours != nil {
+		category.SLAHours = *changes.SLAHours
+	}
+	if changes.DefaultPriority != nil {
+		category.DefaultPriority = *changes.DefaultPriority
+	}
+
+	if err := cs.Categories.Save(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
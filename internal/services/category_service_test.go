@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+)
+
+func TestUpdateCategoryOnlyChangesOwnCategories(t *testing.T) {
+	service := &CategoryService{Categories: newFakeCategories(
+		models.Category{ID: 1, Name: "Shared"},
+		models.Category{ID: 2, Name: "Own", SocietyID: testSociety, SLAHours: 24},
+	)}
+	hours := 8
+
+	if _, err := service.Update(context.Background(), testSociety, 1, CategoryChanges{SLAHours: &hours}); !errors.Is(err, ErrCategoryNotFound) {
+		t.Errorf("shared category: expected ErrCategoryNotFound, got %v", err)
+	}
+
+	category, err := service.Update(context.Background(), testSociety, 2, CategoryChanges{SLAHours: &hours})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if category.SLAHours != 8 {
+		t.Errorf("sla hours = %d, want 8", category.SLAHours)
+	}
+}
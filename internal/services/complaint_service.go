@@ -0,0 +1,613 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/dto"
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/repository"
+)
+
+// DefaultReopenWindowHours applies when a society has not configured its own reopen window
+const DefaultReopenWindowHours = 72
+
+var (
+	ErrComplaintNotFound  = errors.New("complaint not found")
+	ErrNotComplaintOwner  = errors.New("you can only change your own complaints")
+	ErrNotAssignee        = errors.New("you can only resolve complaints assigned to you")
+	ErrMergedComplaint    = errors.New("this complaint was merged into another; act on the primary complaint instead")
+	ErrNotResolved        = errors.New("only resolved complaints can be reopened")
+	ErrReopenWindowClosed = errors.New("the reopen window for this complaint has closed")
+	ErrNotPending         = errors.New("only pending complaints can be edited")
+	ErrNotCancellable     = errors.New("only open complaints can be withdrawn")
+	ErrHasDuplicates      = errors.New("complaint has merged duplicates; ask an admin to withdraw it")
+	ErrInvalidMerge       = errors.New("complaints must be open, distinct, in your society and not already merged")
+	ErrStaffNotFound      = errors.New("staff not found")
+	ErrNotStaffMember     = errors.New("selected user is not a staff member")
+	ErrStaffDeactivated   = errors.New("selected staff member is deactivated")
+)
+
+// StaffUnavailableError is returned when an admin assigns a complaint to staff who are off
+// shift, on leave or at capacity without forcing it
+type StaffUnavailableError struct {
+	Availability StaffAvailability
+}
+
+func (e *StaffUnavailableError) Error() string {
+	return e.Availability.Reason() + "; set force to assign anyway"
+}
+
+// ComplaintService handles complaint creation, listing and lifecycle transitions
+type ComplaintService struct {
+	Complaints repository.ComplaintRepository
+	Categories repository.CategoryRepository
+	Feedback   repository.FeedbackRepository
+	Users      repository.UserRepository
+	Societies  repository.SocietyRepository
+	Tx         repository.Transactor
+
+	Notifications *NotificationService
+	Assignment    *AssignmentService
+	Staff         *StaffService
+	Locations     *LocationService
+	Roles         *RoleService
+}
+
+// ComplaintListOptions are the listing filters a caller may ask for
+type ComplaintListOptions struct {
+	IncludeDuplicates bool
+	LocationID        *uint // the location and everything beneath it
+	SortByPriority    bool
+}
+
+// NewComplaint is a resident's report
+type NewComplaint struct {
+	Title       string
+	Description string
+	CategoryID  uint
+	Priority    string // proposed by the resident, may be empty
+	IsEmergency bool
+	LocationID  *uint // defaults to the resident's unit
+}
+
+// Filter returns the complaints the user's permissions let them list, narrowed by opts
+func (cs *ComplaintService) Filter(ctx context.Context, user *models.User, permissions PermissionSet, opts ComplaintListOptions) (repository.ComplaintFilter, error) {
+	filter := repository.ComplaintFilter{SocietyID: user.SocietyID}
+
+	// Staff see complaints assigned to them, residents their own, including ones merged into another report
+	filter.All = permissions.Has(PermComplaintViewAll)
+	if permissions.Has(PermComplaintViewAssigned) {
+		filter.AssignedTo = &user.ID
+	}
+	if permissions.Has(PermComplaintViewOwn) {
+		filter.ReportedBy = &user.ID
+	}
+
+	// Merged duplicates follow their primary complaint, so hide them from those who work the queue unless asked for
+	worksQueue := permissions.Has(PermComplaintViewAll) || permissions.Has(PermComplaintViewAssigned)
+	filter.ExcludeDuplicates = worksQueue && !opts.IncludeDuplicates
+
+	if opts.LocationID != nil {
+		ids, err := cs.Locations.DescendantIDs(ctx, user.SocietyID, *opts.LocationID)
+		if err != nil {
+			return filter, err
+		}
+		filter.LocationIDs = ids
+	}
+
+	if opts.SortByPriority {
+		filter.Order = PriorityOrderSQL("complaints")
+	}
+
+	return filter, nil
+}
+
+// List returns the filtered complaints with the names of their resident, staff and category
+func (cs *ComplaintService) List(ctx context.Context, filter repository.ComplaintFilter) ([]dto.ComplaintDetails, error) {
+	complaints, err := cs.Complaints.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var userIDs []uint
+	for _, complaint := range complaints {
+		userIDs = append(userIDs, complaint.ResidentID)
+		if complaint.StaffID != nil {
+			userIDs = append(userIDs, *complaint.StaffID)
+		}
+	}
+	names, err := cs.Users.Names(ctx, uniqueIDs(userIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := cs.Categories.ListAvailable(ctx, filter.SocietyID)
+	if err != nil {
+		return nil, err
+	}
+	categoryNames := make(map[uint]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.ID] = category.Name
+	}
+
+	// Always an array and never null
+	details := []dto.ComplaintDetails{}
+	for i := range complaints {
+		complaint := &complaints[i]
+
+		var staffName *string
+		if complaint.StaffID != nil {
+			if name, ok := names[*complaint.StaffID]; ok {
+				staffName = &name
+			}
+		}
+		categoryName, ok := categoryNames[complaint.CategoryID]
+		if !ok {
+			categoryName = "General"
+		}
+
+		details = append(details, dto.ComplaintDetails{
+			ComplaintResponse: dto.NewComplaintResponse(complaint),
+			ResidentName:      names[complaint.ResidentID],
+			StaffName:         staffName,
+			CategoryName:      categoryName,
+		})
+	}
+	return details, nil
+}
+
+// Find returns one of the society's complaints
+func (cs *ComplaintService) Find(ctx context.Context, societyID, id uint) (*models.Complaint, error) {
+	complaint, err := cs.Complaints.Find(ctx, societyID, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrComplaintNotFound
+	}
+	return complaint, err
+}
+
+// Create files a resident's complaint. The category's defaults decide the priority and SLA
+// unless the resident proposes otherwise, and the complaint is assigned according to the
+// society's strategy; it stays pending when that fails. The assigned staff member is returned.
+func (cs *ComplaintService) Create(ctx context.Context, user *models.User, input NewComplaint) (*models.Complaint, *models.Category, *models.User, error) {
+	locationID := input.LocationID
+	if locationID == nil {
+		locationID = user.UnitID
+	} else if err := cs.Locations.ValidateComplaintLocation(ctx, user, *locationID); err != nil {
+		return nil, nil, nil, err
+	}
+
+	category, err := cs.Categories.FindAvailable(ctx, user.SocietyID, input.CategoryID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil, nil, ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	now := time.Now()
+	priority := ResolvePriority(input.Priority, category.DefaultPriority, input.IsEmergency)
+	dueAt := SLADeadline(now, category.SLAHours, priority)
+
+	complaint := &models.Complaint{
+		Title:            input.Title,
+		Description:      input.Description,
+		Status:           "pending",
+		ResidentID:       user.ID,
+		SocietyID:        user.SocietyID,
+		CategoryID:       input.CategoryID,
+		LocationID:       locationID,
+		Priority:         priority,
+		ProposedPriority: input.Priority,
+		IsEmergency:      input.IsEmergency,
+		DueAt:            &dueAt,
+	}
+	if err := cs.Complaints.Create(ctx, complaint); err != nil {
+		return nil, nil, nil, err
+	}
+
+	var staff *models.User
+	if cs.Assignment != nil {
+		if staff, err = cs.Assignment.AutoAssign(ctx, complaint); err != nil {
+			slog.ErrorContext(ctx, "auto-assignment failed", "complaint_id", complaint.ID, "error", err)
+			staff = nil
+		}
+	}
+
+	return complaint, category, staff, nil
+}
+
+// Assign hands a complaint to a staff member of the society and notifies them. Staff who are
+// off shift or at capacity are refused with a StaffUnavailableError unless force is set, in
+// which case the reason is returned as a warning. notified is false when the notification failed.
+func (cs *ComplaintService) Assign(ctx context.Context, actor *models.User, complaintID, staffID uint, force bool) (complaint *models.Complaint, warning string, notified bool, err error) {
+	complaint, err = cs.Find(ctx, actor.SocietyID, complaintID)
+	if err != nil {
+		return nil, "", false, err
+	}
+	if complaint.DuplicateOfID != nil {
+		return nil, "", false, ErrMergedComplaint
+	}
+
+	staff, err := cs.Users.Find(ctx, staffID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && staff.SocietyID != actor.SocietyID) {
+		return nil, "", false, ErrStaffNotFound
+	}
+	if err != nil {
+		return nil, "", false, err
+	}
+	if isStaff, err := cs.Roles.IsStaffRole(ctx, staff.SocietyID, staff.Role); err != nil {
+		return nil, "", false, err
+	} else if !isStaff {
+		return nil, "", false, ErrNotStaffMember
+	}
+	if !staff.IsActive() {
+		return nil, "", false, ErrStaffDeactivated
+	}
+
+	if cs.Staff != nil {
+		availability, err := cs.Staff.Availability(ctx, actor.SocietyID, []uint{staff.ID}, time.Now())
+		if err != nil {
+			return nil, "", false, err
+		}
+		if a := availability[staff.ID]; !a.Available() {
+			if !force {
+				return nil, "", false, &StaffUnavailableError{Availability: a}
+			}
+			warning = a.Reason()
+		}
+	}
+
+	assignComplaint(complaint, staff.ID, time.Now())
+	if err := cs.Complaints.Save(ctx, complaint); err != nil {
+		return nil, "", false, err
+	}
+
+	// Keep merged duplicates in step and tell every reporter
+	if err := cs.SyncDuplicates(ctx, complaint, actor.ID); err != nil {
+		slog.ErrorContext(ctx, "failed to sync duplicates of complaint", "complaint_id", complaint.ID, "error", err)
+	}
+
+	notified = cs.Notifications != nil && cs.Notifications.NotifyStaffAssignment(ctx, staff.ID, complaint) == nil
+	return complaint, warning, notified, nil
+}
+
+// assignComplaint hands the complaint to the staff member at time now. The new assignee gets a
+// fresh escalation clock, so the level reached before the assignment is cleared. The first
+// assignment is kept for the time-to-assign metric.
+func assignComplaint(complaint *models.Complaint, staffID uint, now time.Time) {
+	complaint.StaffID = &staffID
+	complaint.Status = "in-progress"
+	complaint.AssignedAt = &now
+	if complaint.FirstAssignedAt == nil {
+		complaint.FirstAssignedAt = &now
+	}
+	complaint.EscalationLevel = 0
+	complaint.EscalatedAt = nil
+}
+
+// Resolve marks a complaint assigned to the staff member as resolved. Points are awarded
+// later, when the resident leaves feedback.
+func (cs *ComplaintService) Resolve(ctx context.Context, staff *models.User, complaintID uint) (*models.Complaint, error) {
+	complaint, err := cs.Find(ctx, staff.SocietyID, complaintID)
+	if err != nil {
+		return nil, err
+	}
+	if complaint.StaffID == nil || *complaint.StaffID != staff.ID {
+		return nil, ErrNotAssignee
+	}
+	if complaint.DuplicateOfID != nil {
+		return nil, ErrMergedComplaint
+	}
+
+	now := time.Now()
+	complaint.Status = "resolved"
+	complaint.ResolvedAt = &now
+	if err := cs.Complaints.Save(ctx, complaint); err != nil {
+		return nil, err
+	}
+
+	if err := cs.SyncDuplicates(ctx, complaint, staff.ID); err != nil {
+		slog.ErrorContext(ctx, "failed to sync duplicates of complaint", "complaint_id", complaint.ID, "error", err)
+	}
+	return complaint, nil
+}
+
+// SetPriority overrides a complaint's priority and optionally its emergency flag. The SLA
+// deadline is recalculated from the original creation time, and newly flagged emergencies
+// that nobody handles yet go through emergency assignment.
+func (cs *ComplaintService) SetPriority(ctx context.Context, complaint *models.Complaint, priority string, isEmergency *bool) error {
+	slaHours := 0
+	if category, err := cs.Categories.FindAvailable(ctx, complaint.SocietyID, complaint.CategoryID); err == nil {
+		slaHours = category.SLAHours
+	}
+
+	wasEmergency := complaint.IsEmergency
+	if isEmergency != nil {
+		complaint.IsEmergency = *isEmergency
+	}
+	complaint.Priority = priority
+	dueAt := SLADeadline(complaint.CreatedAt, slaHours, complaint.Priority)
+	complaint.DueAt = &dueAt
+
+	if err := cs.Complaints.Save(ctx, complaint); err != nil {
+		return err
+	}
+
+	if complaint.IsEmergency && !wasEmergency && complaint.StaffID == nil && cs.Assignment != nil {
+		if _, err := cs.Assignment.AutoAssign(ctx, complaint); err != nil {
+			slog.ErrorContext(ctx, "emergency assignment failed", "complaint_id", complaint.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// reopenWindow returns how long after resolution the society's residents may reopen a complaint
+func (cs *ComplaintService) reopenWindow(ctx context.Context, societyID uint) time.Duration {
+	hours := DefaultReopenWindowHours
+	if society, err := cs.Societies.Find(ctx, societyID); err == nil && society.ReopenWindowHours > 0 {
+		hours = society.ReopenWindowHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// Reopen sends a resolved complaint back to its assigned staff, reverses any points awarded
+// for the resolution and notifies the staff member and the society's admins
+func (cs *ComplaintService) Reopen(ctx context.Context, complaint *models.Complaint, user *models.User, reason string) (*models.ComplaintReopen, error) {
+	if complaint.DuplicateOfID != nil {
+		return nil, ErrMergedComplaint
+	}
+	if complaint.ResidentID != user.ID {
+		return nil, ErrNotComplaintOwner
+	}
+	if complaint.Status != "resolved" {
+		return nil, ErrNotResolved
+	}
+	// Complaints resolved before resolved_at was recorded fall back to their last update
+	resolvedAt := complaint.UpdatedAt
+	if complaint.ResolvedAt != nil {
+		resolvedAt = *complaint.ResolvedAt
+	}
+	if time.Since(resolvedAt) > cs.reopenWindow(ctx, complaint.SocietyID) {
+		return nil, ErrReopenWindowClosed
+	}
+
+	reopen := models.ComplaintReopen{
+		ComplaintID: complaint.ID,
+		UserID:      user.ID,
+		Reason:      reason,
+	}
+
+	err := cs.Tx.Transaction(ctx, func(ctx context.Context) error {
+		// Take back the points the staff member earned from feedback on this resolution
+		feedback, err := cs.Feedback.FindActive(ctx, complaint.ID)
+		if err == nil {
+			if err := cs.Feedback.Reverse(ctx, feedback); err != nil {
+				return err
+			}
+			reopen.PointsReversed = feedback.Points
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+
+		complaint.Status = "pending"
+		if complaint.StaffID != nil {
+			complaint.Status = "in-progress"
+		}
+		complaint.ResolvedAt = nil
+		complaint.ReopenCount++
+		// The reopened complaint is escalated afresh, as if it had just been raised
+		now := time.Now()
+		complaint.ReopenedAt = &now
+		complaint.EscalationLevel = 0
+		complaint.EscalatedAt = nil
+		if err := cs.Complaints.Save(ctx, complaint); err != nil {
+			return err
+		}
+
+		return cs.Complaints.CreateReopen(ctx, &reopen)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cs.notifyReopened(ctx, complaint, reason)
+	if err := cs.SyncDuplicates(ctx, complaint, user.ID); err != nil {
+		slog.ErrorContext(ctx, "failed to sync duplicates of reopened complaint", "complaint_id", complaint.ID, "error", err)
+	}
+
+	return &reopen, nil
+}
+
+// notifyReopened tells the assigned staff member and the society's admins that a complaint was reopened
+func (cs *ComplaintService) notifyReopened(ctx context.Context, complaint *models.Complaint, reason string) {
+	if cs.Notifications == nil {
+		return
+	}
+
+	recipients, err := cs.Users.ActiveIDs(ctx, complaint.SocietyID, "admin")
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to load admins for reopened complaint", "complaint_id", complaint.ID, "error", err)
+	}
+	if complaint.StaffID != nil {
+		recipients = append(recipients, *complaint.StaffID)
+	}
+
+	title := "Complaint Reopened"
+	message := fmt.Sprintf("Complaint #%d: %s was reopened by the resident: %s", complaint.ID, complaint.Title, reason)
+	for _, id := range uniqueIDs(recipients) {
+		if _, err := cs.Notifications.CreateNotification(ctx, id, title, message, "reopened", &complaint.ID); err != nil {
+			slog.ErrorContext(ctx, "failed to notify user of reopened complaint", "user_id", id, "complaint_id", complaint.ID, "error", err)
+		}
+	}
+}
+
+// Edit updates the title, description or category of a resident's complaint while it is still pending
+func (cs *ComplaintService) Edit(ctx context.Context, complaint *models.Complaint, user *models.User, title, description *string, categoryID *uint) error {
+	if complaint.ResidentID != user.ID {
+		return ErrNotComplaintOwner
+	}
+	if complaint.Status != "pending" {
+		return ErrNotPending
+	}
+
+	if title != nil {
+		complaint.Title = *title
+	}
+	if description != nil {
+		complaint.Description = *description
+	}
+	if categoryID != nil {
+		_, err := cs.Categories.FindAvailable(ctx, complaint.SocietyID, *categoryID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrCategoryNotInSociety
+		}
+		if err != nil {
+			return err
+		}
+		complaint.CategoryID = *categoryID
+	}
+
+	return cs.Complaints.Save(ctx, complaint)
+}
+
+// Cancel withdraws a resident's open complaint and lets the assigned staff member know
+func (cs *ComplaintService) Cancel(ctx context.Context, complaint *models.Complaint, user *models.User) error {
+	if complaint.ResidentID != user.ID {
+		return ErrNotComplaintOwner
+	}
+	// A merged duplicate follows its primary, which would overwrite the cancellation
+	if complaint.DuplicateOfID != nil {
+		return ErrMergedComplaint
+	}
+	if complaint.Status != "pending" && complaint.Status != "in-progress" {
+		return ErrNotCancellable
+	}
+
+	// Other residents' reports follow this one, so it cannot simply disappear
+	duplicates, err := cs.Complaints.CountDuplicates(ctx, complaint.ID)
+	if err != nil {
+		return err
+	}
+	if duplicates > 0 {
+		return ErrHasDuplicates
+	}
+
+	complaint.Status = "cancelled"
+	if err := cs.Complaints.Save(ctx, complaint); err != nil {
+		return err
+	}
+
+	if cs.Notifications != nil && complaint.StaffID != nil {
+		message := fmt.Sprintf("Complaint #%d: %s was withdrawn by the resident", complaint.ID, complaint.Title)
+		if _, err := cs.Notifications.CreateNotification(ctx, *complaint.StaffID, "Complaint Withdrawn", message, "status_update", &complaint.ID); err != nil {
+			slog.ErrorContext(ctx, "failed to notify staff of withdrawn complaint", "staff_id", *complaint.StaffID, "complaint_id", complaint.ID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// Merge links the duplicate complaints to the primary one. Duplicates take on the primary's
+// status and staff, and their reporters are told which complaint now tracks their report.
+func (cs *ComplaintService) Merge(ctx context.Context, societyID, primaryID uint, duplicateIDs []uint) (*models.Complaint, []models.Complaint, error) {
+	duplicateIDs = uniqueIDs(duplicateIDs)
+	for _, id := range duplicateIDs {
+		if id == primaryID {
+			return nil, nil, ErrInvalidMerge
+		}
+	}
+
+	// Duplicates take on the primary's status, so a closed primary would close them unattended
+	primary, err := cs.Complaints.Find(ctx, societyID, primaryID)
+	if err != nil || primary.DuplicateOfID != nil || !slices.Contains(openStatuses, primary.Status) {
+		return nil, nil, ErrInvalidMerge
+	}
+
+	duplicates, err := cs.Complaints.FindUnmerged(ctx, societyID, duplicateIDs, openStatuses)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(duplicates) != len(duplicateIDs) {
+		return nil, nil, ErrInvalidMerge
+	}
+
+	err = cs.Tx.Transaction(ctx, func(ctx context.Context) error {
+		// Anything already merged into a duplicate moves to the new primary
+		if err := cs.Complaints.Repoint(ctx, duplicateIDs, primary.ID); err != nil {
+			return err
+		}
+
+		for i := range duplicates {
+			duplicates[i].DuplicateOfID = &primary.ID
+			if err := cs.Complaints.Save(ctx, &duplicates[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if cs.Notifications != nil {
+		for _, d := range duplicates {
+			message := fmt.Sprintf("Your complaint #%d was merged into complaint #%d: %s. You will receive its updates.", d.ID, primary.ID, primary.Title)
+			if _, err := cs.Notifications.CreateNotification(ctx, d.ResidentID, "Complaint Merged", message, "merged", &d.ID); err != nil {
+				slog.ErrorContext(ctx, "failed to notify resident of merge", "user_id", d.ResidentID, "complaint_id", d.ID, "error", err)
+			}
+		}
+	}
+
+	if err := cs.Complaints.SyncDuplicates(ctx, primary); err != nil {
+		return nil, nil, err
+	}
+
+	return primary, duplicates, nil
+}
+
+// Release puts the staff member's open complaints, duplicates included, back to pending and
+// returns the primaries for reassignment
+func (cs *ComplaintService) Release(ctx context.Context, staffID uint) ([]models.Complaint, error) {
+	return cs.Complaints.Release(ctx, staffID, openStatuses)
+}
+
+// SyncDuplicates copies the primary complaint's status and assignment onto its duplicates
+// and notifies every reporter other than actorID about the primary's current status
+func (cs *ComplaintService) SyncDuplicates(ctx context.Context, primary *models.Complaint, actorID uint) error {
+	duplicates, err := cs.Complaints.Duplicates(ctx, primary.ID)
+	if err != nil {
+		return err
+	}
+	if len(duplicates) > 0 {
+		if err := cs.Complaints.SyncDuplicates(ctx, primary); err != nil {
+			return err
+		}
+	}
+
+	if cs.Notifications == nil {
+		return nil
+	}
+
+	reporters := []uint{primary.ResidentID}
+	for _, d := range duplicates {
+		reporters = append(reporters, d.ResidentID)
+	}
+
+	title := "Complaint Status Updated"
+	message := fmt.Sprintf("Complaint #%d: %s is now %s", primary.ID, primary.Title, primary.Status)
+	for _, id := range uniqueIDs(reporters) {
+		if id == actorID {
+			continue
+		}
+		if _, err := cs.Notifications.CreateNotification(ctx, id, title, message, "status_update", &primary.ID); err != nil {
+			slog.ErrorContext(ctx, "failed to notify reporter of status update", "user_id", id, "error", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,482 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+)
+
+const testSociety = 1
+
+var (
+	testAdmin    = models.User{ID: 10, Name: "Admin", Role: "admin", SocietyID: testSociety}
+	testStaff    = models.User{ID: 20, Name: "Staff", Role: "staff", SocietyID: testSociety}
+	testResident = models.User{ID: 30, Name: "Resident", Role: "user", SocietyID: testSociety}
+	testNeighbor = models.User{ID: 31, Name: "Neighbor", Role: "user", SocietyID: testSociety}
+)
+
+// complaintFixture is a ComplaintService backed by fakes, with the fakes exposed for assertions
+type complaintFixture struct {
+	service       *ComplaintService
+	complaints    *fakeComplaints
+	feedback      *fakeFeedback
+	notifications *fakeNotifications
+}
+
+func newComplaintFixture(complaints ...models.Complaint) *complaintFixture {
+	f := &complaintFixture{
+		complaints:    newFakeComplaints(complaints...),
+		feedback:      newFakeFeedback(),
+		notifications: &fakeNotifications{},
+	}
+	users := newFakeUsers(testAdmin, testStaff, testResident, testNeighbor)
+	f.service = &ComplaintService{
+		Complaints: f.complaints,
+		Categories: newFakeCategories(models.Category{ID: 1, Name: "Plumbing", SocietyID: testSociety}),
+		Feedback:   f.feedback,
+		Users:      users,
+		Societies:  &fakeSocieties{rows: map[uint]*models.Society{testSociety: {ID: testSociety, ReopenWindowHours: 24}}},
+		Tx:         fakeTx{},
+		Notifications: &NotificationService{
+			Notifications: f.notifications,
+			Users:         users,
+		},
+		Roles: &RoleService{},
+	}
+	return f
+}
+
+func resolvedComplaint(id uint, resolvedAgo time.Duration) models.Complaint {
+	staffID := testStaff.ID
+	resolvedAt := time.Now().Add(-resolvedAgo)
+	return models.Complaint{
+		ID: id, Title: "Leaking tap", Status: "resolved", SocietyID: testSociety,
+		ResidentID: testResident.ID, StaffID: &staffID, CategoryID: 1, ResolvedAt: &resolvedAt,
+	}
+}
+
+func TestReopenReversesPointsAndNotifies(t *testing.T) {
+	f := newComplaintFixture(resolvedComplaint(1, time.Hour))
+	ctx := context.Background()
+	f.feedback.Create(ctx, &models.Feedback{ComplaintID: 1, UserID: testResident.ID, StaffID: testStaff.ID, Rating: 4, Points: 8})
+	f.feedback.AwardPoints(ctx, testStaff.ID, 8)
+
+	complaint, _ := f.complaints.Find(ctx, testSociety, 1)
+	reopen, err := f.service.Reopen(ctx, complaint, &testResident, "still leaking")
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+
+	if reopen.PointsReversed != 8 {
+		t.Errorf("points reversed = %d, want 8", reopen.PointsReversed)
+	}
+	if points, _ := f.feedback.StaffPoints(ctx, testStaff.ID); points.TotalPoints != 0 {
+		t.Errorf("staff kept %d points", points.TotalPoints)
+	}
+	stored := f.complaints.rows[1]
+	if stored.Status != "in-progress" || stored.ResolvedAt != nil || stored.ReopenCount != 1 {
+		t.Errorf("complaint not reopened: status %q, resolved %v, count %d", stored.Status, stored.ResolvedAt, stored.ReopenCount)
+	}
+	if len(f.complaints.reopens) != 1 {
+		t.Errorf("recorded %d reopens, want 1", len(f.complaints.reopens))
+	}
+
+	notified := map[uint]bool{}
+	for _, id := range f.notifications.recipients("reopened") {
+		notified[id] = true
+	}
+	if !notified[testStaff.ID] || !notified[testAdmin.ID] || notified[testResident.ID] {
+		t.Errorf("reopen notified %v, want the staff member and the admin", f.notifications.recipients("reopened"))
+	}
+}
+
+func TestReopenRefusedAfterWindow(t *testing.T) {
+	f := newComplaintFixture(resolvedComplaint(1, 48*time.Hour))
+	complaint, _ := f.complaints.Find(context.Background(), testSociety, 1)
+
+	if _, err := f.service.Reopen(context.Background(), complaint, &testResident, "again"); !errors.Is(err, ErrReopenWindowClosed) {
+		t.Fatalf("expected ErrReopenWindowClosed, got %v", err)
+	}
+	if f.complaints.rows[1].Status != "resolved" {
+		t.Error("complaint changed despite the closed window")
+	}
+}
+
+func TestReopenWithoutResolvedAtUsesLastUpdate(t *testing.T) {
+	legacy := resolvedComplaint(1, 0)
+	legacy.ResolvedAt = nil
+	legacy.UpdatedAt = time.Now().Add(-48 * time.Hour)
+	f := newComplaintFixture(legacy)
+	complaint, _ := f.complaints.Find(context.Background(), testSociety, 1)
+
+	if _, err := f.service.Reopen(context.Background(), complaint, &testResident, "again"); !errors.Is(err, ErrReopenWindowClosed) {
+		t.Fatalf("expected ErrReopenWindowClosed, got %v", err)
+	}
+}
+
+func TestReopenRefusedForOtherResidents(t *testing.T) {
+	f := newComplaintFixture(resolvedComplaint(1, time.Hour))
+	complaint, _ := f.complaints.Find(context.Background(), testSociety, 1)
+
+	if _, err := f.service.Reopen(context.Background(), complaint, &testNeighbor, "not mine"); !errors.Is(err, ErrNotComplaintOwner) {
+		t.Fatalf("expected ErrNotComplaintOwner, got %v", err)
+	}
+}
+
+func TestReopenWithoutStaffGoesBackToPending(t *testing.T) {
+	unassigned := resolvedComplaint(1, time.Hour)
+	unassigned.StaffID = nil
+	f := newComplaintFixture(unassigned)
+	complaint, _ := f.complaints.Find(context.Background(), testSociety, 1)
+
+	if _, err := f.service.Reopen(context.Background(), complaint, &testResident, "still broken"); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got := f.complaints.rows[1].Status; got != "pending" {
+		t.Errorf("status = %q, want pending with nobody to send it back to", got)
+	}
+	if got := f.notifications.recipients("reopened"); len(got) != 1 || got[0] != testAdmin.ID {
+		t.Errorf("reopen notified %v, want only the admin", got)
+	}
+}
+
+func TestReopenCountsEveryCycle(t *testing.T) {
+	f := newComplaintFixture(resolvedComplaint(1, time.Hour))
+	ctx := context.Background()
+
+	for cycle := 1; cycle <= 2; cycle++ {
+		complaint, _ := f.complaints.Find(ctx, testSociety, 1)
+		if _, err := f.service.Reopen(ctx, complaint, &testResident, "again"); err != nil {
+			t.Fatalf("reopen %d: %v", cycle, err)
+		}
+		// Reopening an open complaint is refused until staff resolve it again
+		complaint, _ = f.complaints.Find(ctx, testSociety, 1)
+		if _, err := f.service.Reopen(ctx, complaint, &testResident, "again"); !errors.Is(err, ErrNotResolved) {
+			t.Fatalf("reopen of an open complaint: expected ErrNotResolved, got %v", err)
+		}
+		if _, err := f.service.Resolve(ctx, &testStaff, 1); err != nil {
+			t.Fatalf("resolve %d: %v", cycle, err)
+		}
+	}
+	if got := f.complaints.rows[1].ReopenCount; got != 2 {
+		t.Errorf("reopen count = %d, want 2", got)
+	}
+	if len(f.complaints.reopens) != 2 {
+		t.Errorf("recorded %d reopens, want 2", len(f.complaints.reopens))
+	}
+}
+
+func TestReopenUsesTheDefaultWindow(t *testing.T) {
+	f := newComplaintFixture(resolvedComplaint(1, 48*time.Hour), resolvedComplaint(2, 96*time.Hour))
+	f.service.Societies = &fakeSocieties{rows: map[uint]*models.Society{testSociety: {ID: testSociety}}}
+	ctx := context.Background()
+
+	recent, _ := f.complaints.Find(ctx, testSociety, 1)
+	if _, err := f.service.Reopen(ctx, recent, &testResident, "again"); err != nil {
+		t.Errorf("reopen within %d hours: %v", DefaultReopenWindowHours, err)
+	}
+	old, _ := f.complaints.Find(ctx, testSociety, 2)
+	if _, err := f.service.Reopen(ctx, old, &testResident, "again"); !errors.Is(err, ErrReopenWindowClosed) {
+		t.Errorf("reopen after %d hours: expected ErrReopenWindowClosed, got %v", DefaultReopenWindowHours, err)
+	}
+}
+
+func TestReopenTakesDuplicatesAlong(t *testing.T) {
+	primaryID := uint(1)
+	duplicate := resolvedComplaint(2, time.Hour)
+	duplicate.ResidentID = testNeighbor.ID
+	duplicate.DuplicateOfID = &primaryID
+	f := newComplaintFixture(resolvedComplaint(1, time.Hour), duplicate)
+	ctx := context.Background()
+
+	// Duplicates are reopened through their primary
+	merged, _ := f.complaints.Find(ctx, testSociety, 2)
+	if _, err := f.service.Reopen(ctx, merged, &testNeighbor, "mine too"); !errors.Is(err, ErrMergedComplaint) {
+		t.Fatalf("reopen of a duplicate: expected ErrMergedComplaint, got %v", err)
+	}
+
+	primary, _ := f.complaints.Find(ctx, testSociety, 1)
+	if _, err := f.service.Reopen(ctx, primary, &testResident, "still leaking"); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if d := f.complaints.rows[2]; d.Status != "in-progress" || d.ResolvedAt != nil {
+		t.Errorf("duplicate status %q resolved %v, want it reopened with its primary", d.Status, d.ResolvedAt)
+	}
+	if got := f.notifications.recipients("status_update"); len(got) != 1 || got[0] != testNeighbor.ID {
+		t.Errorf("status updates to %v, want the duplicate's reporter", got)
+	}
+}
+
+func TestCancelRefusedWithDuplicates(t *testing.T) {
+	primaryID := uint(1)
+	f := newComplaintFixture(
+		models.Complaint{ID: 1, Status: "pending", SocietyID: testSociety, ResidentID: testResident.ID},
+		models.Complaint{ID: 2, Status: "pending", SocietyID: testSociety, ResidentID: testNeighbor.ID, DuplicateOfID: &primaryID},
+	)
+	complaint, _ := f.complaints.Find(context.Background(), testSociety, 1)
+
+	if err := f.service.Cancel(context.Background(), complaint, &testResident); !errors.Is(err, ErrHasDuplicates) {
+		t.Fatalf("expected ErrHasDuplicates, got %v", err)
+	}
+	if f.complaints.rows[1].Status != "pending" {
+		t.Error("complaint was cancelled")
+	}
+}
+
+func TestCancelNotifiesAssignedStaff(t *testing.T) {
+	staffID := testStaff.ID
+	f := newComplaintFixture(models.Complaint{ID: 1, Status: "in-progress", SocietyID: testSociety, ResidentID: testResident.ID, StaffID: &staffID})
+	complaint, _ := f.complaints.Find(context.Background(), testSociety, 1)
+
+	if err := f.service.Cancel(context.Background(), complaint, &testResident); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if f.complaints.rows[1].Status != "cancelled" {
+		t.Errorf("status = %q, want cancelled", f.complaints.rows[1].Status)
+	}
+	if got := f.notifications.recipients("status_update"); len(got) != 1 || got[0] != testStaff.ID {
+		t.Errorf("notified %v, want the assigned staff member", got)
+	}
+}
+
+func TestCancelRefusedForMergedDuplicates(t *testing.T) {
+	staffID := testStaff.ID
+	f := newComplaintFixture(
+		models.Complaint{ID: 1, Title: "No water", Status: "in-progress", SocietyID: testSociety, ResidentID: testResident.ID, StaffID: &staffID},
+		models.Complaint{ID: 2, Title: "Dry taps", Status: "pending", SocietyID: testSociety, ResidentID: testNeighbor.ID},
+	)
+	if _, _, err := f.service.Merge(context.Background(), testSociety, 1, []uint{2}); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	duplicate, _ := f.complaints.Find(context.Background(), testSociety, 2)
+	if err := f.service.Cancel(context.Background(), duplicate, &testNeighbor); !errors.Is(err, ErrMergedComplaint) {
+		t.Fatalf("expected ErrMergedComplaint, got %v", err)
+	}
+
+	if _, err := f.service.Resolve(context.Background(), &testStaff, 1); err != nil {
+		t.Fatalf("resolve primary: %v", err)
+	}
+	if got := f.complaints.rows[2].Status; got != "resolved" {
+		t.Errorf("duplicate status = %q, want resolved with its primary", got)
+	}
+}
+
+func TestMergeLinksDuplicatesAndFollowsPrimary(t *testing.T) {
+	staffID := testStaff.ID
+	f := newComplaintFixture(
+		models.Complaint{ID: 1, Title: "No water", Status: "in-progress", SocietyID: testSociety, ResidentID: testResident.ID, StaffID: &staffID},
+		models.Complaint{ID: 2, Title: "Dry taps", Status: "pending", SocietyID: testSociety, ResidentID: testNeighbor.ID},
+	)
+
+	_, duplicates, err := f.service.Merge(context.Background(), testSociety, 1, []uint{2})
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if len(duplicates) != 1 {
+		t.Fatalf("merged %d duplicates, want 1", len(duplicates))
+	}
+
+	merged := f.complaints.rows[2]
+	if merged.DuplicateOfID == nil || *merged.DuplicateOfID != 1 {
+		t.Errorf("duplicate not linked to the primary: %v", merged.DuplicateOfID)
+	}
+	if merged.Status != "in-progress" || merged.StaffID == nil || *merged.StaffID != testStaff.ID {
+		t.Errorf("duplicate does not follow the primary: status %q, staff %v", merged.Status, merged.StaffID)
+	}
+	if got := f.notifications.recipients("merged"); len(got) != 1 || got[0] != testNeighbor.ID {
+		t.Errorf("merge notified %v, want the duplicate's reporter", got)
+	}
+}
+
+func TestMergeRejectsInvalidComplaints(t *testing.T) {
+	f := newComplaintFixture(
+		models.Complaint{ID: 1, Status: "pending", SocietyID: testSociety, ResidentID: testResident.ID},
+		models.Complaint{ID: 2, Status: "resolved", SocietyID: testSociety, ResidentID: testNeighbor.ID},
+		models.Complaint{ID: 3, Status: "pending", SocietyID: testSociety + 1, ResidentID: 99},
+		models.Complaint{ID: 4, Status: "pending", SocietyID: testSociety, ResidentID: testNeighbor.ID},
+	)
+
+	cases := map[string][]uint{
+		"itself":          {1},
+		"closed":          {2},
+		"other society":   {3},
+		"missing":         {42},
+		"partly resolved": {2, 3},
+	}
+	for name, ids := range cases {
+		if _, _, err := f.service.Merge(context.Background(), testSociety, 1, ids); !errors.Is(err, ErrInvalidMerge) {
+			t.Errorf("%s: expected ErrInvalidMerge, got %v", name, err)
+		}
+	}
+	// A closed primary would close the duplicates merged into it
+	if _, _, err := f.service.Merge(context.Background(), testSociety, 2, []uint{4}); !errors.Is(err, ErrInvalidMerge) {
+		t.Errorf("resolved primary: expected ErrInvalidMerge, got %v", err)
+	}
+	if f.complaints.rows[2].DuplicateOfID != nil || f.complaints.rows[3].DuplicateOfID != nil || f.complaints.rows[4].DuplicateOfID != nil {
+		t.Error("a rejected merge linked complaints")
+	}
+}
+
+func TestCancelOnlyOpenComplaints(t *testing.T) {
+	f := newComplaintFixture(
+		resolvedComplaint(1, time.Hour),
+		models.Complaint{ID: 2, Status: "cancelled", SocietyID: testSociety, ResidentID: testResident.ID},
+		models.Complaint{ID: 3, Status: "pending", SocietyID: testSociety, ResidentID: testResident.ID},
+	)
+	ctx := context.Background()
+
+	for _, id := range []uint{1, 2} {
+		complaint, _ := f.complaints.Find(ctx, testSociety, id)
+		status := complaint.Status
+		if err := f.service.Cancel(ctx, complaint, &testResident); !errors.Is(err, ErrNotCancellable) {
+			t.Errorf("cancel %s complaint: expected ErrNotCancellable, got %v", status, err)
+		}
+		if f.complaints.rows[id].Status != status {
+			t.Errorf("%s complaint changed to %q", status, f.complaints.rows[id].Status)
+		}
+	}
+
+	pending, _ := f.complaints.Find(ctx, testSociety, 3)
+	if err := f.service.Cancel(ctx, pending, &testNeighbor); !errors.Is(err, ErrNotComplaintOwner) {
+		t.Errorf("cancel by another resident: expected ErrNotComplaintOwner, got %v", err)
+	}
+	if err := f.service.Cancel(ctx, pending, &testResident); err != nil {
+		t.Fatalf("cancel pending complaint: %v", err)
+	}
+	// Nobody was assigned, so nobody needs telling
+	if got := f.notifications.recipients("status_update"); len(got) != 0 {
+		t.Errorf("notified %v, want nobody", got)
+	}
+}
+
+func TestEditOnlyPendingComplaints(t *testing.T) {
+	staffID := testStaff.ID
+	f := newComplaintFixture(
+		models.Complaint{ID: 1, Title: "Leaking tap", Description: "kitchen", Status: "pending", SocietyID: testSociety, ResidentID: testResident.ID, CategoryID: 1},
+		models.Complaint{ID: 2, Title: "Lift", Status: "in-progress", SocietyID: testSociety, ResidentID: testResident.ID, StaffID: &staffID, CategoryID: 1},
+	)
+	f.service.Categories = newFakeCategories(
+		models.Category{ID: 1, Name: "Plumbing", SocietyID: testSociety},
+		models.Category{ID: 2, Name: "General"},
+		models.Category{ID: 3, Name: "Elsewhere", SocietyID: testSociety + 1},
+	)
+	ctx := context.Background()
+	title, description := "Leaking kitchen tap", "under the sink"
+
+	assigned, _ := f.complaints.Find(ctx, testSociety, 2)
+	if err := f.service.Edit(ctx, assigned, &testResident, &title, nil, nil); !errors.Is(err, ErrNotPending) {
+		t.Errorf("edit of an assigned complaint: expected ErrNotPending, got %v", err)
+	}
+
+	pending, _ := f.complaints.Find(ctx, testSociety, 1)
+	if err := f.service.Edit(ctx, pending, &testNeighbor, &title, nil, nil); !errors.Is(err, ErrNotComplaintOwner) {
+		t.Errorf("edit by another resident: expected ErrNotComplaintOwner, got %v", err)
+	}
+	other := uint(3)
+	if err := f.service.Edit(ctx, pending, &testResident, nil, nil, &other); !errors.Is(err, ErrCategoryNotInSociety) {
+		t.Errorf("edit into another society's category: expected ErrCategoryNotInSociety, got %v", err)
+	}
+
+	pending, _ = f.complaints.Find(ctx, testSociety, 1)
+	global := uint(2)
+	if err := f.service.Edit(ctx, pending, &testResident, &title, &description, &global); err != nil {
+		t.Fatalf("edit: %v", err)
+	}
+	if c := f.complaints.rows[1]; c.Title != title || c.Description != description || c.CategoryID != global {
+		t.Errorf("complaint stored as %+v, want the edited fields", *c)
+	}
+}
+
+func TestMergeMovesDuplicatesOfTheMergedComplaint(t *testing.T) {
+	staffID := testStaff.ID
+	earlier := uint(2)
+	f := newComplaintFixture(
+		models.Complaint{ID: 1, Title: "Lift stuck", Status: "in-progress", SocietyID: testSociety, ResidentID: testResident.ID, StaffID: &staffID},
+		models.Complaint{ID: 2, Title: "Lift broken", Status: "pending", SocietyID: testSociety, ResidentID: testNeighbor.ID},
+		models.Complaint{ID: 3, Title: "No lift", Status: "pending", SocietyID: testSociety, ResidentID: testNeighbor.ID, DuplicateOfID: &earlier},
+	)
+
+	if _, _, err := f.service.Merge(context.Background(), testSociety, 1, []uint{2}); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	for _, id := range []uint{2, 3} {
+		c := f.complaints.rows[id]
+		if c.DuplicateOfID == nil || *c.DuplicateOfID != 1 {
+			t.Errorf("complaint %d is a duplicate of %v, want 1", id, c.DuplicateOfID)
+		}
+		if c.Status != "in-progress" || c.StaffID == nil || *c.StaffID != testStaff.ID {
+			t.Errorf("complaint %d: status %q staff %v, want it to follow the new primary", id, c.Status, c.StaffID)
+		}
+	}
+
+	// A duplicate cannot become a primary itself
+	if _, _, err := f.service.Merge(context.Background(), testSociety, 2, []uint{1}); !errors.Is(err, ErrInvalidMerge) {
+		t.Errorf("merge into a duplicate: expected ErrInvalidMerge, got %v", err)
+	}
+}
+
+func TestFilterHidesDuplicatesFromTheQueue(t *testing.T) {
+	service := &ComplaintService{}
+	tests := []struct {
+		name    string
+		perms   []string
+		include bool
+		want    bool
+	}{
+		{"admin", []string{PermComplaintViewAll}, false, true},
+		{"admin asking for duplicates", []string{PermComplaintViewAll}, true, false},
+		{"staff", []string{PermComplaintViewAssigned}, false, true},
+		// Residents keep seeing the reports they filed, even once merged
+		{"resident", []string{PermComplaintViewOwn}, false, false},
+	}
+	for _, tt := range tests {
+		filter, err := service.Filter(context.Background(), &testAdmin, newPermissionSet(tt.perms), ComplaintListOptions{IncludeDuplicates: tt.include})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if filter.ExcludeDuplicates != tt.want {
+			t.Errorf("%s: exclude duplicates = %v, want %v", tt.name, filter.ExcludeDuplicates, tt.want)
+		}
+	}
+}
+
+func TestAssignRefusesStaffOfOtherSocieties(t *testing.T) {
+	f := newComplaintFixture(models.Complaint{ID: 1, Status: "pending", SocietyID: testSociety, ResidentID: testResident.ID})
+	outsider := models.User{ID: 40, Role: "staff", SocietyID: testSociety + 1}
+	f.service.Users.(*fakeUsers).rows[outsider.ID] = &outsider
+
+	if _, _, _, err := f.service.Assign(context.Background(), &testAdmin, 1, outsider.ID, true); !errors.Is(err, ErrStaffNotFound) {
+		t.Fatalf("expected ErrStaffNotFound, got %v", err)
+	}
+	if _, _, _, err := f.service.Assign(context.Background(), &testAdmin, 1, testNeighbor.ID, true); !errors.Is(err, ErrNotStaffMember) {
+		t.Fatalf("expected ErrNotStaffMember, got %v", err)
+	}
+	// Admins hold every permission but do not work the queue
+	if _, _, _, err := f.service.Assign(context.Background(), &testAdmin, 1, testAdmin.ID, true); !errors.Is(err, ErrNotStaffMember) {
+		t.Fatalf("expected ErrNotStaffMember for an admin, got %v", err)
+	}
+}
+
+func TestStaffRolesFollowPermissions(t *testing.T) {
+	tests := []struct {
+		role  string
+		perms []string
+		want  bool
+	}{
+		{"staff", builtinRoles["staff"], true},
+		{"user", builtinRoles["user"], false},
+		{"admin", builtinRoles["admin"], false},
+		{"technician", []string{PermComplaintViewAssigned, PermComplaintResolve, PermPointsView}, true},
+		{"supervisor", []string{PermComplaintViewAll, PermComplaintResolve}, false},
+		{"auditor", []string{PermComplaintViewAssigned}, false},
+	}
+	for _, tt := range tests {
+		if got := worksComplaints(tt.role, newPermissionSet(tt.perms)); got != tt.want {
+			t.Errorf("worksComplaints(%q) = %v, want %v", tt.role, got, tt.want)
+		}
+	}
+}
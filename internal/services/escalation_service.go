@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/repository"
+	"github.com/VinVorteX/flashtrack/internal/telemetry"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Escalation triggers
+const (
+	TriggerUnassigned = "unassigned"
+	TriggerUnresolved = "unresolved"
+)
+
+// Escalation actions
+const (
+	ActionNotifyAdmin = "notify_admin"
+	ActionNotifyRole  = "notify_role"
+	ActionReassign    = "reassign"
+)
+
+var ErrPolicyNotFound = errors.New("escalation policy not found")
+
+// EscalationService manages escalation policies and evaluates them against open complaints.
+// Evaluate runs outside any request and works across every society, so its context carries no
+// tenant session and the repositories fall back to database.DB.
+type EscalationService struct {
+	Escalations   repository.EscalationRepository
+	Complaints    repository.ComplaintRepository
+	Categories    repository.CategoryRepository
+	Users         repository.UserRepository
+	Notifications *NotificationService
+	Assignment    *AssignmentService
+	Plans         *PlanService
+}
+
+// Policies returns the society's escalation policies ordered by level
+func (es *EscalationService) Policies(ctx context.Context, societyID uint) ([]models.EscalationPolicy, error) {
+	return es.Escalations.Policies(ctx, societyID)
+}
+
+// CreatePolicy validates and stores a policy for its society. A category, when given, must be
+// available to the society.
+func (es *EscalationService) CreatePolicy(ctx context.Context, policy *models.EscalationPolicy) error {
+	if err := es.ValidatePolicy(policy); err != nil {
+		return err
+	}
+	if policy.CategoryID != nil {
+		_, err := es.Categories.FindAvailable(ctx, policy.SocietyID, *policy.CategoryID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrCategoryNotInSociety
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return es.Escalations.CreatePolicy(ctx, policy)
+}
+
+// DeletePolicy removes one of the society's policies
+func (es *EscalationService) DeletePolicy(ctx context.Context, societyID, policyID uint) error {
+	err := es.Escalations.DeletePolicy(ctx, societyID, policyID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrPolicyNotFound
+	}
+	return err
+}
+
+// History returns one of the society's complaints with the escalations it went through
+func (es *EscalationService) History(ctx context.Context, societyID, complaintID uint) (*models.Complaint, []models.ComplaintEscalation, error) {
+	complaint, err := es.Complaints.Find(ctx, societyID, complaintID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil, ErrComplaintNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	escalations, err := es.Escalations.History(ctx, complaint.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return complaint, escalations, nil
+}
+
+// ValidatePolicy checks that a policy is complete before it is stored
+func (es *EscalationService) ValidatePolicy(policy *models.EscalationPolicy) error {
+	if policy.Trigger != TriggerUnassigned && policy.Trigger != TriggerUnresolved {
+		return errors.New("trigger must be unassigned or unresolved")
+	}
+	if policy.AfterHours <= 0 {
+		return errors.New("after_hours must be positive")
+	}
+	if policy.Level <= 0 {
+		return errors.New("level must be positive")
+	}
+	switch policy.Action {
+	case ActionNotifyAdmin, ActionReassign:
+	case ActionNotifyRole:
+		if policy.TargetRole == "" {
+			return errors.New("target_role is required for notify_role")
+		}
+	default:
+		return errors.New("action must be notify_admin, notify_role or reassign")
+	}
+	return nil
+}
+
+// Run evaluates escalation policies every interval until ctx is cancelled
+func (es *EscalationService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			// Each run is its own trace, covering the queries and notifications it causes
+			runCtx, span := telemetry.Tracer().Start(ctx, "escalation.evaluate")
+			if err := es.Evaluate(runCtx, now); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				slog.ErrorContext(runCtx, "escalation run failed", "error", err)
+			}
+			span.End()
+		}
+	}
+}
+
+// Evaluate escalates every open complaint whose policies have come due at time now
+func (es *EscalationService) Evaluate(ctx context.Context, now time.Time) error {
+	policies, err := es.Escalations.AllPolicies(ctx)
+	if err != nil {
+		return err
+	}
+	if len(policies) == 0 {
+		return nil
+	}
+
+	bySociety := make(map[uint][]models.EscalationPolicy)
+	for _, p := range policies {
+		bySociety[p.SocietyID] = append(bySociety[p.SocietyID], p)
+	}
+
+	for societyID, societyPolicies := range bySociety {
+		// Policies only run for societies whose plan includes the SLA engine
+		if err := es.Plans.RequireFeature(ctx, societyID, FeatureSLA); err != nil {
+			var featureErr *FeatureError
+			if !errors.As(err, &featureErr) {
+				slog.ErrorContext(ctx, "failed to check plan of society", "society_id", societyID, "error", err)
+			}
+			continue
+		}
+
+		// Merged duplicates follow their primary and are escalated through it
+		complaints, err := es.Complaints.ListUnmerged(ctx, societyID, openStatuses)
+		if err != nil {
+			return err
+		}
+
+		for i := range complaints {
+			for _, policy := range societyPolicies {
+				if !policyDue(policy, &complaints[i], now) {
+					continue
+				}
+				if err := es.escalate(ctx, policy, &complaints[i], now); err != nil {
+					slog.ErrorContext(ctx, "failed to escalate complaint", "complaint_id", complaints[i].ID, "policy_id", policy.ID, "error", err)
+					break
+				}
+				telemetry.SLABreaches.WithLabelValues(policy.Trigger, policy.Action).Inc()
+			}
+		}
+	}
+
+	return nil
+}
+
+// policyDue reports whether the policy should raise the complaint to its level at time now.
+// The clock runs from when the complaint was raised or last reopened; unresolved complaints
+// also restart it when they are assigned, giving each assignee the policy's full time.
+func policyDue(policy models.EscalationPolicy, complaint *models.Complaint, now time.Time) bool {
+	if policy.Level <= complaint.EscalationLevel {
+		return false
+	}
+	if policy.CategoryID != nil && *policy.CategoryID != complaint.CategoryID {
+		return false
+	}
+	if policy.Trigger == TriggerUnassigned && complaint.StaffID != nil {
+		return false
+	}
+
+	since := complaint.CreatedAt
+	if complaint.ReopenedAt != nil && complaint.ReopenedAt.After(since) {
+		since = *complaint.ReopenedAt
+	}
+	if policy.Trigger == TriggerUnresolved && complaint.AssignedAt != nil && complaint.AssignedAt.After(since) {
+		since = *complaint.AssignedAt
+	}
+	return now.Sub(since) >= time.Duration(policy.AfterHours)*time.Hour
+}
+
+// escalate applies the policy action and records the new escalation level on the complaint
+func (es *EscalationService) escalate(ctx context.Context, policy models.EscalationPolicy, complaint *models.Complaint, now time.Time) error {
+	reason := fmt.Sprintf("%s for more than %d hours", policy.Trigger, policy.AfterHours)
+
+	switch policy.Action {
+	case ActionNotifyAdmin:
+		es.notifyRole(ctx, complaint, "admin", policy.Level, reason)
+	case ActionNotifyRole:
+		es.notifyRole(ctx, complaint, policy.TargetRole, policy.Level, reason)
+	case ActionReassign:
+		staff, err := es.Assignment.Reassign(ctx, complaint)
+		if err != nil {
+			return err
+		}
+		if staff == nil {
+			// Nobody else can take it, so make sure an admin hears about it
+			es.notifyRole(ctx, complaint, "admin", policy.Level, reason+"; no staff available for reassignment")
+		}
+	}
+
+	complaint.EscalationLevel = policy.Level
+	complaint.EscalatedAt = &now
+	if err := es.Complaints.SetEscalation(ctx, complaint); err != nil {
+		return err
+	}
+
+	return es.Escalations.Record(ctx, &models.ComplaintEscalation{
+		ComplaintID: complaint.ID,
+		PolicyID:    policy.ID,
+		Level:       policy.Level,
+		Action:      policy.Action,
+	})
+}
+
+// notifyRole notifies every user with the given role in the complaint's society
+func (es *EscalationService) notifyRole(ctx context.Context, complaint *models.Complaint, role string, level int, reason string) {
+	userIDs, err := es.Users.ActiveIDs(ctx, complaint.SocietyID, role)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to load users for escalation", "role", role, "complaint_id", complaint.ID, "error", err)
+		return
+	}
+
+	for _, id := range userIDs {
+		if err := es.Notifications.NotifyEscalation(ctx, id, complaint, level, reason); err != nil {
+			slog.ErrorContext(ctx, "failed to notify user of escalation", "user_id", id, "complaint_id", complaint.ID, "error", err)
+		}
+	}
+}
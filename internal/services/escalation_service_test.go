@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+)
+
+func TestPolicyDue(t *testing.T) {
+	created := time.Date(2025, time.January, 1, 9, 0, 0, 0, time.UTC)
+	at := func(hours int) *time.Time {
+		t := created.Add(time.Duration(hours) * time.Hour)
+		return &t
+	}
+	staffID := testStaff.ID
+	unassigned := models.EscalationPolicy{Trigger: TriggerUnassigned, AfterHours: 4, Level: 1}
+	unresolved := models.EscalationPolicy{Trigger: TriggerUnresolved, AfterHours: 24, Level: 2}
+
+	tests := []struct {
+		name      string
+		policy    models.EscalationPolicy
+		complaint models.Complaint
+		now       time.Time
+		want      bool
+	}{
+		{"unassigned before its time", unassigned, models.Complaint{CreatedAt: created}, *at(3), false},
+		{"unassigned after its time", unassigned, models.Complaint{CreatedAt: created}, *at(4), true},
+		{"assigned complaints are not unassigned", unassigned, models.Complaint{CreatedAt: created, StaffID: &staffID, AssignedAt: at(1)}, *at(30), false},
+		{"level already reached", unassigned, models.Complaint{CreatedAt: created, EscalationLevel: 1}, *at(30), false},
+		{"other category", models.EscalationPolicy{Trigger: TriggerUnassigned, AfterHours: 4, Level: 1, CategoryID: new(uint)},
+			models.Complaint{CreatedAt: created, CategoryID: 3}, *at(30), false},
+		{"unresolved counts from assignment", unresolved, models.Complaint{CreatedAt: created, StaffID: &staffID, AssignedAt: at(10)}, *at(30), false},
+		{"unresolved assignee out of time", unresolved, models.Complaint{CreatedAt: created, StaffID: &staffID, AssignedAt: at(10)}, *at(34), true},
+		{"unresolved without assignee counts from creation", unresolved, models.Complaint{CreatedAt: created}, *at(24), true},
+		{"reopened complaint counts from reopening", unassigned, models.Complaint{CreatedAt: created, ReopenedAt: at(48)}, *at(50), false},
+		{"reopened assignee counts from reopening", unresolved, models.Complaint{CreatedAt: created, StaffID: &staffID, AssignedAt: at(1), ReopenedAt: at(48)}, *at(60), false},
+		{"reopened assignee out of time", unresolved, models.Complaint{CreatedAt: created, StaffID: &staffID, AssignedAt: at(1), ReopenedAt: at(48)}, *at(72), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policyDue(tt.policy, &tt.complaint, tt.now); got != tt.want {
+				t.Errorf("policyDue = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReopenRestartsEscalation(t *testing.T) {
+	complaint := resolvedComplaint(1, time.Hour)
+	escalatedAt := time.Now().Add(-2 * time.Hour)
+	complaint.CreatedAt = time.Now().Add(-72 * time.Hour)
+	complaint.EscalationLevel = 2
+	complaint.EscalatedAt = &escalatedAt
+	f := newComplaintFixture(complaint)
+
+	stored, _ := f.complaints.Find(context.Background(), testSociety, 1)
+	if _, err := f.service.Reopen(context.Background(), stored, &testResident, "still leaking"); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+
+	reopened := f.complaints.rows[1]
+	if reopened.EscalationLevel != 0 || reopened.EscalatedAt != nil || reopened.ReopenedAt == nil {
+		t.Fatalf("escalation level %d escalated %v reopened %v, want a fresh escalation",
+			reopened.EscalationLevel, reopened.EscalatedAt, reopened.ReopenedAt)
+	}
+
+	policy := models.EscalationPolicy{Trigger: TriggerUnresolved, AfterHours: 24, Level: 1}
+	if policyDue(policy, reopened, time.Now()) {
+		t.Error("a just reopened complaint must not be escalated for its age")
+	}
+	if !policyDue(policy, reopened, time.Now().Add(25*time.Hour)) {
+		t.Error("the reopened complaint should escalate once unresolved for the policy's hours")
+	}
+}
+
+func TestAssignRestartsEscalation(t *testing.T) {
+	escalatedAt := time.Now().Add(-time.Hour)
+	complaint := models.Complaint{ID: 1, Status: "pending", EscalationLevel: 3, EscalatedAt: &escalatedAt}
+
+	assignComplaint(&complaint, testStaff.ID, time.Now())
+	if complaint.EscalationLevel != 0 || complaint.EscalatedAt != nil {
+		t.Errorf("escalation level %d escalated %v, want it cleared for the new assignee", complaint.EscalationLevel, complaint.EscalatedAt)
+	}
+	if complaint.StaffID == nil || *complaint.StaffID != testStaff.ID || complaint.Status != "in-progress" || complaint.AssignedAt == nil {
+		t.Errorf("complaint %+v not assigned to the staff member", complaint)
+	}
+}
+
+// escalationFixture is an EscalationService over the test society's admin and two staff members
+type escalationFixture struct {
+	service       *EscalationService
+	complaints    *fakeComplaints
+	escalations   *fakeEscalations
+	notifications *fakeNotifications
+}
+
+func newEscalationFixture(complaints ...models.Complaint) *escalationFixture {
+	f := &escalationFixture{
+		complaints:    newFakeComplaints(complaints...),
+		escalations:   &fakeEscalations{},
+		notifications: &fakeNotifications{},
+	}
+	users := newFakeUsers(testAdmin, testStaff, testOtherStaff, testResident)
+	notifications := &NotificationService{Notifications: f.notifications, Users: users}
+	societies := &fakeSocieties{rows: map[uint]*models.Society{testSociety: {ID: testSociety}}}
+	f.service = &EscalationService{
+		Escalations:   f.escalations,
+		Complaints:    f.complaints,
+		Users:         users,
+		Notifications: notifications,
+		Assignment: &AssignmentService{
+			Complaints:    f.complaints,
+			Users:         users,
+			Societies:     societies,
+			Roles:         &RoleService{Roles: &fakeRoles{}, Users: users},
+			Staff:         &StaffService{Staff: newFakeStaff(), Complaints: f.complaints, Societies: societies},
+			Notifications: notifications,
+		},
+	}
+	return f
+}
+
+func TestEscalateReassignsAndRecordsTheLevel(t *testing.T) {
+	staffID := testStaff.ID
+	assignedAt := time.Now().Add(-30 * time.Hour)
+	f := newEscalationFixture(models.Complaint{ID: 1, Title: "No water", Status: "in-progress", SocietyID: testSociety,
+		ResidentID: testResident.ID, StaffID: &staffID, AssignedAt: &assignedAt, CreatedAt: assignedAt})
+	policy := models.EscalationPolicy{ID: 5, SocietyID: testSociety, Trigger: TriggerUnresolved, AfterHours: 24, Level: 2, Action: ActionReassign}
+
+	complaint, _ := f.complaints.Find(context.Background(), testSociety, 1)
+	now := time.Now()
+	if !policyDue(policy, complaint, now) {
+		t.Fatal("the policy should be due after 30 hours with the same assignee")
+	}
+	if err := f.service.escalate(context.Background(), policy, complaint, now); err != nil {
+		t.Fatalf("escalate: %v", err)
+	}
+
+	stored := f.complaints.rows[1]
+	if stored.StaffID == nil || *stored.StaffID != testOtherStaff.ID {
+		t.Fatalf("complaint stays with %v, want it reassigned to %d", stored.StaffID, testOtherStaff.ID)
+	}
+	// The level is recorded after the reassignment cleared it
+	if stored.EscalationLevel != 2 || stored.EscalatedAt == nil {
+		t.Errorf("escalation level %d escalated %v, want level 2", stored.EscalationLevel, stored.EscalatedAt)
+	}
+	if len(f.escalations.records) != 1 || f.escalations.records[0].Level != 2 || f.escalations.records[0].PolicyID != 5 {
+		t.Errorf("recorded %+v, want one level 2 escalation by the policy", f.escalations.records)
+	}
+	if got := f.notifications.recipients("assignment"); len(got) != 1 || got[0] != testOtherStaff.ID {
+		t.Errorf("assignment notifications to %v, want the new assignee", got)
+	}
+
+	// The next level gives the new assignee the full time again
+	next := models.EscalationPolicy{Trigger: TriggerUnresolved, AfterHours: 24, Level: 3, Action: ActionNotifyAdmin}
+	if stored.AssignedAt == nil || policyDue(next, stored, stored.AssignedAt.Add(time.Hour)) || !policyDue(next, stored, stored.AssignedAt.Add(24*time.Hour)) {
+		t.Error("the next level should count from the reassignment")
+	}
+	if policyDue(policy, stored, now.Add(48*time.Hour)) {
+		t.Error("a level already reached must not escalate again")
+	}
+}
+
+func TestEscalateWithoutOtherStaffTellsTheAdmin(t *testing.T) {
+	staffID := testStaff.ID
+	f := newEscalationFixture(models.Complaint{ID: 1, Title: "No water", Status: "in-progress", SocietyID: testSociety,
+		ResidentID: testResident.ID, StaffID: &staffID})
+	// The only other staff member is away
+	f.service.Users.(*fakeUsers).rows[testOtherStaff.ID].Role = "user"
+	policy := models.EscalationPolicy{SocietyID: testSociety, Trigger: TriggerUnresolved, AfterHours: 24, Level: 1, Action: ActionReassign}
+
+	complaint, _ := f.complaints.Find(context.Background(), testSociety, 1)
+	if err := f.service.escalate(context.Background(), policy, complaint, time.Now()); err != nil {
+		t.Fatalf("escalate: %v", err)
+	}
+	if stored := f.complaints.rows[1]; *stored.StaffID != testStaff.ID || stored.EscalationLevel != 1 {
+		t.Errorf("complaint %+v, want it kept by its assignee at level 1", *stored)
+	}
+	if got := f.notifications.recipients("escalation"); len(got) != 1 || got[0] != testAdmin.ID {
+		t.Errorf("escalation notifications to %v, want the admin", got)
+	}
+}
+
+func TestEscalateNotifiesTheTargetRole(t *testing.T) {
+	f := newEscalationFixture(models.Complaint{ID: 1, Title: "No water", Status: "pending", SocietyID: testSociety, ResidentID: testResident.ID})
+	policy := models.EscalationPolicy{SocietyID: testSociety, Trigger: TriggerUnassigned, AfterHours: 2, Level: 1,
+		Action: ActionNotifyRole, TargetRole: "staff"}
+
+	complaint, _ := f.complaints.Find(context.Background(), testSociety, 1)
+	if err := f.service.escalate(context.Background(), policy, complaint, time.Now()); err != nil {
+		t.Fatalf("escalate: %v", err)
+	}
+	notified := map[uint]bool{}
+	for _, id := range f.notifications.recipients("escalation") {
+		notified[id] = true
+	}
+	if len(notified) != 2 || !notified[testStaff.ID] || !notified[testOtherStaff.ID] {
+		t.Errorf("escalation notifications to %v, want both staff members", f.notifications.recipients("escalation"))
+	}
+}
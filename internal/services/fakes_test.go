@@ -0,0 +1,787 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/repository"
+)
+
+// In-memory repositories for exercising the services without a database
+
+type fakeComplaints struct {
+	rows    map[uint]*models.Complaint
+	reopens []models.ComplaintReopen
+}
+
+func newFakeComplaints(complaints ...models.Complaint) *fakeComplaints {
+	f := &fakeComplaints{rows: map[uint]*models.Complaint{}}
+	for i := range complaints {
+		c := complaints[i]
+		f.rows[c.ID] = &c
+	}
+	return f
+}
+
+func (f *fakeComplaints) Find(ctx context.Context, societyID, id uint) (*models.Complaint, error) {
+	c, ok := f.rows[id]
+	if !ok || c.SocietyID != societyID {
+		return nil, repository.ErrNotFound
+	}
+	copied := *c
+	return &copied, nil
+}
+
+func (f *fakeComplaints) List(ctx context.Context, filter repository.ComplaintFilter) ([]models.Complaint, error) {
+	var out []models.Complaint
+	for _, c := range f.rows {
+		if c.SocietyID == filter.SocietyID {
+			out = append(out, *c)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeComplaints) Titles(ctx context.Context, ids []uint) (map[uint]string, error) {
+	titles := map[uint]string{}
+	for _, id := range ids {
+		if c, ok := f.rows[id]; ok {
+			titles[id] = c.Title
+		}
+	}
+	return titles, nil
+}
+
+func (f *fakeComplaints) Create(ctx context.Context, complaint *models.Complaint) error {
+	complaint.ID = uint(len(f.rows) + 1)
+	return f.Save(ctx, complaint)
+}
+
+func (f *fakeComplaints) Save(ctx context.Context, complaint *models.Complaint) error {
+	copied := *complaint
+	f.rows[complaint.ID] = &copied
+	return nil
+}
+
+func (f *fakeComplaints) FindUnmerged(ctx context.Context, societyID uint, ids []uint, statuses []string) ([]models.Complaint, error) {
+	var out []models.Complaint
+	for _, id := range ids {
+		c, ok := f.rows[id]
+		if !ok || c.SocietyID != societyID || c.DuplicateOfID != nil {
+			continue
+		}
+		for _, s := range statuses {
+			if c.Status == s {
+				out = append(out, *c)
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeComplaints) Duplicates(ctx context.Context, primaryID uint) ([]models.Complaint, error) {
+	var out []models.Complaint
+	for _, c := range f.rows {
+		if c.DuplicateOfID != nil && *c.DuplicateOfID == primaryID {
+			out = append(out, *c)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeComplaints) CountDuplicates(ctx context.Context, primaryID uint) (int64, error) {
+	duplicates, _ := f.Duplicates(ctx, primaryID)
+	return int64(len(duplicates)), nil
+}
+
+func (f *fakeComplaints) Repoint(ctx context.Context, fromIDs []uint, primaryID uint) error {
+	for _, c := range f.rows {
+		for _, from := range fromIDs {
+			if c.DuplicateOfID != nil && *c.DuplicateOfID == from {
+				id := primaryID
+				c.DuplicateOfID = &id
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fakeComplaints) SyncDuplicates(ctx context.Context, primary *models.Complaint) error {
+	for _, c := range f.rows {
+		if c.DuplicateOfID != nil && *c.DuplicateOfID == primary.ID {
+			c.Status, c.StaffID, c.AssignedAt, c.ResolvedAt = primary.Status, primary.StaffID, primary.AssignedAt, primary.ResolvedAt
+		}
+	}
+	return nil
+}
+
+func (f *fakeComplaints) ListUnmerged(ctx context.Context, societyID uint, statuses []string) ([]models.Complaint, error) {
+	var out []models.Complaint
+	for _, c := range f.rows {
+		if c.SocietyID == societyID && c.DuplicateOfID == nil && hasStatus(c, statuses) {
+			out = append(out, *c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (f *fakeComplaints) Workloads(ctx context.Context, staffIDs []uint, statuses []string) (map[uint]int64, error) {
+	workloads := map[uint]int64{}
+	for _, c := range f.rows {
+		if c.StaffID != nil && c.DuplicateOfID == nil && hasStatus(c, statuses) {
+			workloads[*c.StaffID]++
+		}
+	}
+	return workloads, nil
+}
+
+func (f *fakeComplaints) Release(ctx context.Context, staffID uint, statuses []string) ([]models.Complaint, error) {
+	var primaries []models.Complaint
+	for _, c := range f.rows {
+		if c.StaffID == nil || *c.StaffID != staffID || !hasStatus(c, statuses) {
+			continue
+		}
+		c.StaffID, c.Status, c.AssignedAt = nil, "pending", nil
+		if c.DuplicateOfID == nil {
+			primaries = append(primaries, *c)
+		}
+	}
+	sort.Slice(primaries, func(i, j int) bool { return primaries[i].ID < primaries[j].ID })
+	return primaries, nil
+}
+
+func (f *fakeComplaints) SetEscalation(ctx context.Context, complaint *models.Complaint) error {
+	if c, ok := f.rows[complaint.ID]; ok {
+		c.EscalationLevel, c.EscalatedAt = complaint.EscalationLevel, complaint.EscalatedAt
+	}
+	return nil
+}
+
+func hasStatus(c *models.Complaint, statuses []string) bool {
+	for _, s := range statuses {
+		if c.Status == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeComplaints) CreateReopen(ctx context.Context, reopen *models.ComplaintReopen) error {
+	f.reopens = append(f.reopens, *reopen)
+	return nil
+}
+
+type fakeFeedback struct {
+	rows   []*models.Feedback
+	points map[uint]*models.StaffPoints
+}
+
+func newFakeFeedback() *fakeFeedback {
+	return &fakeFeedback{points: map[uint]*models.StaffPoints{}}
+}
+
+func (f *fakeFeedback) Create(ctx context.Context, feedback *models.Feedback) error {
+	feedback.ID = uint(len(f.rows) + 1)
+	copied := *feedback
+	f.rows = append(f.rows, &copied)
+	return nil
+}
+
+func (f *fakeFeedback) FindActive(ctx context.Context, complaintID uint) (*models.Feedback, error) {
+	for _, fb := range f.rows {
+		if fb.ComplaintID == complaintID && !fb.Reversed {
+			copied := *fb
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeFeedback) RatedComplaintIDs(ctx context.Context, userID uint, complaintIDs []uint) ([]uint, error) {
+	var out []uint
+	for _, id := range complaintIDs {
+		for _, fb := range f.rows {
+			if fb.ComplaintID == id && fb.UserID == userID && !fb.Reversed {
+				out = append(out, id)
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeFeedback) ListBySociety(ctx context.Context, societyID uint) ([]models.Feedback, error) {
+	var out []models.Feedback
+	for _, fb := range f.rows {
+		out = append(out, *fb)
+	}
+	return out, nil
+}
+
+func (f *fakeFeedback) Reverse(ctx context.Context, feedback *models.Feedback) error {
+	for _, fb := range f.rows {
+		if fb.ID == feedback.ID {
+			fb.Reversed = true
+		}
+	}
+	if p, ok := f.points[feedback.StaffID]; ok {
+		p.TotalPoints -= feedback.Points
+		p.TasksCompleted--
+	}
+	return nil
+}
+
+func (f *fakeFeedback) AverageRatings(ctx context.Context, staffIDs []uint) (map[uint]float64, error) {
+	sums, counts := map[uint]float64{}, map[uint]float64{}
+	for _, fb := range f.rows {
+		if !fb.Reversed {
+			sums[fb.StaffID] += float64(fb.Rating)
+			counts[fb.StaffID]++
+		}
+	}
+	ratings := map[uint]float64{}
+	for id, n := range counts {
+		ratings[id] = sums[id] / n
+	}
+	return ratings, nil
+}
+
+func (f *fakeFeedback) StaffPoints(ctx context.Context, staffID uint) (*models.StaffPoints, error) {
+	p, ok := f.points[staffID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copied := *p
+	return &copied, nil
+}
+
+func (f *fakeFeedback) AwardPoints(ctx context.Context, staffID uint, points int) error {
+	p, ok := f.points[staffID]
+	if !ok {
+		p = &models.StaffPoints{StaffID: staffID}
+		f.points[staffID] = p
+	}
+	p.TotalPoints += points
+	p.TasksCompleted++
+	return nil
+}
+
+type fakeCategories struct {
+	rows map[uint]*models.Category
+}
+
+func newFakeCategories(categories ...models.Category) *fakeCategories {
+	f := &fakeCategories{rows: map[uint]*models.Category{}}
+	for i := range categories {
+		c := categories[i]
+		f.rows[c.ID] = &c
+	}
+	return f
+}
+
+func (f *fakeCategories) ListAvailable(ctx context.Context, societyID uint) ([]models.Category, error) {
+	var out []models.Category
+	for _, c := range f.rows {
+		if c.SocietyID == societyID || c.SocietyID == 0 {
+			out = append(out, *c)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeCategories) FindAvailable(ctx context.Context, societyID, id uint) (*models.Category, error) {
+	c, ok := f.rows[id]
+	if !ok || (c.SocietyID != societyID && c.SocietyID != 0) {
+		return nil, repository.ErrNotFound
+	}
+	copied := *c
+	return &copied, nil
+}
+
+func (f *fakeCategories) CountAvailable(ctx context.Context, societyID uint, ids []uint) (int64, error) {
+	var count int64
+	for _, id := range ids {
+		if _, err := f.FindAvailable(ctx, societyID, id); err == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeCategories) ListOwned(ctx context.Context, societyID uint) ([]models.Category, error) {
+	var out []models.Category
+	for _, c := range f.rows {
+		if c.SocietyID == societyID {
+			out = append(out, *c)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeCategories) FindOwned(ctx context.Context, societyID, id uint) (*models.Category, error) {
+	c, ok := f.rows[id]
+	if !ok || c.SocietyID != societyID {
+		return nil, repository.ErrNotFound
+	}
+	copied := *c
+	return &copied, nil
+}
+
+func (f *fakeCategories) Save(ctx context.Context, category *models.Category) error {
+	if category.ID == 0 {
+		category.ID = uint(len(f.rows) + 1)
+	}
+	copied := *category
+	f.rows[category.ID] = &copied
+	return nil
+}
+
+type fakeUsers struct {
+	rows map[uint]*models.User
+}
+
+func newFakeUsers(users ...models.User) *fakeUsers {
+	f := &fakeUsers{rows: map[uint]*models.User{}}
+	for i := range users {
+		u := users[i]
+		f.rows[u.ID] = &u
+	}
+	return f
+}
+
+func (f *fakeUsers) Find(ctx context.Context, id uint) (*models.User, error) {
+	u, ok := f.rows[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copied := *u
+	return &copied, nil
+}
+
+func (f *fakeUsers) FindInSociety(ctx context.Context, societyID, id uint) (*models.User, error) {
+	u, ok := f.rows[id]
+	if !ok || u.SocietyID != societyID {
+		return nil, repository.ErrNotFound
+	}
+	copied := *u
+	return &copied, nil
+}
+
+func (f *fakeUsers) FindByEmails(ctx context.Context, societyID uint, emails []string) ([]models.User, error) {
+	var out []models.User
+	for _, u := range f.rows {
+		for _, email := range emails {
+			if u.SocietyID == societyID && strings.EqualFold(u.Email, email) {
+				out = append(out, *u)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeUsers) EmailsInOtherSocieties(ctx context.Context, societyID uint, emails []string) ([]string, error) {
+	var out []string
+	for _, u := range f.rows {
+		for _, email := range emails {
+			if u.SocietyID != societyID && strings.EqualFold(u.Email, email) {
+				out = append(out, strings.ToLower(email))
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeUsers) List(ctx context.Context, filter repository.UserFilter) ([]models.User, int64, error) {
+	var out []models.User
+	for _, u := range f.rows {
+		if u.SocietyID == filter.SocietyID && (filter.Role == "" || u.Role == filter.Role) {
+			out = append(out, *u)
+		}
+	}
+	return out, int64(len(out)), nil
+}
+
+func (f *fakeUsers) Names(ctx context.Context, ids []uint) (map[uint]string, error) {
+	names := map[uint]string{}
+	for _, id := range ids {
+		if u, ok := f.rows[id]; ok {
+			names[id] = u.Name
+		}
+	}
+	return names, nil
+}
+
+func (f *fakeUsers) ActiveIDs(ctx context.Context, societyID uint, role string) ([]uint, error) {
+	var out []uint
+	for _, u := range f.rows {
+		if u.SocietyID == societyID && u.Role == role && u.IsActive() {
+			out = append(out, u.ID)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeUsers) ActiveByRoles(ctx context.Context, societyID uint, roles []string) ([]models.User, error) {
+	var out []models.User
+	for _, u := range f.rows {
+		if u.SocietyID != societyID || !u.IsActive() {
+			continue
+		}
+		for _, role := range roles {
+			if u.Role == role {
+				out = append(out, *u)
+				break
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (f *fakeUsers) CountByRole(ctx context.Context, societyID uint) (map[string]int64, error) {
+	counts := map[string]int64{}
+	for _, u := range f.rows {
+		if u.SocietyID == societyID {
+			counts[u.Role]++
+		}
+	}
+	return counts, nil
+}
+
+func (f *fakeUsers) Create(ctx context.Context, user *models.User) error {
+	user.ID = uint(len(f.rows) + 100)
+	copied := *user
+	f.rows[user.ID] = &copied
+	return nil
+}
+
+// Update only knows the columns the services set
+func (f *fakeUsers) Update(ctx context.Context, id uint, fields map[string]interface{}) error {
+	u, ok := f.rows[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	for column, value := range fields {
+		switch column {
+		case "name":
+			u.Name = value.(string)
+		case "role":
+			u.Role = value.(string)
+		case "unit_id":
+			u.UnitID = value.(*uint)
+		case "password":
+			u.Password = value.(string)
+		case "deactivated_at":
+			u.DeactivatedAt, _ = value.(*time.Time)
+		}
+	}
+	return nil
+}
+
+func (f *fakeUsers) Delete(ctx context.Context, id uint) error {
+	delete(f.rows, id)
+	return nil
+}
+
+type fakeSocieties struct {
+	rows map[uint]*models.Society
+}
+
+func (f *fakeSocieties) Find(ctx context.Context, id uint) (*models.Society, error) {
+	s, ok := f.rows[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	copied := *s
+	return &copied, nil
+}
+
+type fakeNotifications struct {
+	rows []models.Notification
+}
+
+func (f *fakeNotifications) Create(ctx context.Context, notification *models.Notification) error {
+	notification.ID = uint(len(f.rows) + 1)
+	f.rows = append(f.rows, *notification)
+	return nil
+}
+
+func (f *fakeNotifications) ListForUser(ctx context.Context, userID uint) ([]models.Notification, error) {
+	var out []models.Notification
+	for _, n := range f.rows {
+		if n.UserID == userID {
+			out = append(out, n)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeNotifications) MarkRead(ctx context.Context, id, userID uint) error {
+	for i := range f.rows {
+		if f.rows[i].ID == id && f.rows[i].UserID == userID {
+			f.rows[i].IsRead = true
+		}
+	}
+	return nil
+}
+
+// recipients returns who was sent a notification of the given type
+func (f *fakeNotifications) recipients(notifType string) []uint {
+	var out []uint
+	for _, n := range f.rows {
+		if n.Type == notifType {
+			out = append(out, n.UserID)
+		}
+	}
+	return out
+}
+
+type fakeStaff struct {
+	profiles map[uint]models.StaffProfile
+	leaves   []models.StaffLeave
+	skills   []models.StaffCategory
+}
+
+func newFakeStaff(profiles ...models.StaffProfile) *fakeStaff {
+	f := &fakeStaff{profiles: map[uint]models.StaffProfile{}}
+	for _, p := range profiles {
+		f.profiles[p.StaffID] = p
+	}
+	return f
+}
+
+func (f *fakeStaff) Profiles(ctx context.Context, staffIDs []uint) ([]models.StaffProfile, error) {
+	var out []models.StaffProfile
+	for _, id := range staffIDs {
+		if p, ok := f.profiles[id]; ok {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeStaff) SaveProfile(ctx context.Context, profile *models.StaffProfile) error {
+	f.profiles[profile.StaffID] = *profile
+	return nil
+}
+
+func (f *fakeStaff) Leaves(ctx context.Context, societyID uint, staffID *uint, since time.Time) ([]models.StaffLeave, error) {
+	var out []models.StaffLeave
+	for _, l := range f.leaves {
+		if l.SocietyID == societyID && !l.EndDate.Before(since) && (staffID == nil || (l.StaffID != nil && *l.StaffID == *staffID)) {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeStaff) LeavesAt(ctx context.Context, societyID uint, staffIDs []uint, at, day time.Time) ([]models.StaffLeave, error) {
+	var out []models.StaffLeave
+	for _, l := range f.leaves {
+		if l.SocietyID == societyID && !l.StartDate.After(at) && !l.EndDate.Before(day) {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeStaff) CreateLeave(ctx context.Context, leave *models.StaffLeave) error {
+	leave.ID = uint(len(f.leaves) + 1)
+	f.leaves = append(f.leaves, *leave)
+	return nil
+}
+
+func (f *fakeStaff) DeleteLeave(ctx context.Context, societyID, id uint) error {
+	for i, l := range f.leaves {
+		if l.ID == id && l.SocietyID == societyID {
+			f.leaves = append(f.leaves[:i], f.leaves[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (f *fakeStaff) Skills(ctx context.Context, staffIDs []uint) ([]models.StaffCategory, error) {
+	var out []models.StaffCategory
+	for _, s := range f.skills {
+		for _, id := range staffIDs {
+			if s.StaffID == id {
+				out = append(out, s)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeStaff) SkilledStaffIDs(ctx context.Context, societyID, categoryID uint) ([]uint, error) {
+	var out []uint
+	for _, s := range f.skills {
+		if s.SocietyID == societyID && s.CategoryID == categoryID {
+			out = append(out, s.StaffID)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeStaff) SetSkills(ctx context.Context, societyID, staffID uint, categoryIDs []uint) error {
+	kept := f.skills[:0]
+	for _, s := range f.skills {
+		if s.StaffID != staffID {
+			kept = append(kept, s)
+		}
+	}
+	for _, id := range categoryIDs {
+		kept = append(kept, models.StaffCategory{StaffID: staffID, CategoryID: id, SocietyID: societyID})
+	}
+	f.skills = kept
+	return nil
+}
+
+type fakeRoles struct {
+	rows []models.Role
+}
+
+func (f *fakeRoles) Find(ctx context.Context, societyID uint, name string) (*models.Role, error) {
+	for _, r := range f.rows {
+		if r.SocietyID == societyID && r.Name == name {
+			return &r, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeRoles) FindByID(ctx context.Context, societyID, id uint) (*models.Role, error) {
+	for _, r := range f.rows {
+		if r.SocietyID == societyID && r.ID == id {
+			return &r, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeRoles) List(ctx context.Context, societyID uint) ([]models.Role, error) {
+	var out []models.Role
+	for _, r := range f.rows {
+		if r.SocietyID == societyID {
+			out = append(out, r)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func (f *fakeRoles) Create(ctx context.Context, role *models.Role) error {
+	role.ID = uint(len(f.rows) + 1)
+	f.rows = append(f.rows, *role)
+	return nil
+}
+
+func (f *fakeRoles) Save(ctx context.Context, role *models.Role) error {
+	for i := range f.rows {
+		if f.rows[i].ID == role.ID {
+			f.rows[i] = *role
+		}
+	}
+	return nil
+}
+
+func (f *fakeRoles) Delete(ctx context.Context, role *models.Role) error {
+	for i := range f.rows {
+		if f.rows[i].ID == role.ID {
+			f.rows = append(f.rows[:i], f.rows[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// customRole builds a custom role of the test society granting perms
+func customRole(id uint, name string, perms ...string) models.Role {
+	return models.Role{ID: id, SocietyID: testSociety, Name: name, Permissions: strings.Join(perms, ",")}
+}
+
+// fakeCursors keeps one round-robin cursor per society and category
+type fakeCursors struct {
+	last map[[2]uint]uint
+}
+
+func (f *fakeCursors) AdvanceCursor(ctx context.Context, societyID, categoryID uint, next func(lastStaffID uint) uint) error {
+	if f.last == nil {
+		f.last = map[[2]uint]uint{}
+	}
+	key := [2]uint{societyID, categoryID}
+	f.last[key] = next(f.last[key])
+	return nil
+}
+
+type fakeAudit struct {
+	rows []models.AuditLog
+}
+
+func (f *fakeAudit) Create(ctx context.Context, entry *models.AuditLog) error {
+	f.rows = append(f.rows, *entry)
+	return nil
+}
+
+// fakeTx runs the function directly; the fakes have nothing to roll back
+type fakeTx struct{}
+
+func (fakeTx) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeEscalations struct {
+	policies []models.EscalationPolicy
+	records  []models.ComplaintEscalation
+}
+
+func (f *fakeEscalations) Policies(ctx context.Context, societyID uint) ([]models.EscalationPolicy, error) {
+	var out []models.EscalationPolicy
+	for _, p := range f.policies {
+		if p.SocietyID == societyID {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeEscalations) AllPolicies(ctx context.Context) ([]models.EscalationPolicy, error) {
+	return f.policies, nil
+}
+
+func (f *fakeEscalations) CreatePolicy(ctx context.Context, policy *models.EscalationPolicy) error {
+	policy.ID = uint(len(f.policies) + 1)
+	f.policies = append(f.policies, *policy)
+	return nil
+}
+
+func (f *fakeEscalations) DeletePolicy(ctx context.Context, societyID, id uint) error {
+	for i, p := range f.policies {
+		if p.ID == id && p.SocietyID == societyID {
+			f.policies = append(f.policies[:i], f.policies[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (f *fakeEscalations) History(ctx context.Context, complaintID uint) ([]models.ComplaintEscalation, error) {
+	var out []models.ComplaintEscalation
+	for _, r := range f.records {
+		if r.ComplaintID == complaintID {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeEscalations) Record(ctx context.Context, escalation *models.ComplaintEscalation) error {
+	escalation.ID = uint(len(f.records) + 1)
+	f.records = append(f.records, *escalation)
+	return nil
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/VinVorteX/flashtrack/internal/dto"
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/repository"
+)
+
+var (
+	ErrFeedbackNotOwner    = errors.New("you can only provide feedback for your own complaints")
+	ErrFeedbackNotResolved = errors.New("can only provide feedback for resolved complaints")
+	ErrFeedbackExists      = errors.New("feedback already submitted for this complaint")
+)
+
+// pointsPerStar is how many points staff earn for each star of a resident's rating
+const pointsPerStar = 2
+
+// FeedbackService records residents' ratings of resolved complaints and the staff points they earn
+type FeedbackService struct {
+	Feedback   repository.FeedbackRepository
+	Complaints repository.ComplaintRepository
+	Users      repository.UserRepository
+}
+
+// Submit rates the resident's resolved complaint and awards the staff member who handled it
+func (fs *FeedbackService) Submit(ctx context.Context, user *models.User, complaintID uint, rating int, comment string) (*models.Feedback, error) {
+	complaint, err := fs.Complaints.Find(ctx, user.SocietyID, complaintID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrComplaintNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if complaint.ResidentID != user.ID {
+		return nil, ErrFeedbackNotOwner
+	}
+	// A merged duplicate shares its primary's resolution, which is rated on the primary
+	if complaint.DuplicateOfID != nil {
+		return nil, ErrMergedComplaint
+	}
+	if complaint.Status != "resolved" || complaint.StaffID == nil {
+		return nil, ErrFeedbackNotResolved
+	}
+
+	// Only the current resolution can be rated; feedback reversed by a reopen does not count
+	if _, err := fs.Feedback.FindActive(ctx, complaint.ID); err == nil {
+		return nil, ErrFeedbackExists
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	feedback := &models.Feedback{
+		ComplaintID: complaint.ID,
+		UserID:      user.ID,
+		StaffID:     *complaint.StaffID,
+		Rating:      rating,
+		Comment:     comment,
+		Points:      rating * pointsPerStar,
+	}
+	if err := fs.Feedback.Create(ctx, feedback); err != nil {
+		return nil, err
+	}
+
+	if err := fs.Feedback.AwardPoints(ctx, feedback.StaffID, feedback.Points); err != nil {
+		return nil, err
+	}
+	return feedback, nil
+}
+
+// StaffPoints returns the staff member's points, zero when they have none yet
+func (fs *FeedbackService) StaffPoints(ctx context.Context, staffID uint) (*models.StaffPoints, error) {
+	points, err := fs.Feedback.StaffPoints(ctx, staffID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return &models.StaffPoints{StaffID: staffID}, nil
+	}
+	return points, err
+}
+
+// Pending returns which of complaintIDs the user has not rated yet
+func (fs *FeedbackService) Pending(ctx context.Context, userID uint, complaintIDs []uint) ([]uint, error) {
+	rated, err := fs.Feedback.RatedComplaintIDs(ctx, userID, complaintIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	hasFeedback := make(map[uint]bool, len(rated))
+	for _, id := range rated {
+		hasFeedback[id] = true
+	}
+
+	var pending []uint
+	for _, id := range complaintIDs {
+		if !hasFeedback[id] {
+			pending = append(pending, id)
+		}
+	}
+	return pending, nil
+}
+
+// ListForSociety returns the society's feedback with complaint titles and resident and staff names
+func (fs *FeedbackService) ListForSociety(ctx context.Context, societyID uint) ([]dto.FeedbackDetails, error) {
+	feedbacks, err := fs.Feedback.ListBySociety(ctx, societyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var complaintIDs, userIDs []uint
+	for _, fb := range feedbacks {
+		complaintIDs = append(complaintIDs, fb.ComplaintID)
+		userIDs = append(userIDs, fb.UserID, fb.StaffID)
+	}
+	titles, err := fs.Complaints.Titles(ctx, uniqueIDs(complaintIDs))
+	if err != nil {
+		return nil, err
+	}
+	names, err := fs.Users.Names(ctx, uniqueIDs(userIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	details := []dto.FeedbackDetails{}
+	for i := range feedbacks {
+		fb := &feedbacks[i]
+		details = append(details, dto.FeedbackDetails{
+			FeedbackResponse: dto.NewFeedbackResponse(fb),
+			ComplaintTitle:   titles[fb.ComplaintID],
+			UserName:         names[fb.UserID],
+			StaffName:        names[fb.StaffID],
+		})
+	}
+	return details, nil
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+)
+
+func newFeedbackFixture(complaints ...models.Complaint) (*FeedbackService, *fakeFeedback) {
+	feedback := newFakeFeedback()
+	return &FeedbackService{
+		Feedback:   feedback,
+		Complaints: newFakeComplaints(complaints...),
+		Users:      newFakeUsers(testStaff, testResident, testNeighbor),
+	}, feedback
+}
+
+func TestSubmitFeedbackAwardsPoints(t *testing.T) {
+	service, feedback := newFeedbackFixture(resolvedComplaint(1, time.Hour))
+	ctx := context.Background()
+
+	fb, err := service.Submit(ctx, &testResident, 1, 4, "quick fix")
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if fb.Points != 4*pointsPerStar || fb.StaffID != testStaff.ID {
+		t.Errorf("feedback = %+v", fb)
+	}
+
+	points, err := service.StaffPoints(ctx, testStaff.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if points.TotalPoints != 8 || points.TasksCompleted != 1 {
+		t.Errorf("staff points = %+v, want 8 points for 1 task", points)
+	}
+
+	if _, err := service.Submit(ctx, &testResident, 1, 5, "again"); !errors.Is(err, ErrFeedbackExists) {
+		t.Errorf("second submission: expected ErrFeedbackExists, got %v", err)
+	}
+	if len(feedback.rows) != 1 {
+		t.Errorf("stored %d feedbacks, want 1", len(feedback.rows))
+	}
+}
+
+func TestSubmitFeedbackRefusals(t *testing.T) {
+	pending := models.Complaint{ID: 2, Status: "pending", SocietyID: testSociety, ResidentID: testResident.ID}
+	duplicate := resolvedComplaint(3, time.Hour)
+	duplicate.DuplicateOfID = &pending.ID
+	service, feedback := newFeedbackFixture(resolvedComplaint(1, time.Hour), pending, duplicate)
+	ctx := context.Background()
+
+	if _, err := service.Submit(ctx, &testNeighbor, 1, 5, ""); !errors.Is(err, ErrFeedbackNotOwner) {
+		t.Errorf("other resident: expected ErrFeedbackNotOwner, got %v", err)
+	}
+	if _, err := service.Submit(ctx, &testResident, 2, 5, ""); !errors.Is(err, ErrFeedbackNotResolved) {
+		t.Errorf("pending complaint: expected ErrFeedbackNotResolved, got %v", err)
+	}
+	if _, err := service.Submit(ctx, &testResident, 3, 5, ""); !errors.Is(err, ErrMergedComplaint) {
+		t.Errorf("merged duplicate: expected ErrMergedComplaint, got %v", err)
+	}
+	if _, err := service.Submit(ctx, &testResident, 42, 5, ""); !errors.Is(err, ErrComplaintNotFound) {
+		t.Errorf("missing complaint: expected ErrComplaintNotFound, got %v", err)
+	}
+	if len(feedback.rows) != 0 || len(feedback.points) != 0 {
+		t.Error("a refused submission stored feedback or points")
+	}
+}
+
+func TestStaffPointsDefaultToZero(t *testing.T) {
+	service, _ := newFeedbackFixture()
+
+	points, err := service.StaffPoints(context.Background(), testStaff.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if points.TotalPoints != 0 || points.TasksCompleted != 0 {
+		t.Errorf("points = %+v, want zero", points)
+	}
+}
+
+func TestPendingFeedbackSkipsRatedComplaints(t *testing.T) {
+	service, _ := newFeedbackFixture(resolvedComplaint(1, time.Hour), resolvedComplaint(2, time.Hour))
+	ctx := context.Background()
+	if _, err := service.Submit(ctx, &testResident, 1, 3, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	pending, err := service.Pending(ctx, testResident.ID, []uint{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(pending, []uint{2}) {
+		t.Errorf("pending = %v, want [2]", pending)
+	}
+}
@@ -0,0 +1,488 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/repository"
+	"github.com/VinVorteX/flashtrack/internal/utils"
+)
+
+// Actions reported for each imported row
+const (
+	ImportCreate    = "create"
+	ImportUpdate    = "update"
+	ImportUnchanged = "unchanged"
+)
+
+// MaxImportRows bounds a single import so one request cannot tie up the server
+const MaxImportRows = 5000
+
+var ErrEmptyImport = errors.New("csv file has no data rows")
+
+// ImportOptions controls how an import is applied
+type ImportOptions struct {
+	DryRun      bool
+	SendInvites bool
+	// Actor is the admin running the import, in whose name released complaints are reassigned
+	Actor *models.User
+}
+
+// ImportRowResult is the outcome of a single csv row
+type ImportRowResult struct {
+	Row    int      `json:"row"` // line number in the file, the header being line 1
+	Key    string   `json:"key"` // email or category name
+	Action string   `json:"action,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// ImportReport summarises an import. Nothing is written unless every row is valid.
+type ImportReport struct {
+	DryRun    bool              `json:"dry_run"`
+	Applied   bool              `json:"applied"`
+	Total     int               `json:"total"`
+	Created   int               `json:"created"`
+	Updated   int               `json:"updated"`
+	Unchanged int               `json:"unchanged"`
+	Failed    int               `json:"failed"`
+	Invited   int               `json:"invited"`
+	Rows      []ImportRowResult `json:"rows"`
+	// Complaints reports the open complaints of staff moved to another role, as a user update does
+	Complaints *ReleasedComplaints `json:"complaints,omitempty"`
+}
+
+func (r *ImportReport) count(result ImportRowResult) {
+	r.Total++
+	switch {
+	case len(result.Errors) > 0:
+		r.Failed++
+	case result.Action == ImportCreate:
+		r.Created++
+	case result.Action == ImportUpdate:
+		r.Updated++
+	default:
+		r.Unchanged++
+	}
+	r.Rows = append(r.Rows, result)
+}
+
+// ImportService bulk-loads users and categories into a society from csv
+type ImportService struct {
+	Users      repository.UserRepository
+	Categories repository.CategoryRepository
+	Tx         repository.Transactor
+	Accounts   *UserService
+	Invites    *InviteService
+	Roles      *RoleService
+	Plans      *PlanService
+	Locations  *LocationService
+}
+
+// csvRecord is a data row keyed by lower-cased header name
+type csvRecord struct {
+	line   int
+	fields map[string]string
+}
+
+// readCSV parses a csv with a header row, rejecting unknown or missing columns
+func readCSV(r io.Reader, required, optional []string) ([]csvRecord, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, ErrEmptyImport
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv: %w", err)
+	}
+
+	known := map[string]bool{}
+	for _, name := range append(append([]string{}, required...), optional...) {
+		known[name] = true
+	}
+	columns := make([]string, len(header))
+	present := map[string]bool{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !known[name] {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		columns[i] = name
+		present[name] = true
+	}
+	for _, name := range required {
+		if !present[name] {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	var records []csvRecord
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		record := csvRecord{line: line, fields: map[string]string{}}
+		for i, value := range values {
+			record.fields[columns[i]] = strings.TrimSpace(value)
+		}
+		records = append(records, record)
+		if len(records) > MaxImportRows {
+			return nil, fmt.Errorf("csv file has more than %d rows", MaxImportRows)
+		}
+	}
+	if len(records) == 0 {
+		return nil, ErrEmptyImport
+	}
+	return records, nil
+}
+
+// userImport is a validated user row waiting to be written
+type userImport struct {
+	line     int
+	user     models.User
+	password string
+	invite   bool
+	seat     string // plan seat limit the row takes up, if it adds an account to one
+	release  bool   // staff moved to a role that cannot work complaints hand back their open ones
+}
+
+// ImportUsers creates or updates users of the society keyed on email.
+// Columns: name, email, role (user, staff or a custom role of the society, never admin), and
+// optionally unit and password. A unit is either a location ID or a path of names such as "Tower A/3/301".
+func (is *ImportService) ImportUsers(ctx context.Context, societyID uint, r io.Reader, opts ImportOptions) (*ImportReport, error) {
+	records, err := readCSV(r, []string{"name", "email", "role"}, []string{"unit", "password"})
+	if err != nil {
+		return nil, err
+	}
+
+	// Everything the rows are checked against is loaded up front, so the query count does not grow with the file
+	units, err := is.Locations.UnitLookup(ctx, societyID)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := is.Roles.Lookup(ctx, societyID)
+	if err != nil {
+		return nil, err
+	}
+	emails := make([]string, 0, len(records))
+	for _, record := range records {
+		if email := normalizeEmail(record.fields["email"]); email != "" {
+			emails = append(emails, email)
+		}
+	}
+	members, err := is.Users.FindByEmails(ctx, societyID, emails)
+	if err != nil {
+		return nil, err
+	}
+	existingByEmail := make(map[string]models.User, len(members))
+	for _, member := range members {
+		existingByEmail[normalizeEmail(member.Email)] = member
+	}
+	// Emails are unique across societies, which the tenant session cannot see into
+	taken, err := is.Users.EmailsInOtherSocieties(ctx, societyID, emails)
+	if err != nil {
+		return nil, err
+	}
+	elsewhere := make(map[string]bool, len(taken))
+	for _, email := range taken {
+		elsewhere[email] = true
+	}
+
+	report := &ImportReport{DryRun: opts.DryRun, Rows: []ImportRowResult{}}
+	var pending []userImport
+	seen := map[string]int{}
+
+	for _, record := range records {
+		email := normalizeEmail(record.fields["email"])
+		result := ImportRowResult{Row: record.line, Key: email}
+		row := userImport{line: record.line, password: record.fields["password"]}
+
+		fail := func(format string, args ...interface{}) {
+			result.Errors = append(result.Errors, fmt.Sprintf(format, args...))
+		}
+
+		name := record.fields["name"]
+		role := strings.ToLower(record.fields["role"])
+		if name == "" {
+			fail("name is required")
+		}
+		if email == "" || !strings.Contains(email, "@") {
+			fail("a valid email is required")
+		} else if first, ok := seen[email]; ok {
+			fail("email already appears on line %d", first)
+		} else {
+			seen[email] = record.line
+		}
+		exists, isStaff := roles(role)
+		switch {
+		case role == "":
+			fail("role is required")
+		case role == "admin":
+			fail("admin accounts cannot be created or assigned by import")
+		case !exists:
+			fail("role %q is not defined in this society", role)
+		}
+
+		var unitID *uint
+		if unit := record.fields["unit"]; unit != "" {
+			if id, ok := units(unit); ok {
+				unitID = &id
+			} else {
+				fail("unit %q not found", unit)
+			}
+		}
+
+		existing, found := existingByEmail[email]
+		switch {
+		case elsewhere[email]:
+			fail("email is registered in another society")
+		case found && existing.Role == "admin":
+			fail("admin accounts cannot be changed by import")
+		case found:
+			row.user = existing
+			row.release = existing.Role != role && !isStaff
+			if existing.IsActive() && SeatLimit(existing.Role) != SeatLimit(role) {
+				row.seat = SeatLimit(role)
+			}
+			if existing.Name == name && existing.Role == role && sameUnit(existing.UnitID, unitID) {
+				result.Action = ImportUnchanged
+			} else {
+				result.Action = ImportUpdate
+			}
+		default:
+			row.user = models.User{Email: email, SocietyID: societyID}
+			row.seat = SeatLimit(role)
+			result.Action = ImportCreate
+			if row.password == "" {
+				if opts.SendInvites {
+					row.invite = true
+				} else {
+					fail("password is required unless invites are sent")
+				}
+			}
+		}
+
+		// The role and unit were checked against the lookups above, which covers the registration rules
+		// an import can reach since it never makes admins
+		if len(result.Errors) == 0 && result.Action != ImportUnchanged {
+			row.user.Name = name
+			row.user.Role = role
+			row.user.UnitID = unitID
+		}
+
+		if len(result.Errors) > 0 {
+			result.Action = ""
+		}
+		report.count(result)
+		if len(result.Errors) == 0 && result.Action != ImportUnchanged {
+			pending = append(pending, row)
+		}
+	}
+
+	if report.Failed > 0 {
+		return report, nil
+	}
+
+	// The import as a whole must fit in the plan's seats, which a dry run reports too
+	seats := map[string]int64{}
+	for _, row := range pending {
+		if row.seat != "" {
+			seats[row.seat]++
+		}
+	}
+	for _, role := range []string{"user", "staff"} {
+		if err := is.Plans.CheckSeats(ctx, societyID, role, seats[SeatLimit(role)]); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	// bcrypt is slow by design, so hash on every core before opening the transaction
+	var wg sync.WaitGroup
+	work := make(chan *userImport)
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for row := range work {
+				row.user.Password = utils.HashPassword(row.password)
+			}
+		}()
+	}
+	for i := range pending {
+		if pending[i].password != "" && pending[i].user.ID == 0 {
+			work <- &pending[i]
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	type invitation struct {
+		user  models.User
+		token string
+	}
+	var invitations []invitation
+	released := []models.Complaint{}
+
+	err = is.Tx.Transaction(ctx, func(ctx context.Context) error {
+		for i := range pending {
+			row := &pending[i]
+			if row.user.ID == 0 {
+				// Invited users have no password until they accept the invite
+				if err := is.Users.Create(ctx, &row.user); err != nil {
+					return fmt.Errorf("line %d: %w", row.line, err)
+				}
+			} else if err := is.Users.Update(ctx, row.user.ID,
+				map[string]interface{}{"name": row.user.Name, "role": row.user.Role, "unit_id": row.user.UnitID}); err != nil {
+				return fmt.Errorf("line %d: %w", row.line, err)
+			}
+			if row.release {
+				complaints, err := is.Accounts.Complaints.Release(ctx, row.user.ID)
+				if err != nil {
+					return err
+				}
+				released = append(released, complaints...)
+			}
+
+			if row.invite {
+				token, err := is.Invites.Create(ctx, row.user.ID)
+				if err != nil {
+					return err
+				}
+				invitations = append(invitations, invitation{row.user, token})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.Applied = true
+	if len(released) > 0 {
+		report.Complaints = is.Accounts.reassign(ctx, released, opts.Actor)
+	}
+	for _, inv := range invitations {
+		if err := is.Invites.Send(&inv.user, inv.token); err != nil {
+			slog.ErrorContext(ctx, "failed to send invite", "user_id", inv.user.ID, "error", err)
+			continue
+		}
+		report.Invited++
+	}
+
+	return report, nil
+}
+
+// ImportCategories creates or updates the society's own categories keyed on name.
+// Columns: name, and optionally sla_hours and default_priority.
+func (is *ImportService) ImportCategories(ctx context.Context, societyID uint, r io.Reader, opts ImportOptions) (*ImportReport, error) {
+	records, err := readCSV(r, []string{"name"}, []string{"sla_hours", "default_priority"})
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := is.Categories.ListOwned(ctx, societyID)
+	if err != nil {
+		return nil, err
+	}
+	byName := map[string]models.Category{}
+	for _, category := range existing {
+		byName[strings.ToLower(category.Name)] = category
+	}
+
+	report := &ImportReport{DryRun: opts.DryRun, Rows: []ImportRowResult{}}
+	var pending []models.Category
+	seen := map[string]int{}
+
+	for _, record := range records {
+		name := record.fields["name"]
+		key := strings.ToLower(name)
+		result := ImportRowResult{Row: record.line, Key: name}
+
+		if name == "" {
+			result.Errors = append(result.Errors, "name is required")
+		} else if first, ok := seen[key]; ok {
+			result.Errors = append(result.Errors, fmt.Sprintf("category already appears on line %d", first))
+		} else {
+			seen[key] = record.line
+		}
+
+		category, found := byName[key]
+		if !found {
+			category = models.Category{Name: name, SocietyID: societyID}
+		}
+		updated := category
+
+		if value, ok := record.fields["sla_hours"]; ok && value != "" {
+			hours, err := strconv.Atoi(value)
+			if err != nil || hours < 0 {
+				result.Errors = append(result.Errors, "sla_hours must be a whole number of hours")
+			}
+			updated.SLAHours = hours
+		}
+		if value, ok := record.fields["default_priority"]; ok && value != "" {
+			value = strings.ToLower(value)
+			if !IsValidPriority(value) {
+				result.Errors = append(result.Errors, "default_priority must be low, medium, high or urgent")
+			}
+			updated.DefaultPriority = value
+		}
+
+		switch {
+		case len(result.Errors) > 0:
+		case !found:
+			result.Action = ImportCreate
+			pending = append(pending, updated)
+		case updated != category:
+			result.Action = ImportUpdate
+			pending = append(pending, updated)
+		default:
+			result.Action = ImportUnchanged
+		}
+		report.count(result)
+	}
+
+	if opts.DryRun || report.Failed > 0 {
+		return report, nil
+	}
+
+	err = is.Tx.Transaction(ctx, func(ctx context.Context) error {
+		for i := range pending {
+			if err := is.Categories.Save(ctx, &pending[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.Applied = true
+	return report, nil
+}
+
+func sameUnit(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
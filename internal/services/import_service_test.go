@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+)
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		rows    int
+		wantErr string
+	}{
+		{"header only", "name,email,role\n", 0, "no data rows"},
+		{"empty file", "", 0, "no data rows"},
+		{"missing column", "name,email\nA,a@example.com\n", 0, `missing column "role"`},
+		{"unknown column", "name,email,role,age\nA,a@example.com,user,3\n", 0, `unknown column "age"`},
+		{"byte order mark and spacing", "\ufeffName, Email ,ROLE\nA, a@example.com,user\n", 1, ""},
+		{"optional columns", "name,email,role,unit,password\nA,a@example.com,user,,secret\nB,b@example.com,staff,,\n", 2, ""},
+		{"ragged row", "name,email,role\nA,a@example.com\n", 0, "invalid csv"},
+	}
+	for _, tt := range tests {
+		records, err := readCSV(strings.NewReader(tt.csv), []string{"name", "email", "role"}, []string{"unit", "password"})
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || len(records) != tt.rows {
+			t.Errorf("%s: %d records, error %v, want %d records", tt.name, len(records), err, tt.rows)
+		}
+	}
+
+	records, _ := readCSV(strings.NewReader("name,email,role\n  A , a@example.com ,user\n"), []string{"name", "email", "role"}, nil)
+	if got := records[0]; got.line != 2 || got.fields["name"] != "A" || got.fields["email"] != "a@example.com" {
+		t.Errorf("record %+v, want trimmed fields on line 2", got)
+	}
+
+	if _, err := readCSV(strings.NewReader("name\n"+strings.Repeat("x\n", MaxImportRows+1)), []string{"name"}, nil); err == nil {
+		t.Errorf("read %d rows, want the import refused", MaxImportRows+1)
+	}
+	if _, err := readCSV(strings.NewReader("name\n"), []string{"name"}, nil); !errors.Is(err, ErrEmptyImport) {
+		t.Errorf("expected ErrEmptyImport, got %v", err)
+	}
+}
+
+func TestImportCategoriesUpsertsByName(t *testing.T) {
+	categories := newFakeCategories(
+		models.Category{ID: 1, Name: "Plumbing", SocietyID: testSociety, SLAHours: 24, DefaultPriority: PriorityMedium},
+		models.Category{ID: 2, Name: "Lifts", SocietyID: testSociety, SLAHours: 8, DefaultPriority: PriorityHigh},
+	)
+	service := &ImportService{Categories: categories, Tx: fakeTx{}}
+	csv := "name,sla_hours,default_priority\nplumbing,12,HIGH\nLifts,8,high\nGarden,,\n"
+	ctx := context.Background()
+
+	report, err := service.ImportCategories(ctx, testSociety, strings.NewReader(csv), ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if report.Applied || report.Updated != 1 || report.Unchanged != 1 || report.Created != 1 || len(categories.rows) != 2 {
+		t.Fatalf("dry run report %+v with %d categories, want it to change nothing", report, len(categories.rows))
+	}
+
+	if _, err := service.ImportCategories(ctx, testSociety, strings.NewReader(csv), ImportOptions{}); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if c := categories.rows[1]; c.Name != "Plumbing" || c.SLAHours != 12 || c.DefaultPriority != PriorityHigh {
+		t.Errorf("plumbing stored as %+v, want it updated in place", *c)
+	}
+	if len(categories.rows) != 3 {
+		t.Errorf("%d categories, want the garden added", len(categories.rows))
+	}
+
+	// Importing the same file again changes nothing
+	report, err = service.ImportCategories(ctx, testSociety, strings.NewReader(csv), ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Unchanged != 3 || len(categories.rows) != 3 {
+		t.Errorf("second import report %+v with %d categories, want everything unchanged", report, len(categories.rows))
+	}
+}
+
+func TestImportCategoriesRejectsTheWholeFileOnErrors(t *testing.T) {
+	categories := newFakeCategories()
+	service := &ImportService{Categories: categories, Tx: fakeTx{}}
+	csv := "name,sla_hours,default_priority\nGarden,4,low\n,4,low\ngarden,2,\nLifts,-1,\nPower,2,asap\n"
+
+	report, err := service.ImportCategories(context.Background(), testSociety, strings.NewReader(csv), ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Applied || report.Failed != 4 || len(categories.rows) != 0 {
+		t.Fatalf("report %+v with %d categories, want nothing written", report, len(categories.rows))
+	}
+	want := []string{"", "name is required", "category already appears on line 2", "sla_hours must be", "default_priority must be"}
+	for i, row := range report.Rows {
+		if want[i] == "" {
+			if len(row.Errors) != 0 || row.Action != ImportCreate {
+				t.Errorf("row %d: %+v, want a valid create", row.Row, row)
+			}
+			continue
+		}
+		if len(row.Errors) != 1 || !strings.HasPrefix(row.Errors[0], want[i]) || row.Action != "" {
+			t.Errorf("row %d: %+v, want the error %q", row.Row, row, want[i])
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/repository"
+	"github.com/VinVorteX/flashtrack/internal/utils"
+)
+
+// InviteTTL is how long an invite link stays valid
+const InviteTTL = 7 * 24 * time.Hour
+
+var ErrInvalidInvite = errors.New("invite is invalid, expired or already used")
+
+// InviteService issues and redeems password-setup invites. Links in the emails point at AppURL;
+// without a Mailer they are written to the log.
+type InviteService struct {
+	Invites repository.InviteRepository
+	Mailer  Mailer
+	AppURL  string
+}
+
+// Create stores a new invite for the user and returns the raw token. Called with a
+// transaction's context, the invite is only kept when the transaction commits.
+func (is *InviteService) Create(ctx context.Context, userID uint) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(raw)
+
+	invite := models.Invite{
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(InviteTTL),
+	}
+	if err := is.Invites.Create(ctx, &invite); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Send emails the invite link to a newly created user
+func (is *InviteService) Send(user *models.User, token string) error {
+	return is.send(user, "You're invited to FlashTrack",
+		"An account has been created for you on FlashTrack.\nSet your password here", token)
+}
+
+// SendReset emails a password reset link, which is redeemed like an invite
+func (is *InviteService) SendReset(user *models.User, token string) error {
+	return is.send(user, "Reset your FlashTrack password",
+		"An administrator has asked you to choose a new FlashTrack password.\nSet it here", token)
+}
+
+func (is *InviteService) send(user *models.User, subject, intro, token string) error {
+	mailer := is.Mailer
+	if mailer == nil {
+		mailer = LogMailer{}
+	}
+
+	link := fmt.Sprintf("%s/accept-invite?token=%s", is.AppURL, token)
+	body := fmt.Sprintf("Hi %s,\n\n%s within %d days:\n\n%s\n", user.Name, intro, int(InviteTTL.Hours()/24), link)
+	return mailer.Send(user.Email, subject, body)
+}
+
+// Accept sets the invited user's password and marks the invite as used
+func (is *InviteService) Accept(ctx context.Context, token, password string) error {
+	now := time.Now()
+	invite, err := is.Invites.FindPending(ctx, hashToken(token), now)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrInvalidInvite
+	}
+	if err != nil {
+		return err
+	}
+
+	err = is.Invites.Accept(ctx, invite, utils.HashPassword(password), now)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrInvalidInvite
+	}
+	return err
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+)
+
+// Location types
+const (
+	LocationBuilding   = "building"
+	LocationFloor      = "floor"
+	LocationUnit       = "unit"
+	LocationCommonArea = "common_area"
+)
+
+// allowedParents lists the parent types each location type may hang under; "" means the society root
+var allowedParents = map[string][]string{
+	LocationBuilding:   {""},
+	LocationFloor:      {LocationBuilding},
+	LocationUnit:       {LocationFloor},
+	LocationCommonArea: {"", LocationBuilding, LocationFloor},
+}
+
+var (
+	ErrInvalidLocation       = errors.New("location not found in this society")
+	ErrInvalidLocationParent = errors.New("location cannot be placed under that parent")
+	ErrNotComplaintLocation  = errors.New("complaints can only target a unit or a common area")
+	ErrNotOwnUnit            = errors.New("residents can only report for their own unit or a common area")
+	ErrLocationInUse         = errors.New("location has child locations, residents or complaints")
+)
+
+// LocationStat summarises complaints for a top-level location and everything beneath it
+type LocationStat struct {
+	LocationID uint           `json:"location_id"`
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Total      int64          `json:"total"`
+	Open       int64          `json:"open"`
+	Resolved   int64          `json:"resolved"`
+	Last30Days int64          `gorm:"column:last_30_days" json:"last_30_days"`
+	ByCategory map[uint]int64 `gorm:"-" json:"by_category"`
+}
+
+// LocationService manages the society location tree
+type LocationService struct{}
+
+// Find loads a location that belongs to the society
+func (ls *LocationService) Find(ctx context.Context, societyID, locationID uint) (*models.Location, error) {
+	var location models.Location
+	if err := database.Session(ctx).Where("id = ? AND society_id = ?", locationID, societyID).First(&location).Error; err != nil {
+		return nil, ErrInvalidLocation
+	}
+	return &location, nil
+}
+
+// Create validates the location's place in the hierarchy and stores it
+func (ls *LocationService) Create(ctx context.Context, location *models.Location) error {
+	parents, ok := allowedParents[location.Type]
+	if !ok {
+		return errors.New("type must be building, floor, unit or common_area")
+	}
+
+	parentType := ""
+	if location.ParentID != nil {
+		parent, err := ls.Find(ctx, location.SocietyID, *location.ParentID)
+		if err != nil {
+			return err
+		}
+		parentType = parent.Type
+	}
+
+	for _, allowed := range parents {
+		if allowed == parentType {
+			return database.Session(ctx).Create(location).Error
+		}
+	}
+	return ErrInvalidLocationParent
+}
+
+// Delete removes a location that nothing refers to
+func (ls *LocationService) Delete(ctx context.Context, societyID, locationID uint) error {
+	location, err := ls.Find(ctx, societyID, locationID)
+	if err != nil {
+		return err
+	}
+
+	db := database.Session(ctx)
+	var children, residents, complaints int64
+	db.Model(&models.Location{}).Where("parent_id = ?", location.ID).Count(&children)
+	db.Model(&models.User{}).Where("unit_id = ?", location.ID).Count(&residents)
+	db.Model(&models.Complaint{}).Where("location_id = ?", location.ID).Count(&complaints)
+	if children+residents+complaints > 0 {
+		return ErrLocationInUse
+	}
+
+	return db.Delete(location).Error
+}
+
+// ValidateUnit checks that unitID is a unit of the society
+func (ls *LocationService) ValidateUnit(ctx context.Context, societyID, unitID uint) error {
+	location, err := ls.Find(ctx, societyID, unitID)
+	if err != nil {
+		return err
+	}
+	if location.Type != LocationUnit {
+		return errors.New("residents can only be linked to a unit")
+	}
+	return nil
+}
+
+// ValidateComplaintLocation checks that a user may file a complaint against the location
+func (ls *LocationService) ValidateComplaintLocation(ctx context.Context, user *models.User, locationID uint) error {
+	location, err := ls.Find(ctx, user.SocietyID, locationID)
+	if err != nil {
+		return err
+	}
+
+	switch location.Type {
+	case LocationCommonArea:
+		return nil
+	case LocationUnit:
+		if user.Role == "user" && (user.UnitID == nil || *user.UnitID != location.ID) {
+			return ErrNotOwnUnit
+		}
+		return nil
+	}
+	return ErrNotComplaintLocation
+}
+
+// DescendantIDs returns the location and every location beneath it
+func (ls *LocationService) DescendantIDs(ctx context.Context, societyID, locationID uint) ([]uint, error) {
+	var ids []uint
+	err := database.Session(ctx).Raw(`
+		WITH RECURSIVE tree AS (
+			SELECT id FROM locations WHERE id = ? AND society_id = ?
+			UNION ALL
+			SELECT l.id FROM locations l JOIN tree t ON l.parent_id = t.id
+		)
+		SELECT id FROM tree`, locationID, societyID).Scan(&ids).Error
+	return ids, err
+}
+
+// Stats rolls complaint counts up to each top-level building or common area of the society
+func (ls *LocationService) Stats(ctx context.Context, societyID uint) ([]LocationStat, error) {
+	const tree = `
+		WITH RECURSIVE tree AS (
+			SELECT id, id AS root_id FROM locations WHERE society_id = @society AND parent_id IS NULL
+			UNION ALL
+			SELECT l.id, t.root_id FROM locations l JOIN tree t ON l.parent_id = t.id
+		)`
+
+	var stats []LocationStat
+	if err := database.Session(ctx).Raw(tree+`
+		SELECT r.id AS location_id, r.name, r.type,
+			COUNT(c.id) AS total,
+			COUNT(c.id) FILTER (WHERE c.status IN ('pending', 'in-progress')) AS open,
+			COUNT(c.id) FILTER (WHERE c.status = 'resolved') AS resolved,
+			COUNT(c.id) FILTER (WHERE c.created_at >= NOW() - INTERVAL '30 days') AS last_30_days
+		FROM locations r
+		JOIN tree t ON t.root_id = r.id
+		LEFT JOIN complaints c ON c.location_id = t.id AND c.duplicate_of_id IS NULL
+		GROUP BY r.id, r.name, r.type
+		ORDER BY total DESC, r.id`, map[string]interface{}{"society": societyID}).
+		Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+
+	var breakdown []struct {
+		RootID     uint
+		CategoryID uint
+		Count      int64
+	}
+	if err := database.Session(ctx).Raw(tree+`
+		SELECT t.root_id, c.category_id, COUNT(*) AS count
+		FROM tree t
+		JOIN complaints c ON c.location_id = t.id AND c.duplicate_of_id IS NULL
+		GROUP BY t.root_id, c.category_id`, map[string]interface{}{"society": societyID}).
+		Scan(&breakdown).Error; err != nil {
+		return nil, err
+	}
+
+	index := make(map[uint]int, len(stats))
+	for i := range stats {
+		stats[i].ByCategory = map[uint]int64{}
+		index[stats[i].LocationID] = i
+	}
+	for _, row := range breakdown {
+		if i, ok := index[row.RootID]; ok {
+			stats[i].ByCategory[row.CategoryID] = row.Count
+		}
+	}
+
+	return stats, nil
+}
+
+// UnitLookup returns a resolver from a unit reference, either an ID or a "/"-separated path of
+// names such as "Tower A/3/301", to the ID of one of the society's units
+func (ls *LocationService) UnitLookup(ctx context.Context, societyID uint) (func(string) (uint, bool), error) {
+	var locations []models.Location
+	if err := database.Session(ctx).Where("society_id = ?", societyID).Find(&locations).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]models.Location, len(locations))
+	for _, location := range locations {
+		byID[location.ID] = location
+	}
+
+	var path func(models.Location) string
+	path = func(location models.Location) string {
+		name := strings.ToLower(strings.TrimSpace(location.Name))
+		if location.ParentID == nil {
+			return name
+		}
+		parent, ok := byID[*location.ParentID]
+		if !ok {
+			return name
+		}
+		return path(parent) + "/" + name
+	}
+
+	byPath := map[string]uint{}
+	for _, location := range locations {
+		if location.Type == LocationUnit {
+			byPath[path(location)] = location.ID
+		}
+	}
+
+	return func(ref string) (uint, bool) {
+		if id, err := strconv.ParseUint(ref, 10, 32); err == nil {
+			location, ok := byID[uint(id)]
+			return location.ID, ok && location.Type == LocationUnit
+		}
+		parts := strings.Split(ref, "/")
+		for i := range parts {
+			parts[i] = strings.ToLower(strings.TrimSpace(parts[i]))
+		}
+		id, ok := byPath[strings.Join(parts, "/")]
+		return id, ok
+	}, nil
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"net/smtp"
+
+	"github.com/VinVorteX/flashtrack/config"
+)
+
+// Mailer sends plain-text email
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// NewMailer returns an SMTP mailer when an SMTP host is configured and a logging mailer otherwise
+func NewMailer(cfg config.MailConfig) Mailer {
+	if cfg.SMTPHost == "" {
+		return LogMailer{}
+	}
+	return &SMTPMailer{
+		Addr: cfg.SMTPHost + ":" + cfg.SMTPPort,
+		From: cfg.From,
+		Auth: smtp.PlainAuth("", cfg.SMTPUser, cfg.SMTPPassword, cfg.SMTPHost),
+	}
+}
+
+// SMTPMailer delivers email through an SMTP relay
+type SMTPMailer struct {
+	Addr string
+	From string
+	Auth smtp.Auth
+}
+
+// Send delivers a single message
+func (m *SMTPMailer) Send(to, subject, body string) error {
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s",
+		m.From, to, subject, body)
+	return smtp.SendMail(m.Addr, m.Auth, m.From, []string{to}, []byte(msg))
+}
+
+// LogMailer writes email to the server log, for development without an SMTP relay
+type LogMailer struct{}
+
+// Send logs the message instead of delivering it. The body carries invite links, which are
+// as good as passwords, so it is only logged at debug level.
+func (LogMailer) Send(to, subject, body string) error {
+	slog.Info("email not sent, no SMTP relay configured", "to", to, "subject", subject)
+	slog.Debug("email body", "to", to, "body", body)
+	return nil
+}
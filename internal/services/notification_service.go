@@ -1,26 +1,100 @@
 package services
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"strconv"
 	"sync"
+	"time"
 
+	"github.com/VinVorteX/flashtrack/internal/dto"
+	"github.com/VinVorteX/flashtrack/internal/logging"
 	"github.com/VinVorteX/flashtrack/internal/models"
-	"github.com/VinVorteX/flashtrack/pkg/database"
+	"github.com/VinVorteX/flashtrack/internal/repository"
+	"github.com/VinVorteX/flashtrack/internal/telemetry"
 	"github.com/gorilla/websocket"
 )
 
 var (
 	// Store active WebSocket connections
-	wsConnections = make(map[uint]*websocket.Conn)
+	wsConnections = make(map[uint]wsSession)
 	wsMutex       sync.RWMutex
 )
 
-// NotificationService handles notification operations
-type NotificationService struct{}
+// wsSession is a user's open WebSocket together with the ID of the request that opened it, so
+// deliveries can be logged against both the request that triggered them and the session
+type wsSession struct {
+	conn      *websocket.Conn
+	requestID string
+	// writeMu serialises writes: the socket allows one writer at a time, but request handlers
+	// and the escalation scheduler notify the same user concurrently
+	writeMu *sync.Mutex
+}
+
+// writeJSON sends v as the session's only writer
+func (s wsSession) writeJSON(v interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.conn.WriteJSON(v)
+}
+
+// NotificationChannel delivers a stored notification over an additional medium
+type NotificationChannel interface {
+	Name() string
+	Send(user *models.User, notification *models.Notification) error
+}
+
+// EmailChannel delivers notifications by email
+type EmailChannel struct {
+	Mailer Mailer
+}
+
+func (EmailChannel) Name() string { return "email" }
+
+// Send mails the notification to the user, skipping users without an email address
+func (ec EmailChannel) Send(user *models.User, notification *models.Notification) error {
+	if user.Email == "" {
+		return nil
+	}
+	return ec.Mailer.Send(user.Email, notification.Title, notification.Message)
+}
+
+// PushChannel delivers notifications to the user's phone through a Pusher
+type PushChannel struct {
+	Pusher Pusher
+}
+
+func (PushChannel) Name() string { return "push" }
+
+// Send pushes the notification to the user's registered device, skipping users without one
+func (pc PushChannel) Send(user *models.User, notification *models.Notification) error {
+	if user.FCMToken == "" {
+		return nil
+	}
+	data := map[string]string{
+		"type":            notification.Type,
+		"notification_id": strconv.FormatUint(uint64(notification.ID), 10),
+	}
+	if notification.ComplaintID != nil {
+		data["complaint_id"] = strconv.FormatUint(uint64(*notification.ComplaintID), 10)
+	}
+	return pc.Pusher.Push(user.FCMToken, notification.Title, notification.Message, data)
+}
+
+// NotificationService stores notifications and delivers them over WebSocket, and over Channels
+// for notifications that must reach users wherever they are
+type NotificationService struct {
+	Notifications repository.NotificationRepository
+	Users         repository.UserRepository
+	Channels      []NotificationChannel
+
+	// deliveries tracks channel sends still running in the background
+	deliveries sync.WaitGroup
+}
 
 // CreateNotification creates a new notification in database
-func (ns *NotificationService) CreateNotification(userID uint, title, message, notifType string, complaintID *uint) (*models.Notification, error) {
+func (ns *NotificationService) CreateNotification(ctx context.Context, userID uint, title, message, notifType string, complaintID *uint) (*models.Notification, error) {
 	notification := models.Notification{
 		UserID:      userID,
 		Title:       title,
@@ -30,73 +104,157 @@ func (ns *NotificationService) CreateNotification(userID uint, title, message, n
 		IsRead:      false,
 	}
 
-	if err := database.DB.Create(&notification).Error; err != nil {
+	if err := ns.Notifications.Create(ctx, &notification); err != nil {
 		return nil, err
 	}
+	telemetry.NotificationsCreated.WithLabelValues(notifType).Inc()
 
 	// Send real-time notification via WebSocket
-	ns.SendWebSocketNotification(userID, &notification)
+	ns.SendWebSocketNotification(ctx, userID, &notification)
 
 	return &notification, nil
 }
 
 // SendWebSocketNotification sends notification via WebSocket
-func (ns *NotificationService) SendWebSocketNotification(userID uint, notification *models.Notification) {
+func (ns *NotificationService) SendWebSocketNotification(ctx context.Context, userID uint, notification *models.Notification) {
 	wsMutex.RLock()
-	conn, exists := wsConnections[userID]
+	session, exists := wsConnections[userID]
 	wsMutex.RUnlock()
 
 	if exists {
-		if err := conn.WriteJSON(notification); err != nil {
-			log.Printf("Error sending WebSocket notification to user %d: %v", userID, err)
+		if err := session.writeJSON(dto.NewNotificationResponse(notification)); err != nil {
+			slog.WarnContext(ctx, "failed to send websocket notification", "user_id", userID,
+				"notification_id", notification.ID, "session_request_id", session.requestID, "error", err)
+			telemetry.NotificationDeliveries.WithLabelValues(telemetry.ChannelWebSocket, telemetry.Failed).Inc()
 			// Remove dead connection
-			ns.RemoveConnection(userID)
+			ns.RemoveConnection(ctx, userID)
+			return
 		}
+		slog.DebugContext(ctx, "sent websocket notification", "user_id", userID,
+			"notification_id", notification.ID, "session_request_id", session.requestID)
+		telemetry.NotificationDeliveries.WithLabelValues(telemetry.ChannelWebSocket, telemetry.Delivered).Inc()
 	}
 }
 
-// RegisterConnection registers a WebSocket connection for a user
-func (ns *NotificationService) RegisterConnection(userID uint, conn *websocket.Conn) {
+// RegisterConnection registers a WebSocket connection for a user, opened by the request in ctx
+func (ns *NotificationService) RegisterConnection(ctx context.Context, userID uint, conn *websocket.Conn) {
 	wsMutex.Lock()
-	wsConnections[userID] = conn
+	wsConnections[userID] = wsSession{conn: conn, requestID: logging.RequestID(ctx), writeMu: &sync.Mutex{}}
+	telemetry.WebSocketConnections.Set(float64(len(wsConnections)))
 	wsMutex.Unlock()
-	log.Printf("WebSocket connection registered for user %d", userID)
+	slog.InfoContext(ctx, "websocket connection registered", "user_id", userID)
 }
 
 // RemoveConnection removes a WebSocket connection
-func (ns *NotificationService) RemoveConnection(userID uint) {
+func (ns *NotificationService) RemoveConnection(ctx context.Context, userID uint) {
 	wsMutex.Lock()
 	delete(wsConnections, userID)
+	telemetry.WebSocketConnections.Set(float64(len(wsConnections)))
 	wsMutex.Unlock()
-	log.Printf("WebSocket connection removed for user %d", userID)
+	slog.InfoContext(ctx, "websocket connection removed", "user_id", userID)
 }
 
-// GetUserNotifications retrieves all notifications for a user
-func (ns *NotificationService) GetUserNotifications(userID uint) ([]models.Notification, error) {
-	var notifications []models.Notification
-	if err := database.DB.Where("user_id = ?", userID).Order("created_at DESC").Find(&notifications).Error; err != nil {
-		return nil, err
+// CloseConnections tells every connected client the server is going away and closes the
+// sockets, so their handlers return and the clients reconnect to another instance
+func (ns *NotificationService) CloseConnections() {
+	wsMutex.Lock()
+	defer wsMutex.Unlock()
+
+	message := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	for userID, session := range wsConnections {
+		session.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(time.Second))
+		session.conn.Close()
+		delete(wsConnections, userID)
 	}
-	return notifications, nil
+	telemetry.WebSocketConnections.Set(0)
+}
+
+// GetUserNotifications retrieves all notifications for a user
+func (ns *NotificationService) GetUserNotifications(ctx context.Context, userID uint) ([]models.Notification, error) {
+	return ns.Notifications.ListForUser(ctx, userID)
 }
 
 // MarkAsRead marks a notification as read
-func (ns *NotificationService) MarkAsRead(notificationID uint, userID uint) error {
-	return database.DB.Model(&models.Notification{}).
-		Where("id = ? AND user_id = ?", notificationID, userID).
-		Update("is_read", true).Error
+func (ns *NotificationService) MarkAsRead(ctx context.Context, notificationID uint, userID uint) error {
+	return ns.Notifications.MarkRead(ctx, notificationID, userID)
 }
 
 // NotifyStaffAssignment sends notification when staff is assigned to complaint
-func (ns *NotificationService) NotifyStaffAssignment(staffID uint, complaint *models.Complaint) error {
-	var staff models.User
-	if err := database.DB.First(&staff, staffID).Error; err != nil {
+func (ns *NotificationService) NotifyStaffAssignment(ctx context.Context, staffID uint, complaint *models.Complaint) error {
+	if _, err := ns.Users.Find(ctx, staffID); err != nil {
 		return err
 	}
 
 	title := "New Task Assigned"
 	message := fmt.Sprintf("You have been assigned to complaint #%d: %s", complaint.ID, complaint.Title)
 
-	_, err := ns.CreateNotification(staffID, title, message, "assignment", &complaint.ID)
+	_, err := ns.CreateNotification(ctx, staffID, title, message, "assignment", &complaint.ID)
+	return err
+}
+
+// NotifyEscalation notifies a user that a complaint has been escalated
+func (ns *NotificationService) NotifyEscalation(ctx context.Context, userID uint, complaint *models.Complaint, level int, reason string) error {
+	title := fmt.Sprintf("Complaint Escalated (Level %d)", level)
+	message := fmt.Sprintf("Complaint #%d: %s has been escalated - %s", complaint.ID, complaint.Title, reason)
+
+	_, err := ns.CreateNotification(ctx, userID, title, message, "escalation", &complaint.ID)
 	return err
 }
+
+// NotifyEmergency alerts every given user about an emergency complaint through the database,
+// WebSocket and every one of the service's channels. The channels reach outside services that
+// may be slow or down, so they are sent in the background and the caller does not wait for them.
+func (ns *NotificationService) NotifyEmergency(ctx context.Context, userIDs []uint, complaint *models.Complaint) {
+	title := "EMERGENCY Complaint"
+	message := fmt.Sprintf("Emergency reported in complaint #%d: %s", complaint.ID, complaint.Title)
+
+	type delivery struct {
+		user         *models.User
+		notification *models.Notification
+	}
+	var deliveries []delivery
+	for _, userID := range userIDs {
+		notification, err := ns.CreateNotification(ctx, userID, title, message, "emergency", &complaint.ID)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to create emergency notification", "user_id", userID, "error", err)
+			continue
+		}
+
+		if len(ns.Channels) == 0 {
+			continue
+		}
+
+		user, err := ns.Users.Find(ctx, userID)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to load user for emergency notification", "user_id", userID, "error", err)
+			continue
+		}
+		deliveries = append(deliveries, delivery{user: user, notification: notification})
+	}
+	if len(deliveries) == 0 {
+		return
+	}
+
+	// The request may finish first; keep its request and trace IDs for the log but not its
+	// cancellation. Nothing below touches the database, so its session is never used late.
+	ctx = context.WithoutCancel(ctx)
+	ns.deliveries.Add(1)
+	go func() {
+		defer ns.deliveries.Done()
+		for _, d := range deliveries {
+			for _, channel := range ns.Channels {
+				if err := channel.Send(d.user, d.notification); err != nil {
+					slog.ErrorContext(ctx, "failed to send emergency notification", "user_id", d.user.ID, "channel", channel.Name(), "error", err)
+					telemetry.NotificationDeliveries.WithLabelValues(channel.Name(), telemetry.Failed).Inc()
+					continue
+				}
+				telemetry.NotificationDeliveries.WithLabelValues(channel.Name(), telemetry.Delivered).Inc()
+			}
+		}
+	}()
+}
+
+// Wait blocks until every notification still being sent in the background has been sent
+func (ns *NotificationService) Wait() {
+	ns.deliveries.Wait()
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/gorilla/websocket"
+)
+
+// fakeMailer records sent email, failing for the addresses in fail
+type fakeMailer struct {
+	sent []string
+	fail map[string]bool
+}
+
+func (m *fakeMailer) Send(to, subject, body string) error {
+	if m.fail[to] {
+		return errors.New("relay refused")
+	}
+	m.sent = append(m.sent, to+": "+subject)
+	return nil
+}
+
+func TestNotifyEmergencyEmailsEveryone(t *testing.T) {
+	staff, admin := testStaff, testAdmin
+	staff.Email, admin.Email = "staff@example.com", "admin@example.com"
+	mailer := &fakeMailer{fail: map[string]bool{"admin@example.com": true}}
+	notifications := &fakeNotifications{}
+	service := &NotificationService{
+		Notifications: notifications,
+		Users:         newFakeUsers(staff, admin, testResident),
+		Channels:      []NotificationChannel{EmailChannel{Mailer: mailer}},
+	}
+
+	complaint := &models.Complaint{ID: 7, Title: "Gas leak", SocietyID: testSociety}
+	service.NotifyEmergency(context.Background(), []uint{staff.ID, admin.ID, testResident.ID}, complaint)
+	service.Wait()
+
+	// Every user is notified in the app, even when their email fails or they have no address
+	if got := notifications.recipients("emergency"); len(got) != 3 {
+		t.Errorf("stored emergency notifications for %v, want all three users", got)
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0] != "staff@example.com: EMERGENCY Complaint" {
+		t.Errorf("sent %v, want one email to the staff member", mailer.sent)
+	}
+}
+
+func TestWebSocketNotificationsWriteOneAtATime(t *testing.T) {
+	const senders = 50
+	serverConn := make(chan *websocket.Conn)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		serverConn <- conn
+	}))
+	defer server.Close()
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	service := &NotificationService{}
+	conn := <-serverConn
+	defer conn.Close()
+	service.RegisterConnection(ctx, testStaff.ID, conn)
+	defer service.RemoveConnection(ctx, testStaff.ID)
+
+	// Handlers and the escalation scheduler notify the same user at once
+	var wg sync.WaitGroup
+	for i := 1; i <= senders; i++ {
+		wg.Add(1)
+		go func(id uint) {
+			defer wg.Done()
+			service.SendWebSocketNotification(ctx, testStaff.ID, &models.Notification{ID: id, UserID: testStaff.ID, Title: "Escalated"})
+		}(uint(i))
+	}
+
+	client.SetReadDeadline(time.Now().Add(5 * time.Second))
+	received := map[uint]bool{}
+	for len(received) < senders {
+		var notification struct {
+			ID uint `json:"id"`
+		}
+		if err := client.ReadJSON(&notification); err != nil {
+			t.Fatalf("read after %d notifications: %v", len(received), err)
+		}
+		received[notification.ID] = true
+	}
+	wg.Wait()
+}
+
+// fakePusher records pushed titles by device token
+type fakePusher struct {
+	pushed map[string]string
+	data   map[string]map[string]string
+}
+
+func (p *fakePusher) Push(token, title, body string, data map[string]string) error {
+	p.pushed[token] = title
+	p.data[token] = data
+	return nil
+}
+
+func TestEmergencyAlertsOnDutyStaffAndDispatchers(t *testing.T) {
+	staff, dispatcher, supervisor := testStaff, testNeighbor, testOtherStaff
+	staff.FCMToken = "staff-phone"
+	dispatcher.ID, dispatcher.Role, dispatcher.FCMToken = 40, "dispatcher", "dispatcher-phone"
+	supervisor.ID, supervisor.Role, supervisor.FCMToken = 41, "supervisor", "supervisor-phone"
+	users := newFakeUsers(testAdmin, staff, dispatcher, supervisor, testResident)
+	roles := &RoleService{
+		Roles: &fakeRoles{rows: []models.Role{
+			customRole(1, "dispatcher", PermComplaintViewAll, PermComplaintAssign),
+			customRole(2, "supervisor", PermComplaintViewAll, PermAnalyticsView),
+		}},
+		Users: users,
+	}
+	pusher := &fakePusher{pushed: map[string]string{}, data: map[string]map[string]string{}}
+	notifications := &fakeNotifications{}
+	complaints := newFakeComplaints(models.Complaint{ID: 7, Title: "Gas leak", Status: "pending", SocietyID: testSociety,
+		ResidentID: testResident.ID, CategoryID: 1, IsEmergency: true})
+	societies := &fakeSocieties{rows: map[uint]*models.Society{testSociety: {ID: testSociety}}}
+	assignment := &AssignmentService{
+		Complaints: complaints,
+		Users:      users,
+		Societies:  societies,
+		Roles:      roles,
+		Staff:      &StaffService{Staff: newFakeStaff(), Complaints: complaints, Societies: societies},
+		Notifications: &NotificationService{
+			Notifications: notifications,
+			Users:         users,
+			Channels:      []NotificationChannel{PushChannel{Pusher: pusher}},
+		},
+	}
+
+	complaint, _ := complaints.Find(context.Background(), testSociety, 7)
+	picked, err := assignment.AutoAssign(context.Background(), complaint)
+	if err != nil {
+		t.Fatalf("AutoAssign: %v", err)
+	}
+	if picked == nil || picked.ID != staff.ID {
+		t.Errorf("assigned %v, want the on-duty staff member", picked)
+	}
+
+	assignment.Notifications.Wait()
+	alerted := map[uint]bool{}
+	for _, id := range notifications.recipients("emergency") {
+		alerted[id] = true
+	}
+	if len(alerted) != 3 || !alerted[staff.ID] || !alerted[testAdmin.ID] || !alerted[dispatcher.ID] {
+		t.Errorf("alerted %v, want the staff member, the admin and the dispatcher", notifications.recipients("emergency"))
+	}
+	if len(pusher.pushed) != 2 || pusher.pushed["staff-phone"] != "EMERGENCY Complaint" || pusher.pushed["dispatcher-phone"] == "" {
+		t.Errorf("pushed to %v, want the staff member's and the dispatcher's phones", pusher.pushed)
+	}
+	if data := pusher.data["staff-phone"]; data["type"] != "emergency" || data["complaint_id"] != "7" {
+		t.Errorf("push data = %v, want the emergency and its complaint", data)
+	}
+}
+
+// blockingChannel holds every send until release is closed
+type blockingChannel struct {
+	release chan struct{}
+}
+
+func (blockingChannel) Name() string { return "blocking" }
+
+func (c blockingChannel) Send(user *models.User, notification *models.Notification) error {
+	<-c.release
+	return nil
+}
+
+func TestNotifyEmergencyDoesNotWaitForChannels(t *testing.T) {
+	channel := blockingChannel{release: make(chan struct{})}
+	notifications := &fakeNotifications{}
+	service := &NotificationService{
+		Notifications: notifications,
+		Users:         newFakeUsers(testStaff),
+		Channels:      []NotificationChannel{channel},
+	}
+
+	returned := make(chan struct{})
+	go func() {
+		service.NotifyEmergency(context.Background(), []uint{testStaff.ID}, &models.Complaint{ID: 7, SocietyID: testSociety})
+		close(returned)
+	}()
+	select {
+	case <-returned:
+	case <-time.After(5 * time.Second):
+		t.Fatal("NotifyEmergency waited for a stalled channel")
+	}
+	if got := notifications.recipients("emergency"); len(got) != 1 {
+		t.Errorf("stored emergency notifications for %v, want the staff member before returning", got)
+	}
+
+	close(channel.release)
+	service.Wait()
+}
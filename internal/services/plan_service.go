@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+)
+
+// Features that plans switch on or off
+const (
+	FeatureSLA       = "sla"       // escalation policies and the background escalation run
+	FeatureAnalytics = "analytics" // analytics and location statistics
+	FeatureExports   = "exports"   // CSV, XLSX and PDF exports
+)
+
+// Seat limits that plans set
+const (
+	LimitResidents = "residents"
+	LimitStaff     = "staff"
+)
+
+// LegacyPlan applies to societies created before plans existed, so they keep every feature
+// until a super-admin assigns them a plan
+const LegacyPlan = "premium"
+
+// Plan is a subscription tier. A zero limit means unlimited.
+type Plan struct {
+	Name                string          `json:"name"`
+	MaxResidents        int64           `json:"max_residents"`
+	MaxStaff            int64           `json:"max_staff"`
+	AttachmentStorageMB int64           `json:"attachment_storage_mb"`
+	Features            map[string]bool `json:"features"`
+}
+
+// PlanNames lists the plans from smallest to largest
+var PlanNames = []string{"free", "standard", "premium"}
+
+// Plans are the tiers a society can be on
+var Plans = map[string]Plan{
+	"free": {
+		Name:                "free",
+		MaxResidents:        50,
+		MaxStaff:            5,
+		AttachmentStorageMB: 100,
+		Features:            map[string]bool{FeatureSLA: false, FeatureAnalytics: false, FeatureExports: false},
+	},
+	"standard": {
+		Name:                "standard",
+		MaxResidents:        500,
+		MaxStaff:            25,
+		AttachmentStorageMB: 2048,
+		Features:            map[string]bool{FeatureSLA: true, FeatureAnalytics: true, FeatureExports: false},
+	},
+	"premium": {
+		Name:                "premium",
+		AttachmentStorageMB: 20480,
+		Features:            map[string]bool{FeatureSLA: true, FeatureAnalytics: true, FeatureExports: true},
+	},
+}
+
+// FeatureError is returned when the society's plan does not include a feature
+type FeatureError struct {
+	Feature string
+	Plan    string
+}
+
+func (e *FeatureError) Error() string {
+	return fmt.Sprintf("the %s plan does not include %s; upgrade to use it", e.Plan, e.Feature)
+}
+
+// LimitError is returned when an action would take the society past one of its plan's limits
+type LimitError struct {
+	Limit   string
+	Plan    string
+	Max     int64
+	Current int64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("the %s plan allows %d %s and the society has %d; upgrade to add more", e.Plan, e.Max, e.Limit, e.Current)
+}
+
+// LimitUsage is how much of one limit a society uses. Max is 0 when unlimited.
+type LimitUsage struct {
+	Used int64 `json:"used"`
+	Max  int64 `json:"max"`
+}
+
+// PlanUsage is a society's plan with how close it is to each limit
+type PlanUsage struct {
+	Plan              Plan       `json:"plan"`
+	Residents         LimitUsage `json:"residents"`
+	Staff             LimitUsage `json:"staff"`
+	AttachmentStorage LimitUsage `json:"attachment_storage_mb"`
+}
+
+// PlanService enforces plan limits and feature flags per society
+type PlanService struct{}
+
+// ForSociety returns the plan the society is on
+func (ps *PlanService) ForSociety(ctx context.Context, societyID uint) (Plan, error) {
+	var society models.Society
+	if err := database.Session(ctx).Select("plan").First(&society, societyID).Error; err != nil {
+		return Plan{}, err
+	}
+	return planNamed(society.Plan), nil
+}
+
+// RequireFeature returns a FeatureError when the society's plan does not include feature
+func (ps *PlanService) RequireFeature(ctx context.Context, societyID uint, feature string) error {
+	plan, err := ps.ForSociety(ctx, societyID)
+	if err != nil {
+		return err
+	}
+	if !plan.Features[feature] {
+		return &FeatureError{Feature: feature, Plan: plan.Name}
+	}
+	return nil
+}
+
+// CheckSeats returns a LimitError when adding active accounts with role would exceed the plan.
+// Residents hold the user role; staff and custom roles share the staff seats. Admins are not limited.
+func (ps *PlanService) CheckSeats(ctx context.Context, societyID uint, role string, adding int64) error {
+	limit := SeatLimit(role)
+	if limit == "" || adding <= 0 {
+		return nil
+	}
+
+	plan, err := ps.ForSociety(ctx, societyID)
+	if err != nil {
+		return err
+	}
+	max := plan.MaxResidents
+	if limit == LimitStaff {
+		max = plan.MaxStaff
+	}
+	if max == 0 {
+		return nil
+	}
+
+	used, err := countSeats(ctx, societyID, limit)
+	if err != nil {
+		return err
+	}
+	if used+adding > max {
+		return &LimitError{Limit: limit, Plan: plan.Name, Max: max, Current: used}
+	}
+	return nil
+}
+
+// Usage returns the society's plan and its use of each limit
+func (ps *PlanService) Usage(ctx context.Context, societyID uint) (*PlanUsage, error) {
+	plan, err := ps.ForSociety(ctx, societyID)
+	if err != nil {
+		return nil, err
+	}
+
+	residents, err := countSeats(ctx, societyID, LimitResidents)
+	if err != nil {
+		return nil, err
+	}
+	staff, err := countSeats(ctx, societyID, LimitStaff)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PlanUsage{
+		Plan:      plan,
+		Residents: LimitUsage{Used: residents, Max: plan.MaxResidents},
+		Staff:     LimitUsage{Used: staff, Max: plan.MaxStaff},
+		// Complaints do not carry attachments yet, so nothing counts against storage
+		AttachmentStorage: LimitUsage{Used: 0, Max: plan.AttachmentStorageMB},
+	}, nil
+}
+
+// SeatLimit returns which seat limit an account with role counts against, or "" for none
+func SeatLimit(role string) string {
+	switch role {
+	case "admin", RoleSuperAdmin, "":
+		return ""
+	case "user":
+		return LimitResidents
+	}
+	return LimitStaff
+}
+
+// countSeats counts the society's active accounts against a seat limit
+func countSeats(ctx context.Context, societyID uint, limit string) (int64, error) {
+	query := database.Session(ctx).Model(&models.User{}).Where("society_id = ? AND deactivated_at IS NULL", societyID)
+	if limit == LimitResidents {
+		query = query.Where("role = ?", "user")
+	} else {
+		query = query.Where("role NOT IN ?", []string{"user", "admin"})
+	}
+
+	var count int64
+	err := query.Count(&count).Error
+	return count, err
+}
+
+func planNamed(name string) Plan {
+	if plan, ok := Plans[name]; ok {
+		return plan
+	}
+	return Plans[LegacyPlan]
+}
@@ -0,0 +1,422 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/utils"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+	"gorm.io/gorm"
+)
+
+// ImpersonationTTL is how long a support session opened by a super-admin lasts
+const ImpersonationTTL = time.Hour
+
+var (
+	ErrSocietyNotFound     = errors.New("society not found")
+	ErrSocietySuspended    = errors.New("society is suspended")
+	ErrSocietyNotSuspended = errors.New("society must be suspended before it can be deleted")
+	ErrSocietyActive       = errors.New("society is not suspended")
+	ErrUnknownPlan         = errors.New("plan must be one of free, standard or premium")
+	ErrNoSocietyAdmin      = errors.New("society has no active admin to impersonate")
+)
+
+// NewSociety describes a society to create, optionally with its first admin who is invited by email
+type NewSociety struct {
+	Name       string
+	Address    string
+	Plan       string
+	AdminName  string
+	AdminEmail string
+}
+
+// SocietyUsage summarises one society's size and activity for the platform dashboard
+type SocietyUsage struct {
+	SocietyID        uint       `json:"society_id"`
+	Name             string     `json:"name"`
+	Plan             string     `json:"plan"`
+	Suspended        bool       `json:"suspended"`
+	Residents        int64      `json:"residents"`
+	Staff            int64      `json:"staff"`
+	Admins           int64      `json:"admins"`
+	Complaints       int64      `json:"complaints"`
+	OpenComplaints   int64      `json:"open_complaints"`
+	ComplaintsLast30 int64      `json:"complaints_last_30_days"`
+	LastComplaintAt  *time.Time `json:"last_complaint_at,omitempty"`
+}
+
+// PlatformService lets super-admins manage societies across tenants. Platform routes hold no
+// tenant session, so its queries run on the platform login through database.Session, which
+// row-level security lets past every society.
+type PlatformService struct {
+	Invites *InviteService
+
+	// JWTSecret signs impersonation tokens
+	JWTSecret string
+}
+
+// IsSocietySuspended reports whether members of the society are locked out.
+// Platform accounts belong to no society and are never suspended.
+func IsSocietySuspended(societyID uint) (bool, error) {
+	if societyID == 0 {
+		return false, nil
+	}
+	var count int64
+	err := database.DB.Model(&models.Society{}).Where("id = ? AND suspended_at IS NOT NULL", societyID).Count(&count).Error
+	return count > 0, err
+}
+
+// EnsureSuperAdmin creates the platform super-admin configured through the environment
+// if it does not exist yet. An existing account with that email is left untouched.
+func EnsureSuperAdmin(email, password string) error {
+	email = normalizeEmail(email)
+	if email == "" || password == "" {
+		return nil
+	}
+
+	var existing models.User
+	err := database.DB.Where("LOWER(email) = ?", email).Limit(1).Find(&existing).Error
+	if err != nil {
+		return err
+	}
+	if existing.ID != 0 {
+		if existing.Role != RoleSuperAdmin {
+			slog.Warn("super-admin email belongs to a society account; not promoting it", "user_id", existing.ID)
+		}
+		return nil
+	}
+
+	return database.DB.Create(&models.User{
+		Name:     "Platform Admin",
+		Email:    email,
+		Password: utils.HashPassword(password),
+		Role:     RoleSuperAdmin,
+	}).Error
+}
+
+// List returns every society, newest first
+func (ps *PlatformService) List(ctx context.Context) ([]models.Society, error) {
+	societies := []models.Society{}
+	err := database.Session(ctx).Order("id DESC").Find(&societies).Error
+	return societies, err
+}
+
+// Find returns one society
+func (ps *PlatformService) Find(ctx context.Context, societyID uint) (*models.Society, error) {
+	var society models.Society
+	if err := database.Session(ctx).First(&society, societyID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSocietyNotFound
+		}
+		return nil, err
+	}
+	return &society, nil
+}
+
+// Create adds a society and, when an admin email is given, its first admin with an emailed invite
+func (ps *PlatformService) Create(ctx context.Context, actor *models.User, input NewSociety) (*models.Society, *models.User, error) {
+	society := models.Society{
+		Name:    strings.TrimSpace(input.Name),
+		Address: strings.TrimSpace(input.Address),
+		Plan:    input.Plan,
+	}
+	if society.Name == "" {
+		return nil, nil, errors.New("name is required")
+	}
+	if society.Plan == "" {
+		society.Plan = PlanNames[0]
+	}
+	if !isSocietyPlan(society.Plan) {
+		return nil, nil, ErrUnknownPlan
+	}
+
+	var admin *models.User
+	adminEmail := normalizeEmail(input.AdminEmail)
+	if adminEmail != "" {
+		if !strings.Contains(adminEmail, "@") {
+			return nil, nil, errors.New("admin_email must be a valid email")
+		}
+		var taken int64
+		if err := database.Session(ctx).Model(&models.User{}).Where("LOWER(email) = ?", adminEmail).Count(&taken).Error; err != nil {
+			return nil, nil, err
+		}
+		if taken > 0 {
+			return nil, nil, errors.New("admin_email is already registered")
+		}
+		name := strings.TrimSpace(input.AdminName)
+		if name == "" {
+			name = "Society Admin"
+		}
+		admin = &models.User{Name: name, Email: adminEmail, Role: "admin"}
+	}
+
+	var token string
+	err := database.Session(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&society).Error; err != nil {
+			return err
+		}
+		details := map[string]interface{}{"name": society.Name, "plan": society.Plan}
+
+		if admin != nil {
+			// The admin has no password until they accept the invite
+			admin.SocietyID = society.ID
+			if err := tx.Create(admin).Error; err != nil {
+				return err
+			}
+			var err error
+			if token, err = ps.Invites.Create(database.WithSession(ctx, tx), admin.ID); err != nil {
+				return err
+			}
+			details["admin_id"] = admin.ID
+		}
+
+		return recordAuditIn(tx, society.ID, actor, AuditSocietyCreated, "society", society.ID, details)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if admin != nil {
+		if err := ps.Invites.Send(admin, token); err != nil {
+			slog.ErrorContext(ctx, "failed to send invite", "user_id", admin.ID, "society_id", society.ID, "error", err)
+		}
+	}
+	return &society, admin, nil
+}
+
+// Suspend locks every member of the society out until it is resumed
+func (ps *PlatformService) Suspend(ctx context.Context, actor *models.User, societyID uint, reason string) (*models.Society, error) {
+	society, err := ps.Find(ctx, societyID)
+	if err != nil {
+		return nil, err
+	}
+	if society.IsSuspended() {
+		return nil, ErrSocietySuspended
+	}
+
+	now := time.Now()
+	err = database.Session(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(society).Update("suspended_at", &now).Error; err != nil {
+			return err
+		}
+		return recordAuditIn(tx, society.ID, actor, AuditSocietySuspended, "society", society.ID, map[string]interface{}{"reason": reason})
+	})
+	if err != nil {
+		return nil, err
+	}
+	society.SuspendedAt = &now
+	return society, nil
+}
+
+// Resume lifts a suspension
+func (ps *PlatformService) Resume(ctx context.Context, actor *models.User, societyID uint) (*models.Society, error) {
+	society, err := ps.Find(ctx, societyID)
+	if err != nil {
+		return nil, err
+	}
+	if !society.IsSuspended() {
+		return nil, ErrSocietyActive
+	}
+
+	err = database.Session(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(society).Update("suspended_at", nil).Error; err != nil {
+			return err
+		}
+		return recordAuditIn(tx, society.ID, actor, AuditSocietyResumed, "society", society.ID, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	society.SuspendedAt = nil
+	return society, nil
+}
+
+// SetPlan moves the society to another plan
+func (ps *PlatformService) SetPlan(ctx context.Context, actor *models.User, societyID uint, plan string) (*models.Society, error) {
+	if !isSocietyPlan(plan) {
+		return nil, ErrUnknownPlan
+	}
+	society, err := ps.Find(ctx, societyID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := society.Plan
+	err = database.Session(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(society).Update("plan", plan).Error; err != nil {
+			return err
+		}
+		return recordAuditIn(tx, society.ID, actor, AuditSocietyPlanChanged, "society", society.ID,
+			map[string]interface{}{"before": before, "after": plan})
+	})
+	if err != nil {
+		return nil, err
+	}
+	society.Plan = plan
+	return society, nil
+}
+
+// Delete removes a suspended society and all of its data. Audit entries are kept.
+func (ps *PlatformService) Delete(ctx context.Context, actor *models.User, societyID uint) error {
+	society, err := ps.Find(ctx, societyID)
+	if err != nil {
+		return err
+	}
+	if !society.IsSuspended() {
+		return ErrSocietyNotSuspended
+	}
+
+	return database.Session(ctx).Transaction(func(tx *gorm.DB) error {
+		users := tx.Model(&models.User{}).Select("id").Where("society_id = ?", society.ID)
+		complaints := tx.Model(&models.Complaint{}).Select("id").Where("society_id = ?", society.ID)
+
+		cleanups := []struct {
+			model interface{}
+			where string
+			arg   interface{}
+		}{
+			{&models.Feedback{}, "complaint_id IN (?)", complaints},
+			{&models.ComplaintReopen{}, "complaint_id IN (?)", complaints},
+			{&models.ComplaintEscalation{}, "complaint_id IN (?)", complaints},
+			{&models.Notification{}, "user_id IN (?)", users},
+			{&models.Invite{}, "user_id IN (?)", users},
+			{&models.StaffPoints{}, "staff_id IN (?)", users},
+			{&models.Complaint{}, "society_id = ?", society.ID},
+			{&models.StaffCategory{}, "society_id = ?", society.ID},
+			{&models.StaffProfile{}, "society_id = ?", society.ID},
+			{&models.StaffLeave{}, "society_id = ?", society.ID},
+			{&models.AssignmentCursor{}, "society_id = ?", society.ID},
+			{&models.EscalationPolicy{}, "society_id = ?", society.ID},
+			{&models.Location{}, "society_id = ?", society.ID},
+			{&models.Category{}, "society_id = ?", society.ID},
+			{&models.Role{}, "society_id = ?", society.ID},
+			{&models.User{}, "society_id = ?", society.ID},
+		}
+		for _, cleanup := range cleanups {
+			if err := tx.Where(cleanup.where, cleanup.arg).Delete(cleanup.model).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Delete(society).Error; err != nil {
+			return err
+		}
+		return recordAuditIn(tx, society.ID, actor, AuditSocietyDeleted, "society", society.ID,
+			map[string]interface{}{"name": society.Name})
+	})
+}
+
+// Usage returns size and activity figures for one society, or every society when societyID is 0
+func (ps *PlatformService) Usage(ctx context.Context, societyID uint) ([]SocietyUsage, error) {
+	db := database.Session(ctx)
+	societyQuery := db.Order("id")
+	userQuery := db.Model(&models.User{}).Where("deactivated_at IS NULL")
+	complaintQuery := db.Model(&models.Complaint{})
+	if societyID != 0 {
+		societyQuery = societyQuery.Where("id = ?", societyID)
+		userQuery = userQuery.Where("society_id = ?", societyID)
+		complaintQuery = complaintQuery.Where("society_id = ?", societyID)
+	}
+
+	var societies []models.Society
+	if err := societyQuery.Find(&societies).Error; err != nil {
+		return nil, err
+	}
+
+	var members []struct {
+		SocietyID uint
+		Role      string
+		Count     int64
+	}
+	if err := userQuery.Select("society_id, role, COUNT(*) AS count").Group("society_id, role").Scan(&members).Error; err != nil {
+		return nil, err
+	}
+
+	var activity []struct {
+		SocietyID uint
+		Total     int64
+		Open      int64
+		Recent    int64
+		LastAt    *time.Time
+	}
+	if err := complaintQuery.Select(
+		"society_id, COUNT(*) AS total, COUNT(*) FILTER (WHERE status IN ?) AS open, "+
+			"COUNT(*) FILTER (WHERE created_at >= ?) AS recent, MAX(created_at) AS last_at",
+		openStatuses, time.Now().AddDate(0, 0, -30),
+	).Group("society_id").Scan(&activity).Error; err != nil {
+		return nil, err
+	}
+
+	usage := make([]SocietyUsage, len(societies))
+	index := map[uint]*SocietyUsage{}
+	for i, s := range societies {
+		usage[i] = SocietyUsage{SocietyID: s.ID, Name: s.Name, Plan: s.Plan, Suspended: s.IsSuspended()}
+		index[s.ID] = &usage[i]
+	}
+	for _, m := range members {
+		u, ok := index[m.SocietyID]
+		if !ok {
+			continue
+		}
+		switch m.Role {
+		case "admin":
+			u.Admins += m.Count
+		case "staff":
+			u.Staff += m.Count
+		default:
+			u.Residents += m.Count
+		}
+	}
+	for _, a := range activity {
+		if u, ok := index[a.SocietyID]; ok {
+			u.Complaints = a.Total
+			u.OpenComplaints = a.Open
+			u.ComplaintsLast30 = a.Recent
+			u.LastComplaintAt = a.LastAt
+		}
+	}
+	return usage, nil
+}
+
+// Impersonate issues a short-lived token for the society's admin so a super-admin can help with support.
+// The session is recorded in the society's audit log, and every audited action taken with the token
+// carries the super-admin's ID.
+func (ps *PlatformService) Impersonate(ctx context.Context, actor *models.User, societyID uint, reason string) (string, *models.User, error) {
+	if strings.TrimSpace(reason) == "" {
+		return "", nil, errors.New("reason is required")
+	}
+	society, err := ps.Find(ctx, societyID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var admin models.User
+	if err := database.Session(ctx).Where("society_id = ? AND role = ? AND deactivated_at IS NULL", society.ID, "admin").
+		Order("id").First(&admin).Error; err != nil {
+		return "", nil, ErrNoSocietyAdmin
+	}
+
+	expiresAt := time.Now().Add(ImpersonationTTL)
+	err = recordAuditIn(database.Session(ctx), society.ID, actor, AuditImpersonation, "user", admin.ID, map[string]interface{}{
+		"reason":     reason,
+		"expires_at": expiresAt,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	admin.ImpersonatorID = &actor.ID
+	token, err := utils.GenerateJWT(&admin, ps.JWTSecret, ImpersonationTTL)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, &admin, nil
+}
+
+func isSocietyPlan(plan string) bool {
+	_, ok := Plans[plan]
+	return ok
+}
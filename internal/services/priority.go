@@ -0,0 +1,75 @@
+package services
+
+import (
+	"fmt"
+	"time"
+)
+
+// Complaint priority levels, lowest first
+const (
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
+
+// priorityRank orders priorities so listings can sort by urgency
+var priorityRank = map[string]int{
+	PriorityLow:    1,
+	PriorityMedium: 2,
+	PriorityHigh:   3,
+	PriorityUrgent: 4,
+}
+
+// defaultSLAHours is the resolution target for a medium priority complaint
+// when its category does not set SLAHours
+const defaultSLAHours = 48
+
+// slaFactor scales the category's SLA target by priority
+var slaFactor = map[string]float64{
+	PriorityLow:    2,
+	PriorityMedium: 1,
+	PriorityHigh:   0.5,
+	PriorityUrgent: 0.125,
+}
+
+// IsValidPriority reports whether p is a known priority level
+func IsValidPriority(p string) bool {
+	_, ok := priorityRank[p]
+	return ok
+}
+
+// ResolvePriority picks the priority for a new complaint: an emergency is always urgent,
+// otherwise the resident's proposal wins over the category default, falling back to medium
+func ResolvePriority(proposed, categoryDefault string, emergency bool) string {
+	switch {
+	case emergency:
+		return PriorityUrgent
+	case IsValidPriority(proposed):
+		return proposed
+	case IsValidPriority(categoryDefault):
+		return categoryDefault
+	}
+	return PriorityMedium
+}
+
+// SLADeadline returns when a complaint created at from should be resolved
+func SLADeadline(from time.Time, categorySLAHours int, priority string) time.Time {
+	hours := categorySLAHours
+	if hours <= 0 {
+		hours = defaultSLAHours
+	}
+
+	factor, ok := slaFactor[priority]
+	if !ok {
+		factor = 1
+	}
+
+	return from.Add(time.Duration(float64(hours) * factor * float64(time.Hour)))
+}
+
+// PriorityOrderSQL is an ORDER BY expression that sorts emergencies first, then by priority
+func PriorityOrderSQL(table string) string {
+	return fmt.Sprintf("%[1]s.is_emergency DESC, CASE %[1]s.priority WHEN '%[2]s' THEN 4 WHEN '%[3]s' THEN 3 WHEN '%[4]s' THEN 2 WHEN '%[5]s' THEN 1 ELSE 0 END DESC",
+		table, PriorityUrgent, PriorityHigh, PriorityMedium, PriorityLow)
+}
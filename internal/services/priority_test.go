@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+)
+
+func TestResolvePriority(t *testing.T) {
+	tests := []struct {
+		name            string
+		proposed        string
+		categoryDefault string
+		emergency       bool
+		want            string
+	}{
+		{"emergency overrides the proposal", PriorityLow, PriorityHigh, true, PriorityUrgent},
+		{"resident proposal wins", PriorityHigh, PriorityLow, false, PriorityHigh},
+		{"category default without a proposal", "", PriorityHigh, false, PriorityHigh},
+		{"unknown proposal falls back to the category", "critical", PriorityLow, false, PriorityLow},
+		{"medium without either", "", "", false, PriorityMedium},
+		{"medium when the category default is unknown", "", "asap", false, PriorityMedium},
+	}
+	for _, tt := range tests {
+		if got := ResolvePriority(tt.proposed, tt.categoryDefault, tt.emergency); got != tt.want {
+			t.Errorf("%s: ResolvePriority(%q, %q, %v) = %q, want %q", tt.name, tt.proposed, tt.categoryDefault, tt.emergency, got, tt.want)
+		}
+	}
+}
+
+func TestSLADeadline(t *testing.T) {
+	from := time.Date(2025, time.January, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		slaHours int
+		priority string
+		want     time.Duration
+	}{
+		{"medium keeps the category target", 24, PriorityMedium, 24 * time.Hour},
+		{"low doubles it", 24, PriorityLow, 48 * time.Hour},
+		{"high halves it", 24, PriorityHigh, 12 * time.Hour},
+		{"urgent is an eighth", 24, PriorityUrgent, 3 * time.Hour},
+		{"default target without a category SLA", 0, PriorityMedium, 48 * time.Hour},
+		{"default target scaled by priority", 0, PriorityUrgent, 6 * time.Hour},
+		{"unknown priority keeps the target", 10, "asap", 10 * time.Hour},
+		{"fractional hours", 1, PriorityUrgent, 7*time.Minute + 30*time.Second},
+	}
+	for _, tt := range tests {
+		if got := SLADeadline(from, tt.slaHours, tt.priority).Sub(from); got != tt.want {
+			t.Errorf("%s: deadline after %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSetPriorityRecalculatesTheDeadlineFromCreation(t *testing.T) {
+	created := time.Now().Add(-2 * time.Hour)
+	f := newComplaintFixture(models.Complaint{ID: 1, Status: "pending", SocietyID: testSociety, ResidentID: testResident.ID,
+		CategoryID: 1, Priority: PriorityMedium, CreatedAt: created})
+	f.service.Categories = newFakeCategories(models.Category{ID: 1, Name: "Plumbing", SocietyID: testSociety, SLAHours: 16})
+
+	complaint, _ := f.complaints.Find(context.Background(), testSociety, 1)
+	emergency := true
+	if err := f.service.SetPriority(context.Background(), complaint, PriorityHigh, &emergency); err != nil {
+		t.Fatalf("SetPriority: %v", err)
+	}
+	stored := f.complaints.rows[1]
+	if stored.Priority != PriorityHigh || !stored.IsEmergency {
+		t.Errorf("priority %q emergency %v, want high and an emergency", stored.Priority, stored.IsEmergency)
+	}
+	if stored.DueAt == nil || !stored.DueAt.Equal(created.Add(8*time.Hour)) {
+		t.Errorf("due at %v, want 8 hours after creation", stored.DueAt)
+	}
+}
+
+func TestPriorityOrderSQLRanksEveryPriority(t *testing.T) {
+	order := PriorityOrderSQL("c")
+	for p, rank := range priorityRank {
+		if want := fmt.Sprintf("WHEN '%s' THEN %d", p, rank); !strings.Contains(order, want) {
+			t.Errorf("order %q does not rank %s as %d", order, p, rank)
+		}
+	}
+}
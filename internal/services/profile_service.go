@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/utils"
+	"github.com/VinVorteX/flashtrack/pkg/database"
+	"gorm.io/gorm"
+)
+
+// MinPasswordLength applies to passwords chosen through the API
+const MinPasswordLength = 8
+
+var (
+	ErrWrongPassword            = errors.New("current password is incorrect")
+	ErrDeletionAlreadyRequested = errors.New("account deletion was already requested")
+
+	phonePattern    = regexp.MustCompile(`^\+?[0-9][0-9 -]{5,18}[0-9]$`)
+	languagePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z]{2,4})?$`)
+)
+
+// ProfileChanges holds the fields a user may edit on their own profile; nil fields are left alone
+type ProfileChanges struct {
+	Name      *string
+	Phone     *string
+	AvatarURL *string
+	Language  *string
+	Timezone  *string
+	FCMToken  *string
+}
+
+// ProfileService handles the signed-in user's own account
+type ProfileService struct {
+	Notifications *NotificationService
+}
+
+// Update validates and stores profile changes, returning the updated user
+func (ps *ProfileService) Update(ctx context.Context, user *models.User, changes ProfileChanges) (*models.User, error) {
+	updates := map[string]interface{}{}
+
+	if changes.Name != nil {
+		name := strings.TrimSpace(*changes.Name)
+		if name == "" {
+			return nil, errors.New("name cannot be empty")
+		}
+		updates["name"] = name
+	}
+	if changes.Phone != nil {
+		phone := strings.TrimSpace(*changes.Phone)
+		if phone != "" && !phonePattern.MatchString(phone) {
+			return nil, errors.New("phone must be 7 to 20 digits, optionally starting with +")
+		}
+		updates["phone"] = phone
+	}
+	if changes.AvatarURL != nil {
+		avatar := strings.TrimSpace(*changes.AvatarURL)
+		if avatar != "" {
+			u, err := url.ParseRequestURI(avatar)
+			if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				return nil, errors.New("avatar_url must be an http or https URL")
+			}
+		}
+		updates["avatar_url"] = avatar
+	}
+	if changes.Language != nil {
+		language := strings.TrimSpace(*changes.Language)
+		if language != "" && !languagePattern.MatchString(language) {
+			return nil, errors.New("language must be a code such as en or hi-IN")
+		}
+		updates["language"] = language
+	}
+	if changes.Timezone != nil {
+		timezone := strings.TrimSpace(*changes.Timezone)
+		if timezone != "" {
+			if _, err := time.LoadLocation(timezone); err != nil {
+				return nil, errors.New("timezone must be an IANA zone such as Asia/Kolkata")
+			}
+		}
+		updates["timezone"] = timezone
+	}
+	if changes.FCMToken != nil {
+		// An empty token unregisters the device from push notifications
+		updates["fcm_token"] = strings.TrimSpace(*changes.FCMToken)
+	}
+
+	if len(updates) > 0 {
+		if err := database.Session(ctx).Model(user).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	var updated models.User
+	if err := database.Session(ctx).First(&updated, user.ID).Error; err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// ChangePassword replaces the user's password after checking the current one
+func (ps *ProfileService) ChangePassword(ctx context.Context, user *models.User, current, next string) error {
+	if !utils.CheckPassword(user.Password, current) {
+		return ErrWrongPassword
+	}
+	if len(next) < MinPasswordLength {
+		return fmt.Errorf("new password must be at least %d characters", MinPasswordLength)
+	}
+	if current == next {
+		return errors.New("new password must differ from the current one")
+	}
+
+	return database.Session(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Update("password", utils.HashPassword(next)).Error; err != nil {
+			return err
+		}
+		return RecordAudit(tx, user, AuditPasswordChanged, "user", user.ID, nil)
+	})
+}
+
+// RequestDeletion records that the user wants their account removed and tells the society's admins,
+// who complete it through the user management API
+func (ps *ProfileService) RequestDeletion(ctx context.Context, user *models.User, password, reason string) error {
+	if !utils.CheckPassword(user.Password, password) {
+		return ErrWrongPassword
+	}
+	if user.DeletionRequestedAt != nil {
+		return ErrDeletionAlreadyRequested
+	}
+
+	now := time.Now()
+	err := database.Session(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Update("deletion_requested_at", &now).Error; err != nil {
+			return err
+		}
+		return RecordAudit(tx, user, AuditDeletionRequested, "user", user.ID, map[string]interface{}{"reason": reason})
+	})
+	if err != nil {
+		return err
+	}
+	user.DeletionRequestedAt = &now
+
+	if ps.Notifications == nil {
+		return nil
+	}
+	var admins []models.User
+	if err := database.Session(ctx).Select("id").
+		Where("society_id = ? AND role = ? AND deactivated_at IS NULL AND id <> ?", user.SocietyID, "admin", user.ID).
+		Find(&admins).Error; err != nil {
+		slog.ErrorContext(ctx, "failed to load admins for deletion request", "user_id", user.ID, "error", err)
+		return nil
+	}
+	message := fmt.Sprintf("%s (%s) asked for their account to be deleted", user.Name, user.Email)
+	if reason != "" {
+		message += ": " + reason
+	}
+	for _, admin := range admins {
+		if _, err := ps.Notifications.CreateNotification(ctx, admin.ID, "Account Deletion Requested", message, "deletion_request", nil); err != nil {
+			slog.ErrorContext(ctx, "failed to notify admin of deletion request", "admin_id", admin.ID, "error", err)
+		}
+	}
+	return nil
+}
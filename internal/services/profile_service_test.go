@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/utils"
+)
+
+// Every case here is refused before the profile is written
+func TestProfileUpdateValidates(t *testing.T) {
+	text := func(s string) *string { return &s }
+	tests := []struct {
+		name    string
+		changes ProfileChanges
+		wantErr string
+	}{
+		{"blank name", ProfileChanges{Name: text("   ")}, "name cannot be empty"},
+		{"phone with letters", ProfileChanges{Phone: text("call me")}, "phone must be"},
+		{"phone too short", ProfileChanges{Phone: text("12345")}, "phone must be"},
+		{"avatar without scheme", ProfileChanges{AvatarURL: text("example.com/me.png")}, "avatar_url must be"},
+		{"avatar with another scheme", ProfileChanges{AvatarURL: text("javascript://alert(1)")}, "avatar_url must be"},
+		{"language name", ProfileChanges{Language: text("English")}, "language must be"},
+		{"unknown timezone", ProfileChanges{Timezone: text("Mars/Olympus")}, "timezone must be"},
+		// A valid field does not let an invalid one through
+		{"valid name with a bad phone", ProfileChanges{Name: text("Resident"), Phone: text("x")}, "phone must be"},
+	}
+	service := &ProfileService{}
+	for _, tt := range tests {
+		_, err := service.Update(context.Background(), &testResident, tt.changes)
+		if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestProfileFieldPatterns(t *testing.T) {
+	for _, phone := range []string{"+91 98765 43210", "555-0100", "0123456"} {
+		if !phonePattern.MatchString(phone) {
+			t.Errorf("phone %q refused", phone)
+		}
+	}
+	for _, language := range []string{"en", "hi-IN", "zh-Hant", "fil"} {
+		if !languagePattern.MatchString(language) {
+			t.Errorf("language %q refused", language)
+		}
+	}
+}
+
+func TestPasswordChecksComeBeforeWriting(t *testing.T) {
+	user := testResident
+	user.Password = utils.HashPassword("current-secret")
+	service := &ProfileService{}
+	ctx := context.Background()
+
+	if err := service.ChangePassword(ctx, &user, "wrong-secret", "another-secret"); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("wrong current password: expected ErrWrongPassword, got %v", err)
+	}
+	if err := service.ChangePassword(ctx, &user, "current-secret", "short"); err == nil || !strings.Contains(err.Error(), "at least 8") {
+		t.Errorf("short password: got %v", err)
+	}
+	if err := service.ChangePassword(ctx, &user, "current-secret", "current-secret"); err == nil || !strings.Contains(err.Error(), "differ") {
+		t.Errorf("unchanged password: got %v", err)
+	}
+
+	requested := time.Now()
+	if err := service.RequestDeletion(ctx, &user, "wrong-secret", ""); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("deletion with a wrong password: expected ErrWrongPassword, got %v", err)
+	}
+	user.DeletionRequestedAt = &requested
+	if err := service.RequestDeletion(ctx, &user, "current-secret", ""); !errors.Is(err, ErrDeletionAlreadyRequested) {
+		t.Errorf("second deletion request: expected ErrDeletionAlreadyRequested, got %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	fcmEndpoint = "https://fcm.googleapis.com"
+	fcmScope    = "https://www.googleapis.com/auth/firebase.messaging"
+)
+
+// Pusher sends a push notification to one device
+type Pusher interface {
+	Push(token, title, body string, data map[string]string) error
+}
+
+// NewPusher returns an FCM pusher when a service account key is configured and a logging pusher otherwise
+func NewPusher(cfg config.PushConfig) Pusher {
+	if cfg.FCMCredentialsFile == "" {
+		return LogPusher{}
+	}
+	return &FCMPusher{CredentialsFile: cfg.FCMCredentialsFile}
+}
+
+// FCMPusher sends through the Firebase Cloud Messaging HTTP v1 API, signed in as the service
+// account in CredentialsFile. The key is read on the first push and the access token is reused
+// until shortly before it expires.
+type FCMPusher struct {
+	CredentialsFile string
+	// Client defaults to one with a 10 second timeout
+	Client *http.Client
+
+	// endpoint replaces the FCM API in tests
+	endpoint string
+
+	mu      sync.Mutex
+	account *serviceAccount
+	token   string
+	expiry  time.Time
+}
+
+// serviceAccount is the part of a Google service account key FCM needs
+type serviceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// Push sends one notification with high priority, so it is shown even when the app is asleep
+func (p *FCMPusher) Push(token, title, body string, data map[string]string) error {
+	accessToken, projectID, err := p.accessToken()
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"message": map[string]interface{}{
+			"token":        token,
+			"notification": map[string]string{"title": title, "body": body},
+			"data":         data,
+			"android":      map[string]string{"priority": "high"},
+			"apns":         map[string]interface{}{"headers": map[string]string{"apns-priority": "10"}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	endpoint := p.endpoint
+	if endpoint == "" {
+		endpoint = fcmEndpoint
+	}
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/v1/projects/%s/messages:send", endpoint, url.PathEscape(projectID)), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("fcm send failed: %s: %s", resp.Status, detail)
+	}
+	return nil
+}
+
+// accessToken returns a valid OAuth access token for the service account and its project,
+// exchanging a freshly signed assertion for a new token when the cached one is about to expire
+func (p *FCMPusher) accessToken() (string, string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.account == nil {
+		account, err := readServiceAccount(p.CredentialsFile)
+		if err != nil {
+			return "", "", err
+		}
+		p.account = account
+	}
+	if p.token != "" && time.Now().Before(p.expiry.Add(-time.Minute)) {
+		return p.token, p.account.ProjectID, nil
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(p.account.PrivateKey))
+	if err != nil {
+		return "", "", fmt.Errorf("invalid private key in %s: %w", p.CredentialsFile, err)
+	}
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   p.account.ClientEmail,
+		"scope": fcmScope,
+		"aud":   p.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(key)
+	if err != nil {
+		return "", "", err
+	}
+
+	resp, err := p.client().PostForm(p.account.TokenURI, url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	})
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", "", fmt.Errorf("fcm token exchange failed: %s: %s", resp.Status, detail)
+	}
+
+	var grant struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&grant); err != nil {
+		return "", "", fmt.Errorf("fcm token exchange failed: %w", err)
+	}
+	p.token = grant.AccessToken
+	p.expiry = now.Add(time.Duration(grant.ExpiresIn) * time.Second)
+	return p.token, p.account.ProjectID, nil
+}
+
+func (p *FCMPusher) client() *http.Client {
+	if p.Client != nil {
+		return p.Client
+	}
+	return &http.Client{Timeout: 10 * time.Second}
+}
+
+// readServiceAccount loads and checks a service account key file
+func readServiceAccount(path string) (*serviceAccount, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var account serviceAccount
+	if err := json.Unmarshal(raw, &account); err != nil {
+		return nil, fmt.Errorf("invalid service account key %s: %w", path, err)
+	}
+	if account.ProjectID == "" || account.ClientEmail == "" || account.PrivateKey == "" || account.TokenURI == "" {
+		return nil, errors.New("service account key " + path + " needs project_id, client_email, private_key and token_uri")
+	}
+	return &account, nil
+}
+
+// LogPusher writes push notifications to the server log, for development without Firebase
+type LogPusher struct{}
+
+// Push logs the notification instead of sending it. Device tokens identify a user's phone, so
+// they are left out.
+func (LogPusher) Push(token, title, body string, data map[string]string) error {
+	slog.Info("push notification not sent, no FCM credentials configured", "title", title)
+	return nil
+}
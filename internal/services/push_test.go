@@ -0,0 +1,82 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFCMPusherSignsInOnceAndSends(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exchanges int
+	var sent []map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		exchanges++
+		if r.FormValue("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" || r.FormValue("assertion") == "" {
+			http.Error(w, "bad grant", http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "access-1", "expires_in": 3600})
+	})
+	mux.HandleFunc("/v1/projects/flashtrack-test/messages:send", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-1" {
+			http.Error(w, "unauthenticated", http.StatusUnauthorized)
+			return
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		sent = append(sent, body["message"].(map[string]interface{}))
+		w.Write([]byte(`{"name":"projects/flashtrack-test/messages/1"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	credentials, _ := json.Marshal(map[string]string{
+		"project_id":   "flashtrack-test",
+		"client_email": "push@flashtrack-test.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		"token_uri":    server.URL + "/token",
+	})
+	path := filepath.Join(t.TempDir(), "firebase.json")
+	if err := os.WriteFile(path, credentials, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	pusher := &FCMPusher{CredentialsFile: path, endpoint: server.URL}
+	for _, token := range []string{"phone-1", "phone-2"} {
+		if err := pusher.Push(token, "EMERGENCY Complaint", "Gas leak", map[string]string{"complaint_id": "7"}); err != nil {
+			t.Fatalf("push to %s: %v", token, err)
+		}
+	}
+
+	if exchanges != 1 {
+		t.Errorf("signed in %d times, want the access token reused", exchanges)
+	}
+	if len(sent) != 2 || sent[0]["token"] != "phone-1" || sent[1]["token"] != "phone-2" {
+		t.Fatalf("sent %v, want one message per device", sent)
+	}
+	if data := sent[0]["data"].(map[string]interface{}); data["complaint_id"] != "7" {
+		t.Errorf("data = %v, want the complaint ID", data)
+	}
+}
+
+func TestFCMPusherReportsABadKeyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "firebase.json")
+	os.WriteFile(path, []byte(`{"project_id":"flashtrack-test"}`), 0o600)
+
+	if err := (&FCMPusher{CredentialsFile: path}).Push("phone", "title", "body", nil); err == nil {
+		t.Error("expected an incomplete service account key to fail the push")
+	}
+}
@@ -0,0 +1,381 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/repository"
+)
+
+// Permissions checked by the API
+const (
+	PermComplaintCreate       = "complaint.create"
+	PermComplaintEditOwn      = "complaint.edit_own"
+	PermComplaintViewOwn      = "complaint.view_own"
+	PermComplaintViewAssigned = "complaint.view_assigned"
+	PermComplaintViewAll      = "complaint.view_all"
+	PermComplaintAssign       = "complaint.assign"
+	PermComplaintResolve      = "complaint.resolve"
+	PermComplaintMerge        = "complaint.merge"
+	PermComplaintPrioritize   = "complaint.prioritize"
+	PermFeedbackSubmit        = "feedback.submit"
+	PermFeedbackView          = "feedback.view"
+	PermPointsView            = "points.view"
+	PermStatsView             = "stats.view"
+	PermStaffManage           = "staff.manage"
+	PermSettingsManage        = "settings.manage"
+	PermCategoryManage        = "category.manage"
+	PermLocationManage        = "location.manage"
+	PermEscalationManage      = "escalation.manage"
+	PermAnalyticsView         = "analytics.view"
+	PermReportExport          = "report.export"
+	PermUserManage            = "user.manage"
+	PermAuditView             = "audit.view"
+	PermRoleManage            = "role.manage"
+)
+
+// RoleSuperAdmin is the platform operator role. It belongs to no society and only grants
+// PermPlatformManage, so it cannot be created as a custom role or assigned by society admins.
+const (
+	RoleSuperAdmin     = "superadmin"
+	PermPlatformManage = "platform.manage"
+)
+
+// PermissionInfo describes a permission for the role editor
+type PermissionInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// AllPermissions is the catalogue of permissions that roles can grant
+var AllPermissions = []PermissionInfo{
+	{PermComplaintCreate, "File new complaints"},
+	{PermComplaintEditOwn, "Edit, withdraw and reopen own complaints"},
+	{PermComplaintViewOwn, "See own complaints"},
+	{PermComplaintViewAssigned, "See complaints assigned to them"},
+	{PermComplaintViewAll, "See every complaint in the society"},
+	{PermComplaintAssign, "Assign complaints to staff"},
+	{PermComplaintResolve, "Resolve complaints assigned to them"},
+	{PermComplaintMerge, "Merge duplicate complaints"},
+	{PermComplaintPrioritize, "Change complaint priority and emergency flag"},
+	{PermFeedbackSubmit, "Rate resolved complaints"},
+	{PermFeedbackView, "Read all feedback"},
+	{PermPointsView, "See own staff points"},
+	{PermStatsView, "See society head counts"},
+	{PermStaffManage, "Manage staff skills, schedules and leave"},
+	{PermSettingsManage, "Change society settings"},
+	{PermCategoryManage, "Manage and import categories"},
+	{PermLocationManage, "Manage buildings, floors, units and common areas"},
+	{PermEscalationManage, "Manage escalation policies and view escalations"},
+	{PermAnalyticsView, "View analytics and location statistics"},
+	{PermReportExport, "Export feedback and staff performance reports"},
+	{PermUserManage, "Manage, import and deactivate accounts"},
+	{PermAuditView, "Read the audit log"},
+	{PermRoleManage, "Create and edit custom roles"},
+}
+
+// builtinRoles are available in every society and cannot be edited
+var builtinRoles = map[string][]string{
+	"user":  {PermComplaintCreate, PermComplaintEditOwn, PermComplaintViewOwn, PermFeedbackSubmit},
+	"staff": {PermComplaintViewAssigned, PermComplaintResolve, PermPointsView, PermStatsView},
+	"admin": allPermissionNames(),
+}
+
+var (
+	ErrRoleNotFound      = errors.New("role not found in this society")
+	ErrRoleInUse         = errors.New("role is still assigned to users")
+	ErrBuiltinRole       = errors.New("built-in roles cannot be created, changed or deleted")
+	ErrUnknownPermission = errors.New("unknown permission")
+)
+
+func allPermissionNames() []string {
+	names := make([]string, len(AllPermissions))
+	for i, p := range AllPermissions {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// PermissionSet is the set of permissions a user holds
+type PermissionSet map[string]bool
+
+// Has reports whether the set grants every one of perms
+func (ps PermissionSet) Has(perms ...string) bool {
+	for _, p := range perms {
+		if !ps[p] {
+			return false
+		}
+	}
+	return true
+}
+
+// IsBuiltinRole reports whether name is one of the roles defined in code
+func IsBuiltinRole(name string) bool {
+	_, ok := builtinRoles[name]
+	return ok
+}
+
+// RoleInfo is a built-in or custom role with its permissions
+type RoleInfo struct {
+	ID          uint     `json:"id,omitempty"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	BuiltIn     bool     `json:"built_in"`
+	Users       int64    `json:"users"`
+}
+
+// RoleService resolves roles to permissions and manages custom roles
+type RoleService struct {
+	Roles repository.RoleRepository
+	Users repository.UserRepository
+}
+
+// Permissions returns what the user's role allows. Unknown roles grant nothing.
+func (rs *RoleService) Permissions(ctx context.Context, user *models.User) (PermissionSet, error) {
+	if user.Role == RoleSuperAdmin {
+		return newPermissionSet([]string{PermPlatformManage}), nil
+	}
+	if perms, ok := builtinRoles[user.Role]; ok {
+		return newPermissionSet(perms), nil
+	}
+
+	role, err := rs.Roles.Find(ctx, user.SocietyID, user.Role)
+	if errors.Is(err, repository.ErrNotFound) {
+		return PermissionSet{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newPermissionSet(splitPermissions(role.Permissions)), nil
+}
+
+// Exists reports whether name is a built-in role or a custom role of the society
+func (rs *RoleService) Exists(ctx context.Context, societyID uint, name string) (bool, error) {
+	if IsBuiltinRole(name) {
+		return true, nil
+	}
+	_, err := rs.Roles.Find(ctx, societyID, name)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// worksComplaints reports whether holders of a role with these permissions are staff who can be
+// assigned complaints. The built-in admin role holds every permission but triages the queue
+// rather than working it, so it never counts.
+func worksComplaints(role string, perms PermissionSet) bool {
+	return role != "admin" && perms.Has(PermComplaintViewAssigned, PermComplaintResolve)
+}
+
+// IsStaffRole reports whether holders of the role can be assigned complaints
+func (rs *RoleService) IsStaffRole(ctx context.Context, societyID uint, role string) (bool, error) {
+	perms, err := rs.Permissions(ctx, &models.User{SocietyID: societyID, Role: role})
+	if err != nil {
+		return false, err
+	}
+	return worksComplaints(role, perms), nil
+}
+
+// StaffRoles returns the built-in and custom roles of the society whose holders can be assigned complaints
+func (rs *RoleService) StaffRoles(ctx context.Context, societyID uint) ([]string, error) {
+	return rs.rolesWhere(ctx, societyID, worksComplaints)
+}
+
+// RolesWith returns the built-in and custom roles of the society that grant every one of perms
+func (rs *RoleService) RolesWith(ctx context.Context, societyID uint, perms ...string) ([]string, error) {
+	return rs.rolesWhere(ctx, societyID, func(role string, set PermissionSet) bool {
+		return set.Has(perms...)
+	})
+}
+
+// rolesWhere returns the sorted names of the built-in and custom roles of the society that match
+func (rs *RoleService) rolesWhere(ctx context.Context, societyID uint, match func(role string, perms PermissionSet) bool) ([]string, error) {
+	custom, err := rs.Roles.List(ctx, societyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name, perms := range builtinRoles {
+		if match(name, newPermissionSet(perms)) {
+			names = append(names, name)
+		}
+	}
+	for _, role := range custom {
+		if match(role.Name, newPermissionSet(splitPermissions(role.Permissions))) {
+			names = append(names, role.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Lookup loads the society's custom roles once and returns a resolver reporting whether a role
+// exists and whether its holders can be assigned complaints, for checking many names at a time
+func (rs *RoleService) Lookup(ctx context.Context, societyID uint) (func(name string) (exists, staff bool), error) {
+	custom, err := rs.Roles.List(ctx, societyID)
+	if err != nil {
+		return nil, err
+	}
+
+	perms := make(map[string]PermissionSet, len(builtinRoles)+len(custom))
+	for name, p := range builtinRoles {
+		perms[name] = newPermissionSet(p)
+	}
+	for _, role := range custom {
+		perms[role.Name] = newPermissionSet(splitPermissions(role.Permissions))
+	}
+
+	return func(name string) (bool, bool) {
+		set, ok := perms[name]
+		return ok, ok && worksComplaints(name, set)
+	}, nil
+}
+
+// List returns the built-in roles followed by the society's custom roles, with user counts
+func (rs *RoleService) List(ctx context.Context, societyID uint) ([]RoleInfo, error) {
+	custom, err := rs.Roles.List(ctx, societyID)
+	if err != nil {
+		return nil, err
+	}
+	users, err := rs.Users.CountByRole(ctx, societyID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := []RoleInfo{}
+	for _, name := range []string{"admin", "staff", "user"} {
+		roles = append(roles, RoleInfo{
+			Name:        name,
+			Description: "Built-in " + name + " role",
+			Permissions: builtinRoles[name],
+			BuiltIn:     true,
+			Users:       users[name],
+		})
+	}
+	for _, role := range custom {
+		roles = append(roles, RoleInfo{
+			ID:          role.ID,
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: splitPermissions(role.Permissions),
+			Users:       users[role.Name],
+		})
+	}
+	return roles, nil
+}
+
+// Create adds a custom role to the society
+func (rs *RoleService) Create(ctx context.Context, societyID uint, name, description string, permissions []string) (*models.Role, error) {
+	name = strings.TrimSpace(strings.ToLower(name))
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	if IsBuiltinRole(name) || name == RoleSuperAdmin {
+		return nil, ErrBuiltinRole
+	}
+	if exists, err := rs.Exists(ctx, societyID, name); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, errors.New("a role with this name already exists")
+	}
+
+	joined, err := joinPermissions(permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := models.Role{SocietyID: societyID, Name: name, Description: description, Permissions: joined}
+	if err := rs.Roles.Create(ctx, &role); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// Update changes a custom role's description and permissions. Names are fixed because users refer to them.
+func (rs *RoleService) Update(ctx context.Context, societyID, roleID uint, description *string, permissions []string) (*models.Role, error) {
+	role, err := rs.Roles.FindByID(ctx, societyID, roleID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrRoleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if description != nil {
+		role.Description = *description
+	}
+	if permissions != nil {
+		joined, err := joinPermissions(permissions)
+		if err != nil {
+			return nil, err
+		}
+		role.Permissions = joined
+	}
+
+	if err := rs.Roles.Save(ctx, role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// Delete removes a custom role nobody holds any more
+func (rs *RoleService) Delete(ctx context.Context, societyID, roleID uint) error {
+	role, err := rs.Roles.FindByID(ctx, societyID, roleID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrRoleNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	holders, err := rs.Users.CountByRole(ctx, societyID)
+	if err != nil {
+		return err
+	}
+	if holders[role.Name] > 0 {
+		return ErrRoleInUse
+	}
+
+	return rs.Roles.Delete(ctx, role)
+}
+
+func newPermissionSet(perms []string) PermissionSet {
+	set := make(PermissionSet, len(perms))
+	for _, p := range perms {
+		set[p] = true
+	}
+	return set
+}
+
+// joinPermissions validates permission names and stores them sorted and de-duplicated
+func joinPermissions(perms []string) (string, error) {
+	known := newPermissionSet(allPermissionNames())
+	set := PermissionSet{}
+	for _, p := range perms {
+		p = strings.TrimSpace(p)
+		if !known[p] {
+			return "", errors.New(ErrUnknownPermission.Error() + ": " + p)
+		}
+		set[p] = true
+	}
+
+	names := make([]string, 0, len(set))
+	for p := range set {
+		names = append(names, p)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ","), nil
+}
+
+func splitPermissions(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
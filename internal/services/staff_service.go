@@ -0,0 +1,355 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/repository"
+)
+
+const shiftTimeLayout = "15:04"
+
+var ErrLeaveNotFound = errors.New("leave not found")
+
+// StaffAvailability describes whether a staff member can take new work at a point in time
+type StaffAvailability struct {
+	OnShift        bool  `json:"on_shift"`
+	OnLeave        bool  `json:"on_leave"`
+	OpenComplaints int64 `json:"open_complaints"`
+	MaxConcurrent  int   `json:"max_concurrent"`
+	AtCapacity     bool  `json:"at_capacity"`
+}
+
+// Available reports whether the staff member is on duty and below capacity
+func (a StaffAvailability) Available() bool {
+	return a.OnShift && !a.OnLeave && !a.AtCapacity
+}
+
+// Reason explains why the staff member is unavailable, or returns an empty string
+func (a StaffAvailability) Reason() string {
+	switch {
+	case a.OnLeave:
+		return "staff member is on leave"
+	case !a.OnShift:
+		return "staff member is off shift"
+	case a.AtCapacity:
+		return fmt.Sprintf("staff member is at capacity (%d/%d open complaints)", a.OpenComplaints, a.MaxConcurrent)
+	}
+	return ""
+}
+
+// StaffService manages staff schedules, skills and availability
+type StaffService struct {
+	Staff      repository.StaffRepository
+	Users      repository.UserRepository
+	Complaints repository.ComplaintRepository
+	Categories repository.CategoryRepository
+	Societies  repository.SocietyRepository
+	Roles      *RoleService
+}
+
+// Members returns the society's active staff members ordered by ID, optionally only those
+// skilled in the category
+func (ss *StaffService) Members(ctx context.Context, societyID uint, categoryID *uint) ([]models.User, error) {
+	roles, err := ss.Roles.StaffRoles(ctx, societyID)
+	if err != nil {
+		return nil, err
+	}
+	staff, err := ss.Users.ActiveByRoles(ctx, societyID, roles)
+	if err != nil || categoryID == nil {
+		return staff, err
+	}
+
+	skilledIDs, err := ss.SkilledStaffIDs(ctx, societyID, *categoryID)
+	if err != nil {
+		return nil, err
+	}
+	skilled := make(map[uint]bool, len(skilledIDs))
+	for _, id := range skilledIDs {
+		skilled[id] = true
+	}
+	matching := []models.User{}
+	for _, s := range staff {
+		if skilled[s.ID] {
+			matching = append(matching, s)
+		}
+	}
+	return matching, nil
+}
+
+// FindMember loads a staff member of the society, active or not
+func (ss *StaffService) FindMember(ctx context.Context, societyID, staffID uint) (*models.User, error) {
+	staff, err := ss.Users.FindInSociety(ctx, societyID, staffID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrStaffNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if isStaff, err := ss.Roles.IsStaffRole(ctx, societyID, staff.Role); err != nil {
+		return nil, err
+	} else if !isStaff {
+		return nil, ErrStaffNotFound
+	}
+	return staff, nil
+}
+
+// Headcount counts the society's staff members, whichever role lets them work complaints, and residents
+func (ss *StaffService) Headcount(ctx context.Context, societyID uint) (staff, residents int64, err error) {
+	roles, err := ss.Roles.StaffRoles(ctx, societyID)
+	if err != nil {
+		return 0, 0, err
+	}
+	counts, err := ss.Users.CountByRole(ctx, societyID)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, role := range roles {
+		staff += counts[role]
+	}
+	return staff, counts["user"], nil
+}
+
+// GetProfile returns the staff member's profile, or an empty profile if none has been saved
+func (ss *StaffService) GetProfile(ctx context.Context, staffID uint) (models.StaffProfile, error) {
+	profiles, err := ss.Staff.Profiles(ctx, []uint{staffID})
+	if err != nil || len(profiles) == 0 {
+		return models.StaffProfile{StaffID: staffID}, err
+	}
+	return profiles[0], nil
+}
+
+// SaveProfile validates and stores a staff member's schedule and capacity
+func (ss *StaffService) SaveProfile(ctx context.Context, profile *models.StaffProfile) error {
+	if (profile.ShiftStart == "") != (profile.ShiftEnd == "") {
+		return errors.New("shift_start and shift_end must be set together")
+	}
+	for _, value := range []string{profile.ShiftStart, profile.ShiftEnd} {
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse(shiftTimeLayout, value); err != nil {
+			return errors.New("shift times must use HH:MM format")
+		}
+	}
+	if _, err := parseWorkingDays(profile.WorkingDays); err != nil {
+		return err
+	}
+	if profile.MaxConcurrent < 0 {
+		return errors.New("max_concurrent cannot be negative")
+	}
+
+	return ss.Staff.SaveProfile(ctx, profile)
+}
+
+// GetCategoryIDs returns the categories each of the given staff members is skilled in
+func (ss *StaffService) GetCategoryIDs(ctx context.Context, staffIDs []uint) (map[uint][]uint, error) {
+	mappings, err := ss.Staff.Skills(ctx, staffIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	skills := make(map[uint][]uint, len(staffIDs))
+	for _, m := range mappings {
+		skills[m.StaffID] = append(skills[m.StaffID], m.CategoryID)
+	}
+	return skills, nil
+}
+
+// SkilledStaffIDs returns the society's staff members skilled in the category
+func (ss *StaffService) SkilledStaffIDs(ctx context.Context, societyID, categoryID uint) ([]uint, error) {
+	return ss.Staff.SkilledStaffIDs(ctx, societyID, categoryID)
+}
+
+// Availability computes shift, leave and workload status for the given staff members at time at.
+// Shifts and working days are read on the society's clock.
+func (ss *StaffService) Availability(ctx context.Context, societyID uint, staffIDs []uint, at time.Time) (map[uint]StaffAvailability, error) {
+	result := make(map[uint]StaffAvailability, len(staffIDs))
+	if len(staffIDs) == 0 {
+		return result, nil
+	}
+
+	society, err := ss.Societies.Find(ctx, societyID)
+	if err != nil {
+		return nil, err
+	}
+	at = at.In(society.Location())
+
+	profiles, err := ss.Staff.Profiles(ctx, staffIDs)
+	if err != nil {
+		return nil, err
+	}
+	profileByStaff := make(map[uint]models.StaffProfile, len(profiles))
+	for _, p := range profiles {
+		profileByStaff[p.StaffID] = p
+	}
+
+	// Leave dates are stored as server-local midnights, so the society's calendar day is looked up
+	// the same way; leave covers whole days, so covering the day's start covers at
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.Local)
+	leaves, err := ss.Staff.LeavesAt(ctx, societyID, staffIDs, day, day)
+	if err != nil {
+		return nil, err
+	}
+	holiday := false
+	onLeave := make(map[uint]bool)
+	for _, leave := range leaves {
+		if leave.StaffID == nil {
+			holiday = true
+		} else {
+			onLeave[*leave.StaffID] = true
+		}
+	}
+
+	workloads, err := ss.Complaints.Workloads(ctx, staffIDs, openStatuses)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range staffIDs {
+		profile := profileByStaff[id]
+		a := StaffAvailability{
+			OnShift:        isOnShift(profile, at),
+			OnLeave:        holiday || onLeave[id],
+			OpenComplaints: workloads[id],
+			MaxConcurrent:  profile.MaxConcurrent,
+		}
+		a.AtCapacity = a.MaxConcurrent > 0 && a.OpenComplaints >= int64(a.MaxConcurrent)
+		result[id] = a
+	}
+
+	return result, nil
+}
+
+// isOnShift reports whether at falls within the profile's shift on one of its working days.
+// A night shift spanning midnight belongs to the day it starts on, so its early hours are
+// checked against the previous day.
+func isOnShift(profile models.StaffProfile, at time.Time) bool {
+	days, _ := parseWorkingDays(profile.WorkingDays)
+	worksOn := func(day time.Weekday) bool {
+		return len(days) == 0 || days[day]
+	}
+
+	if profile.ShiftStart == "" || profile.ShiftEnd == "" {
+		return worksOn(at.Weekday())
+	}
+
+	start, err1 := time.Parse(shiftTimeLayout, profile.ShiftStart)
+	end, err2 := time.Parse(shiftTimeLayout, profile.ShiftEnd)
+	if err1 != nil || err2 != nil {
+		return worksOn(at.Weekday())
+	}
+
+	now := at.Hour()*60 + at.Minute()
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+
+	if from <= to {
+		return worksOn(at.Weekday()) && now >= from && now < to
+	}
+	// Night shift spanning midnight
+	if now >= from {
+		return worksOn(at.Weekday())
+	}
+	return now < to && worksOn((at.Weekday()+6)%7)
+}
+
+// parseWorkingDays parses a comma separated list of weekday numbers
+func parseWorkingDays(value string) (map[time.Weekday]bool, error) {
+	days := make(map[time.Weekday]bool)
+	if strings.TrimSpace(value) == "" {
+		return days, nil
+	}
+
+	for _, part := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 0 || n > 6 {
+			return nil, errors.New("working_days must contain weekday numbers 0-6")
+		}
+		days[time.Weekday(n)] = true
+	}
+	return days, nil
+}
+
+// FormatWorkingDays joins weekday numbers into the stored comma separated form
+func FormatWorkingDays(days []int) string {
+	parts := make([]string, len(days))
+	for i, d := range days {
+		parts[i] = strconv.Itoa(d)
+	}
+	return strings.Join(parts, ",")
+}
+
+// FilterAvailable keeps only the staff members who are available at time at
+func (ss *StaffService) FilterAvailable(ctx context.Context, societyID uint, staff []models.User, at time.Time) ([]models.User, error) {
+	availability, err := ss.Availability(ctx, societyID, staffIDs(staff), at)
+	if err != nil {
+		return nil, err
+	}
+
+	var available []models.User
+	for _, s := range staff {
+		if availability[s.ID].Available() {
+			available = append(available, s)
+		}
+	}
+	return available, nil
+}
+
+// FilterOnDuty keeps only the staff members who are on shift and not on leave at time at,
+// regardless of how many complaints they already have
+func (ss *StaffService) FilterOnDuty(ctx context.Context, societyID uint, staff []models.User, at time.Time) ([]models.User, error) {
+	availability, err := ss.Availability(ctx, societyID, staffIDs(staff), at)
+	if err != nil {
+		return nil, err
+	}
+
+	var onDuty []models.User
+	for _, s := range staff {
+		if a := availability[s.ID]; a.OnShift && !a.OnLeave {
+			onDuty = append(onDuty, s)
+		}
+	}
+	return onDuty, nil
+}
+
+// SetCategories replaces the categories a staff member is skilled in
+func (ss *StaffService) SetCategories(ctx context.Context, societyID, staffID uint, categoryIDs []uint) error {
+	categoryIDs = uniqueIDs(categoryIDs)
+	if len(categoryIDs) > 0 {
+		count, err := ss.Categories.CountAvailable(ctx, societyID, categoryIDs)
+		if err != nil {
+			return err
+		}
+		if int(count) != len(categoryIDs) {
+			return ErrCategoryNotInSociety
+		}
+	}
+
+	return ss.Staff.SetSkills(ctx, societyID, staffID, categoryIDs)
+}
+
+// Leaves returns the society's leave and holiday entries that have not ended yet, or only the
+// staff member's own leave when staffID is set
+func (ss *StaffService) Leaves(ctx context.Context, societyID uint, staffID *uint) ([]models.StaffLeave, error) {
+	return ss.Staff.Leaves(ctx, societyID, staffID, time.Now().AddDate(0, 0, -1))
+}
+
+// CreateLeave records a leave period, or a society-wide holiday when the entry has no staff member
+func (ss *StaffService) CreateLeave(ctx context.Context, leave *models.StaffLeave) error {
+	return ss.Staff.CreateLeave(ctx, leave)
+}
+
+// DeleteLeave removes a leave or holiday entry of the society
+func (ss *StaffService) DeleteLeave(ctx context.Context, societyID, leaveID uint) error {
+	err := ss.Staff.DeleteLeave(ctx, societyID, leaveID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrLeaveNotFound
+	}
+	return err
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+)
+
+// 2025-01-03 is a Friday
+func friday(hour, minute int) time.Time {
+	return time.Date(2025, time.January, 3, hour, minute, 0, 0, time.UTC)
+}
+
+func TestIsOnShift(t *testing.T) {
+	weekdays := "1,2,3,4,5"
+	tests := []struct {
+		name    string
+		profile models.StaffProfile
+		at      time.Time
+		want    bool
+	}{
+		{"no profile works around the clock", models.StaffProfile{}, friday(3, 0), true},
+		{"day shift within hours", models.StaffProfile{ShiftStart: "09:00", ShiftEnd: "17:00"}, friday(9, 0), true},
+		{"day shift ends exclusively", models.StaffProfile{ShiftStart: "09:00", ShiftEnd: "17:00"}, friday(17, 0), false},
+		{"day shift before start", models.StaffProfile{ShiftStart: "09:00", ShiftEnd: "17:00"}, friday(8, 59), false},
+		{"working day without shift hours", models.StaffProfile{WorkingDays: weekdays}, friday(23, 0), true},
+		{"day off without shift hours", models.StaffProfile{WorkingDays: weekdays}, friday(23, 0).AddDate(0, 0, 1), false},
+		{"day shift on a day off", models.StaffProfile{ShiftStart: "09:00", ShiftEnd: "17:00", WorkingDays: weekdays}, friday(10, 0).AddDate(0, 0, 1), false},
+		{"friday night shift in the evening", models.StaffProfile{ShiftStart: "22:00", ShiftEnd: "06:00", WorkingDays: weekdays}, friday(22, 30), true},
+		{"friday night shift runs into saturday", models.StaffProfile{ShiftStart: "22:00", ShiftEnd: "06:00", WorkingDays: weekdays}, friday(2, 0).AddDate(0, 0, 1), true},
+		{"friday night shift ends saturday morning", models.StaffProfile{ShiftStart: "22:00", ShiftEnd: "06:00", WorkingDays: weekdays}, friday(6, 0).AddDate(0, 0, 1), false},
+		{"no saturday night shift", models.StaffProfile{ShiftStart: "22:00", ShiftEnd: "06:00", WorkingDays: weekdays}, friday(23, 0).AddDate(0, 0, 1), false},
+		{"no sunday night shift to run into monday", models.StaffProfile{ShiftStart: "22:00", ShiftEnd: "06:00", WorkingDays: weekdays}, friday(2, 0).AddDate(0, 0, 3), false},
+		{"night shift between its hours", models.StaffProfile{ShiftStart: "22:00", ShiftEnd: "06:00"}, friday(12, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isOnShift(tt.profile, tt.at); got != tt.want {
+				t.Errorf("isOnShift(%+v, %s) = %v, want %v", tt.profile, tt.at.Format("Mon 15:04"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAvailabilityReadsShiftsInTheSocietyTimezone(t *testing.T) {
+	staff := &StaffService{
+		Staff: newFakeStaff(models.StaffProfile{StaffID: testStaff.ID, ShiftStart: "09:00", ShiftEnd: "17:00", WorkingDays: "1,2,3,4,5"}),
+		Societies: &fakeSocieties{rows: map[uint]*models.Society{
+			testSociety: {ID: testSociety, Timezone: "Asia/Kolkata"},
+		}},
+		Complaints: newFakeComplaints(),
+	}
+
+	tests := []struct {
+		at   time.Time
+		want bool
+	}{
+		// 10:00 on Friday in Kolkata, before the shift starts in UTC
+		{friday(4, 30), true},
+		// 17:30 on Friday in Kolkata, still within the shift in UTC
+		{friday(12, 0), false},
+		// 05:00 on Saturday in Kolkata, still Friday in UTC
+		{friday(23, 30), false},
+	}
+	for _, tt := range tests {
+		availability, err := staff.Availability(context.Background(), testSociety, []uint{testStaff.ID}, tt.at)
+		if err != nil {
+			t.Fatalf("Availability: %v", err)
+		}
+		if got := availability[testStaff.ID].OnShift; got != tt.want {
+			t.Errorf("on shift at %s UTC = %v, want %v", tt.at.Format("Mon 15:04"), got, tt.want)
+		}
+	}
+}
+
+// leaveOn is a leave entry of the staff member, or a holiday when staffID is nil, for one day
+func leaveOn(staffID *uint, year int, month time.Month, day int) models.StaffLeave {
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	return models.StaffLeave{SocietyID: testSociety, StaffID: staffID, StartDate: date, EndDate: date}
+}
+
+func TestAvailabilityCombinesLeaveAndCapacity(t *testing.T) {
+	onLeave, full := testStaff.ID, testOtherStaff.ID
+	staff := newFakeStaff(models.StaffProfile{StaffID: full, MaxConcurrent: 1})
+	staff.leaves = []models.StaffLeave{leaveOn(&onLeave, 2025, time.January, 3), leaveOn(nil, 2025, time.January, 6)}
+	service := &StaffService{
+		Staff:      staff,
+		Societies:  &fakeSocieties{rows: map[uint]*models.Society{testSociety: {ID: testSociety}}},
+		Complaints: newFakeComplaints(models.Complaint{ID: 1, Status: "in-progress", SocietyID: testSociety, StaffID: &full}),
+	}
+	ctx := context.Background()
+	ids := []uint{onLeave, full, testThirdStaff.ID}
+
+	availability, err := service.Availability(ctx, testSociety, ids, friday(12, 0))
+	if err != nil {
+		t.Fatalf("Availability: %v", err)
+	}
+	if a := availability[onLeave]; a.Available() || a.Reason() != "staff member is on leave" {
+		t.Errorf("staff on leave: %+v, reason %q", a, a.Reason())
+	}
+	if a := availability[full]; a.Available() || !a.AtCapacity || a.Reason() != "staff member is at capacity (1/1 open complaints)" {
+		t.Errorf("staff at capacity: %+v, reason %q", a, a.Reason())
+	}
+	if a := availability[testThirdStaff.ID]; !a.Available() || a.Reason() != "" {
+		t.Errorf("free staff: %+v, reason %q", a, a.Reason())
+	}
+
+	members := []models.User{testStaff, testOtherStaff, testThirdStaff}
+	onDuty, err := service.FilterOnDuty(ctx, testSociety, members, friday(12, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Emergencies go to staff at capacity too, but never to staff on leave
+	if got := staffIDs(onDuty); len(got) != 2 || got[0] != full || got[1] != testThirdStaff.ID {
+		t.Errorf("on duty: %v, want the full and the free staff member", got)
+	}
+
+	// The holiday on Monday applies to everyone
+	availability, _ = service.Availability(ctx, testSociety, ids, friday(12, 0).AddDate(0, 0, 3))
+	for _, id := range ids {
+		if !availability[id].OnLeave {
+			t.Errorf("staff %d not on leave on a holiday", id)
+		}
+	}
+}
+
+func TestAvailabilityReadsLeaveOnTheSocietyCalendar(t *testing.T) {
+	onLeave := testStaff.ID
+	staff := newFakeStaff()
+	staff.leaves = []models.StaffLeave{leaveOn(&onLeave, 2025, time.January, 4)}
+	service := &StaffService{
+		Staff:      staff,
+		Societies:  &fakeSocieties{rows: map[uint]*models.Society{testSociety: {ID: testSociety, Timezone: "Asia/Kolkata"}}},
+		Complaints: newFakeComplaints(),
+	}
+
+	// Friday 20:00 UTC is already Saturday in Kolkata
+	availability, err := service.Availability(context.Background(), testSociety, []uint{onLeave}, friday(20, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !availability[onLeave].OnLeave {
+		t.Error("Saturday's leave should apply once it is Saturday in the society")
+	}
+	availability, _ = service.Availability(context.Background(), testSociety, []uint{onLeave}, friday(12, 0))
+	if availability[onLeave].OnLeave {
+		t.Error("Saturday's leave must not apply on Friday afternoon in the society")
+	}
+}
+
+func TestSaveProfileValidates(t *testing.T) {
+	service := &StaffService{Staff: newFakeStaff()}
+	tests := []struct {
+		name    string
+		profile models.StaffProfile
+		wantErr bool
+	}{
+		{"day shift", models.StaffProfile{ShiftStart: "09:00", ShiftEnd: "17:00", WorkingDays: "1,2,3,4,5", MaxConcurrent: 3}, false},
+		{"night shift", models.StaffProfile{ShiftStart: "22:00", ShiftEnd: "06:00"}, false},
+		{"no schedule", models.StaffProfile{}, false},
+		{"start without end", models.StaffProfile{ShiftStart: "09:00"}, true},
+		{"bad time", models.StaffProfile{ShiftStart: "9am", ShiftEnd: "17:00"}, true},
+		{"weekday out of range", models.StaffProfile{WorkingDays: "1,7"}, true},
+		{"weekday not a number", models.StaffProfile{WorkingDays: "mon"}, true},
+		{"negative capacity", models.StaffProfile{MaxConcurrent: -1}, true},
+	}
+	for _, tt := range tests {
+		profile := tt.profile
+		profile.StaffID = testStaff.ID
+		if err := service.SaveProfile(context.Background(), &profile); (err != nil) != tt.wantErr {
+			t.Errorf("%s: SaveProfile error = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
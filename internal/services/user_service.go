@@ -0,0 +1,349 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/repository"
+	"github.com/VinVorteX/flashtrack/internal/utils"
+)
+
+var (
+	ErrUserNotFound    = errors.New("user not found in this society")
+	ErrManageSelf      = errors.New("admins cannot change or remove their own account here")
+	ErrInvalidRole     = errors.New("role must be user, staff, admin or a custom role of the society")
+	ErrAlreadyInactive = errors.New("user is already deactivated")
+	ErrAlreadyActive   = errors.New("user is already active")
+)
+
+// UserFilter narrows a user listing
+type UserFilter struct {
+	Role   string
+	Status string // active, deactivated, deletion_requested or empty for all
+	Search string // matched against name and email
+	Limit  int
+	Offset int
+}
+
+// UserChanges holds the fields an admin may edit; nil fields are left alone
+type UserChanges struct {
+	Name   *string
+	Role   *string
+	UnitID *uint
+	// ClearUnit unlinks the user from their unit
+	ClearUnit bool
+}
+
+// ReleasedComplaints reports what happened to the open complaints of a removed staff member
+type ReleasedComplaints struct {
+	Reassigned int `json:"reassigned"`
+	Unassigned int `json:"unassigned"`
+}
+
+// UserService lets admins manage the accounts of their society
+type UserService struct {
+	Users      repository.UserRepository
+	Audit      repository.AuditRepository
+	Tx         repository.Transactor
+	Assignment *AssignmentService
+	Complaints *ComplaintService
+	Invites    *InviteService
+	Roles      *RoleService
+	Plans      *PlanService
+	Locations  *LocationService
+}
+
+// ValidateRegistration applies the society rules every new account must pass
+func (us *UserService) ValidateRegistration(ctx context.Context, user models.User) error {
+	// The role must be built in or defined by the society
+	if user.Role != "" {
+		exists, err := us.Roles.Exists(ctx, user.SocietyID, user.Role)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrInvalidRole
+		}
+	}
+
+	// Check if admin already exists for this society
+	if user.Role == "admin" {
+		counts, err := us.Users.CountByRole(ctx, user.SocietyID)
+		if err != nil {
+			return err
+		}
+		if counts["admin"] > 0 {
+			return errors.New("admin already exists for this society")
+		}
+	}
+
+	// Residents may be linked to their flat at registration
+	if user.UnitID != nil {
+		if err := us.Locations.ValidateUnit(ctx, user.SocietyID, *user.UnitID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List returns the society's users matching the filter and the total number of matches
+func (us *UserService) List(ctx context.Context, societyID uint, filter UserFilter) ([]models.User, int64, error) {
+	if filter.Limit <= 0 || filter.Limit > 200 {
+		filter.Limit = 50
+	}
+	return us.Users.List(ctx, repository.UserFilter{
+		SocietyID: societyID,
+		Role:      filter.Role,
+		Status:    filter.Status,
+		Search:    filter.Search,
+		Limit:     filter.Limit,
+		Offset:    filter.Offset,
+	})
+}
+
+// Find loads a user of the society
+func (us *UserService) Find(ctx context.Context, societyID, userID uint) (*models.User, error) {
+	user, err := us.Users.FindInSociety(ctx, societyID, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrUserNotFound
+	}
+	return user, err
+}
+
+// target loads a user the actor may manage, which excludes the actor themselves
+func (us *UserService) target(ctx context.Context, actor *models.User, userID uint) (*models.User, error) {
+	if actor.ID == userID {
+		return nil, ErrManageSelf
+	}
+	return us.Find(ctx, actor.SocietyID, userID)
+}
+
+// Update changes a user's name, role or unit. Staff moved to a role that cannot work complaints
+// hand back their open ones.
+func (us *UserService) Update(ctx context.Context, actor *models.User, userID uint, changes UserChanges) (*models.User, *ReleasedComplaints, error) {
+	user, err := us.target(ctx, actor, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	before := map[string]interface{}{"name": user.Name, "role": user.Role, "unit_id": user.UnitID}
+	updated := *user
+
+	if changes.Name != nil {
+		name := strings.TrimSpace(*changes.Name)
+		if name == "" {
+			return nil, nil, errors.New("name cannot be empty")
+		}
+		updated.Name = name
+	}
+	if changes.Role != nil {
+		if *changes.Role == "" {
+			return nil, nil, ErrInvalidRole
+		}
+		updated.Role = *changes.Role
+	}
+	if changes.ClearUnit {
+		updated.UnitID = nil
+	} else if changes.UnitID != nil {
+		updated.UnitID = changes.UnitID
+	}
+
+	// The registration rules decide who may become admin and which units are valid
+	check := models.User{SocietyID: user.SocietyID}
+	if updated.Role != user.Role {
+		check.Role = updated.Role
+	}
+	if !sameUnit(updated.UnitID, user.UnitID) {
+		check.UnitID = updated.UnitID
+	}
+	if err := us.ValidateRegistration(ctx, check); err != nil {
+		return nil, nil, err
+	}
+	release := false
+	if updated.Role != user.Role {
+		isStaff, err := us.Roles.IsStaffRole(ctx, user.SocietyID, updated.Role)
+		if err != nil {
+			return nil, nil, err
+		}
+		release = !isStaff
+	}
+	if user.IsActive() && SeatLimit(updated.Role) != SeatLimit(user.Role) {
+		if err := us.Plans.CheckSeats(ctx, user.SocietyID, updated.Role, 1); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	after := map[string]interface{}{"name": updated.Name, "role": updated.Role, "unit_id": updated.UnitID}
+	released := []models.Complaint{}
+	err = us.Tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := us.Users.Update(ctx, user.ID, map[string]interface{}{
+			"name":    updated.Name,
+			"role":    updated.Role,
+			"unit_id": updated.UnitID,
+		}); err != nil {
+			return err
+		}
+		if release {
+			if released, err = us.Complaints.Release(ctx, user.ID); err != nil {
+				return err
+			}
+		}
+		return us.audit(ctx, actor, AuditUserUpdated, user.ID, map[string]interface{}{"before": before, "after": after})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &updated, us.reassign(ctx, released, actor), nil
+}
+
+// Deactivate blocks the user from signing in and hands their open complaints to other staff
+func (us *UserService) Deactivate(ctx context.Context, actor *models.User, userID uint) (*models.User, *ReleasedComplaints, error) {
+	user, err := us.target(ctx, actor, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !user.IsActive() {
+		return nil, nil, ErrAlreadyInactive
+	}
+
+	now := time.Now()
+	released := []models.Complaint{}
+	err = us.Tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := us.Users.Update(ctx, user.ID, map[string]interface{}{"deactivated_at": &now}); err != nil {
+			return err
+		}
+		// Whatever their role, anyone still holding open complaints hands them back
+		if released, err = us.Complaints.Release(ctx, user.ID); err != nil {
+			return err
+		}
+		return us.audit(ctx, actor, AuditUserDeactivated, user.ID, nil)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user.DeactivatedAt = &now
+	return user, us.reassign(ctx, released, actor), nil
+}
+
+// Reactivate lets a deactivated user sign in again
+func (us *UserService) Reactivate(ctx context.Context, actor *models.User, userID uint) (*models.User, error) {
+	user, err := us.target(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsActive() {
+		return nil, ErrAlreadyActive
+	}
+	if err := us.Plans.CheckSeats(ctx, user.SocietyID, user.Role, 1); err != nil {
+		return nil, err
+	}
+
+	err = us.Tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := us.Users.Update(ctx, user.ID, map[string]interface{}{"deactivated_at": nil}); err != nil {
+			return err
+		}
+		return us.audit(ctx, actor, AuditUserReactivated, user.ID, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user.DeactivatedAt = nil
+	return user, nil
+}
+
+// ResetPassword sets a new password, or emails a reset link when password is empty.
+// It reports whether a link was sent.
+func (us *UserService) ResetPassword(ctx context.Context, actor *models.User, userID uint, password string) (bool, error) {
+	user, err := us.target(ctx, actor, userID)
+	if err != nil {
+		return false, err
+	}
+
+	method := "email_link"
+	if password != "" {
+		method = "set_by_admin"
+	}
+
+	var token string
+	err = us.Tx.Transaction(ctx, func(ctx context.Context) error {
+		if password != "" {
+			if err := us.Users.Update(ctx, user.ID, map[string]interface{}{"password": utils.HashPassword(password)}); err != nil {
+				return err
+			}
+		} else if token, err = us.Invites.Create(ctx, user.ID); err != nil {
+			return err
+		}
+		return us.audit(ctx, actor, AuditUserPasswordReset, user.ID, map[string]interface{}{"method": method})
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if token == "" {
+		return false, nil
+	}
+	return true, us.Invites.SendReset(user, token)
+}
+
+// Delete removes the account and its personal data. Complaints and feedback stay for the society's records.
+func (us *UserService) Delete(ctx context.Context, actor *models.User, userID uint) (*ReleasedComplaints, error) {
+	user, err := us.target(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	released := []models.Complaint{}
+	err = us.Tx.Transaction(ctx, func(ctx context.Context) error {
+		// Whatever their role, anyone still holding open complaints hands them back
+		if released, err = us.Complaints.Release(ctx, user.ID); err != nil {
+			return err
+		}
+		if err := us.Users.Delete(ctx, user.ID); err != nil {
+			return err
+		}
+		return us.audit(ctx, actor, AuditUserDeleted, user.ID,
+			map[string]interface{}{"name": user.Name, "email": user.Email, "role": user.Role})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return us.reassign(ctx, released, actor), nil
+}
+
+// audit records an action the actor took on one of the society's users
+func (us *UserService) audit(ctx context.Context, actor *models.User, action string, userID uint, details interface{}) error {
+	entry, err := newAuditEntry(actor.SocietyID, actor, action, "user", userID, details)
+	if err != nil {
+		return err
+	}
+	return us.Audit.Create(ctx, entry)
+}
+
+// reassign runs auto-assignment for released complaints; those nobody takes wait for the admin
+func (us *UserService) reassign(ctx context.Context, complaints []models.Complaint, actor *models.User) *ReleasedComplaints {
+	result := &ReleasedComplaints{}
+	for i := range complaints {
+		complaint := &complaints[i]
+		staff, err := us.Assignment.AutoAssign(ctx, complaint)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to reassign complaint", "complaint_id", complaint.ID, "error", err)
+		}
+		if staff != nil {
+			result.Reassigned++
+		} else {
+			result.Unassigned++
+		}
+		if err := us.Complaints.SyncDuplicates(ctx, complaint, actor.ID); err != nil {
+			slog.ErrorContext(ctx, "failed to sync duplicates of complaint", "complaint_id", complaint.ID, "error", err)
+		}
+	}
+	return result
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/utils"
+)
+
+var testOtherStaff = models.User{ID: 21, Name: "Other Staff", Role: "staff", SocietyID: testSociety}
+
+// userFixture is a UserService whose released complaints are reassigned by least workload
+type userFixture struct {
+	service    *UserService
+	users      *fakeUsers
+	complaints *fakeComplaints
+	audit      *fakeAudit
+}
+
+func newUserFixture(complaints ...models.Complaint) *userFixture {
+	f := &userFixture{
+		users:      newFakeUsers(testAdmin, testStaff, testOtherStaff, testResident),
+		complaints: newFakeComplaints(complaints...),
+		audit:      &fakeAudit{},
+	}
+	roles := &RoleService{Roles: &fakeRoles{}, Users: f.users}
+	societies := &fakeSocieties{rows: map[uint]*models.Society{
+		testSociety: {ID: testSociety, AssignmentStrategy: StrategyLeastWorkload},
+	}}
+	f.service = &UserService{
+		Users: f.users,
+		Audit: f.audit,
+		Tx:    fakeTx{},
+		Roles: roles,
+		Complaints: &ComplaintService{
+			Complaints: f.complaints,
+			Users:      f.users,
+			Tx:         fakeTx{},
+			Roles:      roles,
+		},
+		Assignment: &AssignmentService{
+			Complaints: f.complaints,
+			Users:      f.users,
+			Societies:  societies,
+			Roles:      roles,
+			Staff:      &StaffService{Staff: newFakeStaff(), Complaints: f.complaints, Societies: societies},
+		},
+	}
+	return f
+}
+
+func TestDeactivateReassignsOpenComplaints(t *testing.T) {
+	staffID := testStaff.ID
+	primaryID := uint(1)
+	f := newUserFixture(
+		models.Complaint{ID: 1, Title: "Leaking tap", Status: "in-progress", SocietyID: testSociety,
+			ResidentID: testResident.ID, StaffID: &staffID, CategoryID: 1},
+		models.Complaint{ID: 2, Title: "Tap leaking", Status: "in-progress", SocietyID: testSociety,
+			ResidentID: testResident.ID, StaffID: &staffID, CategoryID: 1, DuplicateOfID: &primaryID},
+	)
+
+	user, released, err := f.service.Deactivate(context.Background(), &testAdmin, testStaff.ID)
+	if err != nil {
+		t.Fatalf("Deactivate: %v", err)
+	}
+	if user.IsActive() {
+		t.Error("expected the user to be deactivated")
+	}
+	if released.Reassigned != 1 || released.Unassigned != 0 {
+		t.Errorf("released = %+v, want one reassigned primary", *released)
+	}
+
+	for _, id := range []uint{1, 2} {
+		c := f.complaints.rows[id]
+		if c.StaffID == nil || *c.StaffID != testOtherStaff.ID || c.Status != "in-progress" {
+			t.Errorf("complaint %d: staff %v status %q, want it with the other staff member", id, c.StaffID, c.Status)
+		}
+	}
+	if len(f.audit.rows) != 1 || f.audit.rows[0].Action != AuditUserDeactivated || f.audit.rows[0].TargetID != testStaff.ID {
+		t.Errorf("audit = %+v, want one deactivation of the staff member", f.audit.rows)
+	}
+}
+
+func TestDeactivateRefusesSelfAndInactiveUsers(t *testing.T) {
+	f := newUserFixture()
+	if _, _, err := f.service.Deactivate(context.Background(), &testAdmin, testAdmin.ID); !errors.Is(err, ErrManageSelf) {
+		t.Errorf("expected ErrManageSelf, got %v", err)
+	}
+
+	deactivated := time.Now()
+	f.users.rows[testResident.ID].DeactivatedAt = &deactivated
+	if _, _, err := f.service.Deactivate(context.Background(), &testAdmin, testResident.ID); !errors.Is(err, ErrAlreadyInactive) {
+		t.Errorf("expected ErrAlreadyInactive, got %v", err)
+	}
+	if len(f.audit.rows) != 0 {
+		t.Errorf("refused actions must not be audited, got %+v", f.audit.rows)
+	}
+}
+
+func TestChangeRoleReleasesComplaintsOnlyWhenLeavingStaffWork(t *testing.T) {
+	staffID := testStaff.ID
+	f := newUserFixture(models.Complaint{ID: 1, Title: "Leaking tap", Status: "in-progress", SocietyID: testSociety,
+		ResidentID: testResident.ID, StaffID: &staffID, CategoryID: 1})
+	f.service.Roles.Roles = &fakeRoles{rows: []models.Role{
+		customRole(1, "technician", PermComplaintViewAssigned, PermComplaintResolve),
+		customRole(2, "gatekeeper", PermComplaintViewAll),
+	}}
+	ctx := context.Background()
+
+	technician := "technician"
+	if _, released, err := f.service.Update(ctx, &testAdmin, testStaff.ID, UserChanges{Role: &technician}); err != nil {
+		t.Fatalf("move to technician: %v", err)
+	} else if released.Reassigned+released.Unassigned != 0 {
+		t.Errorf("released %+v, want a role that still works complaints to keep them", *released)
+	}
+	if c := f.complaints.rows[1]; *c.StaffID != testStaff.ID {
+		t.Errorf("complaint moved to %d", *c.StaffID)
+	}
+
+	gatekeeper := "gatekeeper"
+	user, released, err := f.service.Update(ctx, &testAdmin, testStaff.ID, UserChanges{Role: &gatekeeper})
+	if err != nil {
+		t.Fatalf("move to gatekeeper: %v", err)
+	}
+	if user.Role != gatekeeper || f.users.rows[testStaff.ID].Role != gatekeeper {
+		t.Errorf("role %q stored %q, want %q", user.Role, f.users.rows[testStaff.ID].Role, gatekeeper)
+	}
+	if released.Reassigned != 1 {
+		t.Errorf("released %+v, want the complaint reassigned", *released)
+	}
+	if c := f.complaints.rows[1]; c.StaffID == nil || *c.StaffID != testOtherStaff.ID {
+		t.Errorf("complaint with %v, want the other staff member", c.StaffID)
+	}
+	if len(f.audit.rows) != 2 || f.audit.rows[1].Action != AuditUserUpdated || !strings.Contains(f.audit.rows[1].Details, `"role":"gatekeeper"`) {
+		t.Errorf("audit = %+v, want both role changes recorded", f.audit.rows)
+	}
+}
+
+func TestUpdateRefusesInvalidChanges(t *testing.T) {
+	f := newUserFixture()
+	ctx := context.Background()
+	blank, unknown, admin := "  ", "janitor", "admin"
+
+	if _, _, err := f.service.Update(ctx, &testAdmin, testStaff.ID, UserChanges{Name: &blank}); err == nil {
+		t.Error("blank name accepted")
+	}
+	if _, _, err := f.service.Update(ctx, &testAdmin, testStaff.ID, UserChanges{Role: &unknown}); !errors.Is(err, ErrInvalidRole) {
+		t.Errorf("unknown role: expected ErrInvalidRole, got %v", err)
+	}
+	// The society already has its admin
+	if _, _, err := f.service.Update(ctx, &testAdmin, testStaff.ID, UserChanges{Role: &admin}); err == nil {
+		t.Error("second admin accepted")
+	}
+	if _, _, err := f.service.Update(ctx, &testAdmin, testAdmin.ID, UserChanges{Role: &unknown}); !errors.Is(err, ErrManageSelf) {
+		t.Errorf("own account: expected ErrManageSelf, got %v", err)
+	}
+	if f.users.rows[testStaff.ID].Role != "staff" || len(f.audit.rows) != 0 {
+		t.Errorf("refused updates changed %+v or were audited %+v", *f.users.rows[testStaff.ID], f.audit.rows)
+	}
+}
+
+func TestDeleteHandsBackComplaintsAndKeepsARecord(t *testing.T) {
+	staffID := testStaff.ID
+	f := newUserFixture(models.Complaint{ID: 1, Title: "Leaking tap", Status: "in-progress", SocietyID: testSociety,
+		ResidentID: testResident.ID, StaffID: &staffID, CategoryID: 1})
+	f.users.rows[testStaff.ID].Email = "staff@example.com"
+	f.users.rows[99] = &models.User{ID: 99, Role: "user", SocietyID: testSociety + 1}
+	ctx := context.Background()
+
+	if _, err := f.service.Delete(ctx, &testAdmin, 99); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("other society: expected ErrUserNotFound, got %v", err)
+	}
+	if _, err := f.service.Delete(ctx, &testAdmin, testAdmin.ID); !errors.Is(err, ErrManageSelf) {
+		t.Errorf("own account: expected ErrManageSelf, got %v", err)
+	}
+
+	released, err := f.service.Delete(ctx, &testAdmin, testStaff.ID)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok := f.users.rows[testStaff.ID]; ok {
+		t.Error("staff member still exists")
+	}
+	if released.Reassigned != 1 || *f.complaints.rows[1].StaffID != testOtherStaff.ID {
+		t.Errorf("released %+v, complaint with %v, want it reassigned", *released, f.complaints.rows[1].StaffID)
+	}
+	// The audit entry outlives the account, so it keeps who was removed
+	if len(f.audit.rows) != 1 || f.audit.rows[0].Action != AuditUserDeleted || !strings.Contains(f.audit.rows[0].Details, "staff@example.com") {
+		t.Errorf("audit = %+v, want the deletion with the removed email", f.audit.rows)
+	}
+}
+
+func TestResetPasswordSetsTheHash(t *testing.T) {
+	f := newUserFixture()
+	sent, err := f.service.ResetPassword(context.Background(), &testAdmin, testResident.ID, "new-secret")
+	if err != nil || sent {
+		t.Fatalf("ResetPassword = %v, %v, want the password set without a link", sent, err)
+	}
+	if hash := f.users.rows[testResident.ID].Password; hash == "" || hash == "new-secret" || !utils.CheckPassword(hash, "new-secret") {
+		t.Errorf("stored password %q, want a hash of the new password", hash)
+	}
+	if len(f.audit.rows) != 1 || !strings.Contains(f.audit.rows[0].Details, "set_by_admin") || strings.Contains(f.audit.rows[0].Details, "new-secret") {
+		t.Errorf("audit = %+v, want the method without the password", f.audit.rows)
+	}
+}
@@ -0,0 +1,95 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	startKey  = "telemetry:start"
+	parentKey = "telemetry:parent"
+)
+
+// GormPlugin times every database operation into DBQueryDuration and wraps it in a client span,
+// a child of the request's span when the query runs with the request context. Install it with
+// db.Use(telemetry.GormPlugin{}).
+type GormPlugin struct{}
+
+// Name identifies the plugin to gorm
+func (GormPlugin) Name() string {
+	return "flashtrack:telemetry"
+}
+
+// Initialize registers the callbacks around each kind of operation
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("*").Register("telemetry:before_create", beforeQuery("create")),
+		cb.Create().After("*").Register("telemetry:after_create", afterQuery("create")),
+		cb.Query().Before("*").Register("telemetry:before_query", beforeQuery("select")),
+		cb.Query().After("*").Register("telemetry:after_query", afterQuery("select")),
+		cb.Update().Before("*").Register("telemetry:before_update", beforeQuery("update")),
+		cb.Update().After("*").Register("telemetry:after_update", afterQuery("update")),
+		cb.Delete().Before("*").Register("telemetry:before_delete", beforeQuery("delete")),
+		cb.Delete().After("*").Register("telemetry:after_delete", afterQuery("delete")),
+		cb.Row().Before("*").Register("telemetry:before_row", beforeQuery("row")),
+		cb.Row().After("*").Register("telemetry:after_row", afterQuery("row")),
+		cb.Raw().Before("*").Register("telemetry:before_raw", beforeQuery("raw")),
+		cb.Raw().After("*").Register("telemetry:after_raw", afterQuery("raw")),
+	)
+}
+
+func beforeQuery(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		parent := db.Statement.Context
+		ctx, _ := Tracer().Start(parent, "db."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system.name", "postgresql"),
+				attribute.String("db.operation.name", operation),
+			))
+		db.Statement.Context = ctx
+		db.InstanceSet(parentKey, parent)
+		db.InstanceSet(startKey, time.Now())
+	}
+}
+
+func afterQuery(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		failed := db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound)
+
+		if start, ok := db.InstanceGet(startKey); ok {
+			DBQueryDuration.WithLabelValues(operation, table).Observe(time.Since(start.(time.Time)).Seconds())
+		}
+		if failed {
+			DBQueryErrors.WithLabelValues(operation, table).Inc()
+		}
+
+		// The SQL keeps its placeholders, so the values bound to it never reach the trace
+		span := trace.SpanFromContext(db.Statement.Context)
+		span.SetAttributes(
+			attribute.String("db.collection.name", table),
+			attribute.String("db.query.text", db.Statement.SQL.String()),
+			attribute.Int64("db.rows_affected", db.RowsAffected),
+		)
+		if failed {
+			span.RecordError(db.Error)
+			span.SetStatus(codes.Error, db.Error.Error())
+		}
+		span.End()
+
+		if parent, ok := db.InstanceGet(parentKey); ok {
+			db.Statement.Context = parent.(context.Context)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package telemetry
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type widget struct {
+	ID   uint
+	Name string
+}
+
+// useInMemoryTracing records every span in the returned exporter for the rest of the test
+func useInMemoryTracing(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		provider.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+var registerDriver sync.Once
+
+// openFailingDB returns a gorm database with the plugin installed whose every statement fails
+func openFailingDB(t *testing.T) *gorm.DB {
+	registerDriver.Do(func() { sql.Register("telemetry-failing", failingDriver{}) })
+	sqlDB, err := sql.Open("telemetry-failing", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Use(GormPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func sampleCount(t *testing.T, operation, table string) uint64 {
+	var m dto.Metric
+	if err := DBQueryDuration.WithLabelValues(operation, table).(prometheus.Metric).Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestGormPluginTimesAndTracesQueries(t *testing.T) {
+	exporter := useInMemoryTracing(t)
+	db := openFailingDB(t)
+
+	ctx, parent := Tracer().Start(context.Background(), "request")
+	before := sampleCount(t, "select", "widgets")
+	var widgets []widget
+	db.Session(&gorm.Session{DryRun: true}).WithContext(ctx).Where("name = ?", "secret-value").Find(&widgets)
+	parent.End()
+
+	if got := sampleCount(t, "select", "widgets") - before; got != 1 {
+		t.Errorf("expected one select on widgets to be timed, got %d", got)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 || spans[0].Name != "db.select" {
+		t.Fatalf("expected a db.select span then the request span, got %v", spans)
+	}
+	query := spans[0]
+	if query.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("expected the query span to be a child of the request span")
+	}
+	for _, attr := range query.Attributes {
+		switch attr.Key {
+		case "db.collection.name":
+			if attr.Value.AsString() != "widgets" {
+				t.Errorf("db.collection.name = %q, want widgets", attr.Value.AsString())
+			}
+		case "db.query.text":
+			if attr.Value.AsString() == "" {
+				t.Errorf("expected the query text on the span")
+			}
+		}
+		if attr.Value.AsString() == "secret-value" {
+			t.Errorf("bound value leaked into span attribute %s", attr.Key)
+		}
+	}
+}
+
+func TestGormPluginRecordsFailures(t *testing.T) {
+	exporter := useInMemoryTracing(t)
+	db := openFailingDB(t)
+
+	errorsBefore := testutil.ToFloat64(DBQueryErrors.WithLabelValues("create", "widgets"))
+	if err := db.Create(&widget{Name: "a"}).Error; err == nil {
+		t.Fatal("expected the create to fail")
+	}
+
+	if got := testutil.ToFloat64(DBQueryErrors.WithLabelValues("create", "widgets")) - errorsBefore; got != 1 {
+		t.Errorf("expected one failed create to be counted, got %v", got)
+	}
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "db.create" || spans[0].Status.Code != codes.Error {
+		t.Errorf("expected one failed db.create span, got %v", spans)
+	}
+}
+
+// failingDriver is a database/sql driver whose every statement fails
+type failingDriver struct{}
+
+var errUnavailable = errors.New("database unavailable")
+
+func (failingDriver) Open(string) (driver.Conn, error) { return failingConn{}, nil }
+
+type failingConn struct{}
+
+func (failingConn) Prepare(string) (driver.Stmt, error) { return nil, errUnavailable }
+func (failingConn) Close() error                        { return nil }
+func (failingConn) Begin() (driver.Tx, error)           { return failingTx{}, nil }
+
+type failingTx struct{}
+
+func (failingTx) Commit() error   { return nil }
+func (failingTx) Rollback() error { return nil }
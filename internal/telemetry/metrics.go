@@ -0,0 +1,120 @@
+// Package telemetry exposes Prometheus metrics and OpenTelemetry traces for the HTTP API, the
+// database and the notification pipeline.
+//
+// Metrics are package-level collectors registered on Registry and served by Handler. Spans are
+// started from the global OpenTelemetry tracer provider, which StartTracing replaces with an
+// OTLP exporter when an endpoint is configured; until then every span is a no-op.
+package telemetry
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "flashtrack"
+
+// Outcomes of a notification delivery attempt
+const (
+	Delivered = "delivered"
+	Failed    = "failed"
+)
+
+// ChannelWebSocket labels deliveries over the notification WebSocket
+const ChannelWebSocket = "websocket"
+
+var (
+	// Registry holds every collector below plus the Go runtime and process collectors
+	Registry = prometheus.NewRegistry()
+
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests served, by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time to serve an HTTP request, by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Time to run a database operation, by operation and table.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	DBQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_query_errors_total",
+		Help:      "Database operations that failed, by operation and table. Record-not-found is not counted.",
+	}, []string{"operation", "table"})
+
+	WebSocketConnections = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "websocket_connections",
+		Help:      "Notification WebSocket connections currently open.",
+	})
+
+	NotificationsCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notifications_created_total",
+		Help:      "Notifications stored, by type.",
+	}, []string{"type"})
+
+	NotificationDeliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notification_deliveries_total",
+		Help:      "Attempts to deliver a notification, by channel and outcome (delivered or failed).",
+	}, []string{"channel", "outcome"})
+
+	SLABreaches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sla_breaches_total",
+		Help:      "Complaints escalated for breaching an escalation policy, by policy trigger and action.",
+	}, []string{"trigger", "action"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		DBQueryDuration,
+		DBQueryErrors,
+		WebSocketConnections,
+		NotificationsCreated,
+		NotificationDeliveries,
+		SLABreaches,
+	)
+}
+
+// Handler serves Registry in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// Middleware records the rate, errors and duration of requests per route. Paths that match no
+// route are counted under "unmatched", so scanners probing random URLs cannot grow the label set.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		HTTPRequests.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		HTTPDuration.WithLabelValues(c.Request.Method, route).Observe(time.Since(start).Seconds())
+	}
+}
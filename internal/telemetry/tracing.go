@@ -0,0 +1,57 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/VinVorteX/flashtrack/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName identifies FlashTrack in traces; OTEL_SERVICE_NAME overrides it
+const ServiceName = "flashtrack"
+
+// Tracer returns the tracer for FlashTrack's own spans. It is looked up from the global provider
+// on each call, so a provider installed later, such as an in-memory one in tests, takes effect.
+func Tracer() trace.Tracer {
+	return otel.Tracer("github.com/VinVorteX/flashtrack")
+}
+
+// StartTracing installs a global tracer provider that batches spans to the configured OTLP/HTTP
+// collector and returns a function that flushes and stops it. Like OTEL_EXPORTER_OTLP_ENDPOINT,
+// the endpoint is the collector's base URL; spans are posted to its /v1/traces. Without an
+// endpoint nothing is installed and spans stay no-ops.
+func StartTracing(ctx context.Context, cfg config.TracingConfig) (func(context.Context) error, error) {
+	if cfg.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(strings.TrimSuffix(cfg.Endpoint, "/")+"/v1/traces"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", ServiceName)),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		// Follow the caller's sampling decision, sample a share of new traces
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
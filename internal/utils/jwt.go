@@ -5,21 +5,21 @@ import (
 	"strings"
 	"time"
 
-	"github.com/VinVorteX/flashtrack/config"
 	"github.com/VinVorteX/flashtrack/internal/models"
 	"github.com/golang-jwt/jwt/v5"
 )
 
 type Claims struct {
-	UserID    uint   `json:"user_id"`
-	Email     string `json:"email"`
-	Role      string `json:"role"`
-	SocietyID uint   `json:"society_id"`
+	UserID         uint   `json:"user_id"`
+	Email          string `json:"email"`
+	Role           string `json:"role"`
+	SocietyID      uint   `json:"society_id"`
+	ImpersonatorID *uint  `json:"impersonator_id,omitempty"` // set on support sessions opened by a platform super-admin
 	jwt.RegisteredClaims
 }
 
-// ParseJWT parses and validates a JWT token, returning the user info
-func ParseJWT(tokenString string) (*models.User, error) {
+// ParseJWT parses and validates a JWT token signed with secret, returning the user info
+func ParseJWT(tokenString, secret string) (*models.User, error) {
 	// Remove "Bearer " prefix if present
 	tokenString = strings.TrimPrefix(tokenString, "Bearer ")
 
@@ -33,9 +33,7 @@ func ParseJWT(tokenString string) (*models.User, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("invalid signing method")
 		}
-		// Get secret from environment
-		cfg := config.LoadConfig()
-		return []byte(cfg.JWTSecret), nil
+		return []byte(secret), nil
 	})
 
 	if err != nil {
@@ -53,19 +51,21 @@ func ParseJWT(tokenString string) (*models.User, error) {
 
 	// Return user info from claims
 	return &models.User{
-		ID:        claims.UserID,
-		Email:     claims.Email,
-		SocietyID: claims.SocietyID,
+		ID:             claims.UserID,
+		Email:          claims.Email,
+		SocietyID:      claims.SocietyID,
+		ImpersonatorID: claims.ImpersonatorID,
 	}, nil
 }
 
 // GenerateJWT creates a new JWT token for a user
 func GenerateJWT(user *models.User, secret string, duration time.Duration) (string, error) {
 	claims := &Claims{
-		UserID:    user.ID,
-		Email:     user.Email,
-		Role:      user.Role,
-		SocietyID: user.SocietyID,
+		UserID:         user.ID,
+		Email:          user.Email,
+		Role:           user.Role,
+		SocietyID:      user.SocietyID,
+		ImpersonatorID: user.ImpersonatorID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -1,23 +1,50 @@
 package database
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
 	"github.com/VinVorteX/flashtrack/config"
 	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/internal/telemetry"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/lib/pq"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 var DB *gorm.DB
 
-func Connect(cfg config.Config) {
-	db, err := gorm.Open(postgres.Open(cfg.DBUrl), &gorm.Config{})
+// Connect opens the database, migrates the schema and installs the tenant scopes and row-level
+// security before publishing the connection as DB
+func Connect(cfg config.DatabaseConfig) error {
+	db, err := gorm.Open(postgres.Open(cfg.URL), &gorm.Config{
+		// Slow and failed queries go to the JSON log under the request's ID. The SQL is logged
+		// with placeholders, so passwords and tokens bound as values never reach the log.
+		Logger: logger.NewSlogLogger(slog.Default(), logger.Config{
+			SlowThreshold:             200 * time.Millisecond,
+			LogLevel:                  logger.Warn,
+			IgnoreRecordNotFoundError: true,
+			ParameterizedQueries:      true,
+		}),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	sqlDB, err := db.DB()
 	if err != nil {
-		panic("failed to connect to database")
+		return fmt.Errorf("failed to connect to database: %w", err)
 	}
+	sqlDB.SetMaxOpenConns(int(cfg.MaxOpenConns))
+	sqlDB.SetMaxIdleConns(int(cfg.MaxIdleConns))
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
-	db.AutoMigrate(
+	err = db.AutoMigrate(
 		&models.User{},
 		&models.Complaint{},
 		&models.Society{},
@@ -25,7 +52,62 @@ func Connect(cfg config.Config) {
 		&models.Notification{},
 		&models.Feedback{},
 		&models.StaffPoints{},
+		&models.StaffCategory{},
+		&models.AssignmentCursor{},
+		&models.StaffProfile{},
+		&models.StaffLeave{},
+		&models.EscalationPolicy{},
+		&models.ComplaintEscalation{},
+		&models.ComplaintReopen{},
+		&models.Location{},
+		&models.Invite{},
+		&models.AuditLog{},
+		&models.Role{},
 	)
+	if err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	// Complaints assigned before the first assignment was recorded count from their current one
+	if err := db.Exec("UPDATE complaints SET first_assigned_at = assigned_at WHERE first_assigned_at IS NULL AND assigned_at IS NOT NULL").Error; err != nil {
+		return fmt.Errorf("failed to backfill first assignments: %w", err)
+	}
+
+	// Time and trace every query
+	if err := db.Use(telemetry.GormPlugin{}); err != nil {
+		return fmt.Errorf("failed to install telemetry: %w", err)
+	}
+
+	if err := RegisterTenantScopes(db); err != nil {
+		return fmt.Errorf("failed to register tenant scopes: %w", err)
+	}
+	if err := EnableRowLevelSecurity(db); err != nil {
+		return fmt.Errorf("failed to enable row-level security: %w", err)
+	}
 
 	DB = db
+	return nil
+}
+
+// Ping checks that the database answers
+func Ping(ctx context.Context) error {
+	if DB == nil {
+		return errors.New("database not connected")
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// Close closes the database connections
+func Close() error {
+	if DB == nil {
+		return nil
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
@@ -0,0 +1,154 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Roles used by row-level security. Requests run as TenantRole and only see their society's rows
+// of the protected tables. Members of PlatformRole, which the application's own login is granted,
+// bypass the policies; platform jobs such as escalation runs and society management use it.
+const (
+	TenantRole   = "flashtrack_tenant"
+	PlatformRole = "flashtrack_platform"
+)
+
+// societySetting is the session variable the policies compare society_id with
+const societySetting = "app.society_id"
+
+// currentSociety reads societySetting; it is NULL when unset so no tenant row matches
+const currentSociety = "NULLIF(current_setting('" + societySetting + "', true), '')::bigint"
+
+// EmailsInOtherSocieties names the function that returns which of a list of lowercased emails
+// belong to accounts outside a society. Emails are unique across societies, so imports must check
+// them against every society from a tenant session that row-level security keeps out of the others.
+const EmailsInOtherSocieties = "emails_in_other_societies"
+
+// rowPolicy is the condition a tenant's rows of a table satisfy. read may admit shared rows
+// that write does not.
+type rowPolicy struct {
+	read  string
+	write string
+}
+
+func ownRows(condition string) rowPolicy {
+	return rowPolicy{read: condition, write: condition}
+}
+
+// rlsPolicies lists the tables the database itself keeps apart per society
+var rlsPolicies = map[string]rowPolicy{
+	"users":         ownRows("society_id = " + currentSociety),
+	"complaints":    ownRows("society_id = " + currentSociety),
+	"feedbacks":     ownRows("complaint_id IN (SELECT id FROM complaints WHERE society_id = " + currentSociety + ")"),
+	"notifications": ownRows("user_id IN (SELECT id FROM users WHERE society_id = " + currentSociety + ")"),
+	"categories": {
+		read:  "society_id = " + currentSociety + " OR society_id = 0 OR society_id IS NULL",
+		write: "society_id = " + currentSociety,
+	},
+}
+
+// EnableRowLevelSecurity creates the tenant and platform roles, grants the tenant role access to
+// the application's tables and (re)creates the policies. It runs after AutoMigrate on every start,
+// so the connecting user needs CREATEROLE unless the roles were created and granted beforehand.
+func EnableRowLevelSecurity(db *gorm.DB) error {
+	statements := []string{
+		fmt.Sprintf(`DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = '%[1]s') THEN
+		CREATE ROLE %[1]s NOLOGIN;
+	END IF;
+	IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = '%[2]s') THEN
+		CREATE ROLE %[2]s NOLOGIN;
+	END IF;
+	IF NOT pg_has_role(current_user, '%[1]s', 'MEMBER') THEN
+		EXECUTE format('GRANT %[1]s TO %%I', current_user);
+	END IF;
+	IF NOT pg_has_role(current_user, '%[2]s', 'MEMBER') THEN
+		EXECUTE format('GRANT %[2]s TO %%I', current_user);
+	END IF;
+	EXECUTE format('GRANT USAGE ON SCHEMA %%I TO %[1]s', current_schema());
+	EXECUTE format('GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA %%I TO %[1]s', current_schema());
+	EXECUTE format('GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA %%I TO %[1]s', current_schema());
+END
+$$`, TenantRole, PlatformRole),
+		// Runs as its owner, a PlatformRole member, and only reveals whether the emails are taken
+		`CREATE OR REPLACE FUNCTION ` + EmailsInOtherSocieties + `(society bigint, candidates text[]) RETURNS SETOF text
+	LANGUAGE sql STABLE SECURITY DEFINER SET search_path FROM CURRENT AS $$
+	SELECT DISTINCT LOWER(email) FROM users WHERE LOWER(email) = ANY(candidates) AND society_id IS DISTINCT FROM society
+$$`,
+	}
+
+	for table, policy := range rlsPolicies {
+		statements = append(statements,
+			"ALTER TABLE "+table+" ENABLE ROW LEVEL SECURITY",
+			// Apply the policies to the table owner too, so only PlatformRole members bypass them
+			"ALTER TABLE "+table+" FORCE ROW LEVEL SECURITY",
+			"DROP POLICY IF EXISTS tenant_read ON "+table,
+			"CREATE POLICY tenant_read ON "+table+" FOR SELECT USING ("+policy.read+")",
+			"DROP POLICY IF EXISTS tenant_write ON "+table,
+			"CREATE POLICY tenant_write ON "+table+" USING ("+policy.write+") WITH CHECK ("+policy.write+")",
+			"DROP POLICY IF EXISTS platform_bypass ON "+table,
+			"CREATE POLICY platform_bypass ON "+table+" TO "+PlatformRole+" USING (true) WITH CHECK (true)",
+		)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return fmt.Errorf("%w: %s", err, strings.SplitN(statement, "\n", 2)[0])
+			}
+		}
+		return nil
+	})
+}
+
+// TenantSession returns a session for one request that is held to the society twice over: the
+// tenant scopes filter its statements and, because it runs as TenantRole with the society in
+// app.society_id, Postgres row-level security checks them as well. The session holds its own
+// connection so the settings cover every statement of the request, including transactions;
+// release must be called when the request is done to reset the connection and return it to the pool.
+//
+// The settings live on the connection rather than in a transaction around the request, so each
+// write commits as it happens and notifications pushed while the request runs see it. Services find
+// the session through Session, as WithSession stores it in the request's context.
+func TenantSession(ctx context.Context, societyID uint) (*gorm.DB, func(), error) {
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return nil, nil, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	release := func() {
+		reset := context.Background()
+		_, err := conn.ExecContext(reset, "RESET ROLE")
+		if err == nil {
+			_, err = conn.ExecContext(reset, "RESET "+societySetting)
+		}
+		if err != nil {
+			// Never hand a connection that may still carry a tenant back to the pool
+			conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}
+
+	if _, err := conn.ExecContext(ctx, "SELECT set_config($1, $2, false)", societySetting, strconv.FormatUint(uint64(societyID), 10)); err != nil {
+		release()
+		return nil, nil, err
+	}
+	if _, err := conn.ExecContext(ctx, "SET ROLE "+TenantRole); err != nil {
+		release()
+		return nil, nil, err
+	}
+
+	session := DB.WithContext(ctx)
+	session.Statement.ConnPool = conn
+	return Scoped(session, societyID), release, nil
+}
@@ -0,0 +1,217 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/VinVorteX/flashtrack/config"
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"github.com/VinVorteX/flashtrack/pkg/database/dbtest"
+	"gorm.io/gorm"
+)
+
+// rlsFixture is a society with one row in each table row-level security protects
+type rlsFixture struct {
+	society   models.Society
+	user      models.User
+	category  models.Category
+	complaint models.Complaint
+	feedback  models.Feedback
+	notice    models.Notification
+}
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	dbtest.Stop()
+	os.Exit(code)
+}
+
+// connectTestDatabase connects to the disposable test database, or skips when there is none
+func connectTestDatabase(t *testing.T) {
+	t.Helper()
+	url, err := dbtest.URL()
+	if errors.Is(err, dbtest.ErrUnavailable) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Default().Database
+	cfg.URL = url
+	if err := Connect(cfg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func seedRLSFixture(t *testing.T, marker string) *rlsFixture {
+	t.Helper()
+	f := &rlsFixture{society: models.Society{Name: marker}}
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Seeding runs on the application's own connection, which holds the platform role
+	must(DB.Create(&f.society).Error)
+	f.user = models.User{Name: marker, Email: marker + "@example.com", Role: "user", SocietyID: f.society.ID}
+	must(DB.Create(&f.user).Error)
+	f.category = models.Category{Name: marker, SocietyID: f.society.ID}
+	must(DB.Create(&f.category).Error)
+	f.complaint = models.Complaint{Title: marker, Status: "resolved", ResidentID: f.user.ID, SocietyID: f.society.ID, CategoryID: f.category.ID}
+	must(DB.Create(&f.complaint).Error)
+	f.feedback = models.Feedback{ComplaintID: f.complaint.ID, UserID: f.user.ID, Rating: 5}
+	must(DB.Create(&f.feedback).Error)
+	f.notice = models.Notification{UserID: f.user.ID, Title: marker}
+	must(DB.Create(&f.notice).Error)
+
+	t.Cleanup(func() {
+		DB.Delete(&f.notice)
+		DB.Delete(&f.feedback)
+		DB.Delete(&f.complaint)
+		DB.Delete(&f.category)
+		DB.Delete(&f.user)
+		DB.Delete(&f.society)
+	})
+	return f
+}
+
+func openTenantSession(t *testing.T, societyID uint) *gorm.DB {
+	t.Helper()
+	db, release, err := TenantSession(context.Background(), societyID)
+	if err != nil {
+		t.Fatalf("tenant session: %v", err)
+	}
+	t.Cleanup(release)
+	return db
+}
+
+// countRaw counts with raw SQL, which the tenant scopes leave alone, so only row-level security applies
+func countRaw(t *testing.T, db *gorm.DB, table string, id uint) int64 {
+	t.Helper()
+	var count int64
+	if err := db.Raw("SELECT count(*) FROM "+table+" WHERE id = ?", id).Scan(&count).Error; err != nil {
+		t.Fatalf("count %s: %v", table, err)
+	}
+	return count
+}
+
+func TestRowLevelSecurityHidesOtherSocieties(t *testing.T) {
+	connectTestDatabase(t)
+	nonce := time.Now().UnixNano()
+	a := seedRLSFixture(t, fmt.Sprintf("rls-a-%d", nonce))
+	b := seedRLSFixture(t, fmt.Sprintf("rls-b-%d", nonce))
+	db := openTenantSession(t, a.society.ID)
+
+	rows := map[string][2]uint{
+		"users":         {a.user.ID, b.user.ID},
+		"categories":    {a.category.ID, b.category.ID},
+		"complaints":    {a.complaint.ID, b.complaint.ID},
+		"feedbacks":     {a.feedback.ID, b.feedback.ID},
+		"notifications": {a.notice.ID, b.notice.ID},
+	}
+	for table, ids := range rows {
+		if got := countRaw(t, db, table, ids[0]); got != 1 {
+			t.Errorf("%s: own row not visible", table)
+		}
+		if got := countRaw(t, db, table, ids[1]); got != 0 {
+			t.Errorf("%s: other society's row visible", table)
+		}
+	}
+}
+
+func TestRowLevelSecurityBlocksCrossTenantWrites(t *testing.T) {
+	connectTestDatabase(t)
+	nonce := time.Now().UnixNano()
+	a := seedRLSFixture(t, fmt.Sprintf("rls-a-%d", nonce))
+	b := seedRLSFixture(t, fmt.Sprintf("rls-b-%d", nonce))
+	db := openTenantSession(t, a.society.ID)
+
+	update := db.Exec("UPDATE complaints SET title = 'changed' WHERE id = ?", b.complaint.ID)
+	if update.Error != nil || update.RowsAffected != 0 {
+		t.Errorf("update of another society's complaint: affected %d, err %v", update.RowsAffected, update.Error)
+	}
+	remove := db.Exec("DELETE FROM users WHERE id = ?", b.user.ID)
+	if remove.Error != nil || remove.RowsAffected != 0 {
+		t.Errorf("delete of another society's user: affected %d, err %v", remove.RowsAffected, remove.Error)
+	}
+
+	insert := db.Exec("INSERT INTO complaints (title, status, resident_id, society_id, category_id) VALUES ('x', 'pending', ?, ?, ?)",
+		b.user.ID, b.society.ID, b.category.ID)
+	if insert.Error == nil {
+		t.Error("insert into another society was allowed")
+	}
+	move := db.Exec("UPDATE complaints SET society_id = ? WHERE id = ?", b.society.ID, a.complaint.ID)
+	if move.Error == nil {
+		t.Error("moving a complaint to another society was allowed")
+	}
+
+	var title string
+	DB.Raw("SELECT title FROM complaints WHERE id = ?", b.complaint.ID).Scan(&title)
+	if title == "changed" {
+		t.Error("another society's complaint was changed")
+	}
+}
+
+func TestRowLevelSecuritySharesGlobalCategoriesReadOnly(t *testing.T) {
+	connectTestDatabase(t)
+	a := seedRLSFixture(t, fmt.Sprintf("rls-a-%d", time.Now().UnixNano()))
+
+	shared := models.Category{Name: "rls-shared"}
+	if err := DB.Create(&shared).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { DB.Delete(&shared) })
+
+	db := openTenantSession(t, a.society.ID)
+	if got := countRaw(t, db, "categories", shared.ID); got != 1 {
+		t.Error("shared category not visible")
+	}
+	update := db.Exec("UPDATE categories SET name = 'changed' WHERE id = ?", shared.ID)
+	if update.Error != nil || update.RowsAffected != 0 {
+		t.Errorf("update of a shared category: affected %d, err %v", update.RowsAffected, update.Error)
+	}
+}
+
+func TestPlatformRoleBypassesRowLevelSecurity(t *testing.T) {
+	connectTestDatabase(t)
+	nonce := time.Now().UnixNano()
+	a := seedRLSFixture(t, fmt.Sprintf("rls-a-%d", nonce))
+	b := seedRLSFixture(t, fmt.Sprintf("rls-b-%d", nonce))
+
+	for _, id := range []uint{a.complaint.ID, b.complaint.ID} {
+		if got := countRaw(t, DB, "complaints", id); got != 1 {
+			t.Errorf("platform connection cannot see complaint %d", id)
+		}
+	}
+}
+
+func TestTenantSessionResetsConnection(t *testing.T) {
+	connectTestDatabase(t)
+	a := seedRLSFixture(t, fmt.Sprintf("rls-a-%d", time.Now().UnixNano()))
+
+	// With a single connection the next query is guaranteed to reuse the released one
+	sqlDB, err := DB.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.SetMaxOpenConns(0) })
+
+	_, release, err := TenantSession(context.Background(), a.society.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+
+	var role, society string
+	DB.Raw("SELECT current_user::text, coalesce(current_setting('" + societySetting + "', true), '')").Row().Scan(&role, &society)
+	if role == TenantRole || society != "" {
+		t.Errorf("released connection still runs as %q for society %q", role, society)
+	}
+}
@@ -0,0 +1,177 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tenantKey is the statement setting that carries the society a session is scoped to
+const tenantKey = "tenant:society_id"
+
+// ErrCrossTenantWrite is returned when a scoped session tries to create a row in another society
+var ErrCrossTenantWrite = errors.New("cannot write a row that belongs to another society")
+
+// tenantScope returns the condition restricting a table to one society. write is true for
+// updates and deletes, which may not touch shared rows that reads can see.
+type tenantScope func(table string, societyID uint, write bool) clause.Expression
+
+// ownedBySociety scopes tables with a society_id column
+func ownedBySociety(table string, societyID uint, _ bool) clause.Expression {
+	return clause.Eq{Column: clause.Column{Table: table, Name: "society_id"}, Value: societyID}
+}
+
+// ownedThrough scopes tables that belong to a society through a parent row
+func ownedThrough(column, parent string) tenantScope {
+	return func(table string, societyID uint, _ bool) clause.Expression {
+		return clause.Expr{
+			SQL:  "? IN (SELECT id FROM " + parent + " WHERE society_id = ?)",
+			Vars: []interface{}{clause.Column{Table: table, Name: column}, societyID},
+		}
+	}
+}
+
+// tenantScopes lists every tenant-owned table and how its rows map to a society
+var tenantScopes = map[string]tenantScope{
+	"users":                 ownedBySociety,
+	"complaints":            ownedBySociety,
+	"locations":             ownedBySociety,
+	"escalation_policies":   ownedBySociety,
+	"staff_categories":      ownedBySociety,
+	"assignment_cursors":    ownedBySociety,
+	"staff_profiles":        ownedBySociety,
+	"staff_leaves":          ownedBySociety,
+	"roles":                 ownedBySociety,
+	"audit_logs":            ownedBySociety,
+	"feedbacks":             ownedThrough("complaint_id", "complaints"),
+	"complaint_reopens":     ownedThrough("complaint_id", "complaints"),
+	"complaint_escalations": ownedThrough("complaint_id", "complaints"),
+	"notifications":         ownedThrough("user_id", "users"),
+	"invites":               ownedThrough("user_id", "users"),
+	"staff_points":          ownedThrough("staff_id", "users"),
+
+	// Categories without a society are shared by every society and may be read but not changed
+	"categories": func(table string, societyID uint, write bool) clause.Expression {
+		own := ownedBySociety(table, societyID, write)
+		if write {
+			return own
+		}
+		column := clause.Column{Table: table, Name: "society_id"}
+		return clause.Or(own, clause.Eq{Column: column, Value: 0}, clause.Eq{Column: column, Value: nil})
+	},
+
+	// A society can only see itself
+	"societies": func(table string, societyID uint, _ bool) clause.Expression {
+		return clause.Eq{Column: clause.Column{Table: table, Name: "id"}, Value: societyID}
+	},
+}
+
+// ForSociety returns a session that only reads and writes the society's rows of tenant-owned tables.
+// Raw SQL is passed through unchanged, so scoped code should stick to the query builder.
+func ForSociety(societyID uint) *gorm.DB {
+	return Scoped(DB, societyID)
+}
+
+// Scoped restricts db to the society's rows of tenant-owned tables
+func Scoped(db *gorm.DB, societyID uint) *gorm.DB {
+	return db.Set(tenantKey, societyID).Session(&gorm.Session{})
+}
+
+// RegisterTenantScopes installs the callbacks that apply ForSociety sessions' society filter.
+// Connect calls it; tests that open their own connection should call it too.
+func RegisterTenantScopes(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:query", scopeRead); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("tenant:row", scopeRead); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:update", scopeWrite); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("tenant:delete", scopeWrite); err != nil {
+		return err
+	}
+	return callbacks.Create().Before("gorm:create").Register("tenant:create", stampSociety)
+}
+
+func tenantOf(db *gorm.DB) (uint, bool) {
+	value, ok := db.Statement.Settings.Load(tenantKey)
+	if !ok {
+		return 0, false
+	}
+	societyID, ok := value.(uint)
+	return societyID, ok
+}
+
+func scopeRead(db *gorm.DB)  { addTenantCondition(db, false) }
+func scopeWrite(db *gorm.DB) { addTenantCondition(db, true) }
+
+func addTenantCondition(db *gorm.DB, write bool) {
+	societyID, ok := tenantOf(db)
+	if !ok || db.Statement.SQL.Len() > 0 {
+		return
+	}
+	scope, ok := tenantScopes[db.Statement.Table]
+	if !ok {
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{scope(db.Statement.Table, societyID, write)}})
+}
+
+// stampSociety fills in the society of new rows and refuses rows meant for another society
+func stampSociety(db *gorm.DB) {
+	societyID, ok := tenantOf(db)
+	if !ok || db.Statement.Schema == nil {
+		return
+	}
+	field := db.Statement.Schema.LookUpField("SocietyID")
+	if field == nil {
+		return
+	}
+
+	stamp := func(row reflect.Value) {
+		value, zero := field.ValueOf(db.Statement.Context, row)
+		if zero {
+			if err := field.Set(db.Statement.Context, row, societyID); err != nil {
+				db.AddError(err)
+			}
+			return
+		}
+		if id, ok := value.(uint); ok && id != societyID {
+			db.AddError(ErrCrossTenantWrite)
+		}
+	}
+
+	switch rv := reflect.Indirect(db.Statement.ReflectValue); rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			stamp(reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		stamp(rv)
+	}
+}
+
+type sessionKey struct{}
+
+// WithSession returns a context carrying db, so code that only receives the context
+// (repositories in particular) uses the same tenant-scoped session or transaction
+func WithSession(ctx context.Context, db *gorm.DB) context.Context {
+	return context.WithValue(ctx, sessionKey{}, db)
+}
+
+// Session returns the session carried by ctx, or DB bound to ctx for background work that has none
+func Session(ctx context.Context) *gorm.DB {
+	if db, ok := ctx.Value(sessionKey{}).(*gorm.DB); ok {
+		return db
+	}
+	if DB == nil {
+		return nil
+	}
+	return DB.WithContext(ctx)
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/VinVorteX/flashtrack/internal/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunDB builds statements without a database so the generated SQL can be inspected
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+	})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if err := RegisterTenantScopes(db); err != nil {
+		t.Fatalf("register scopes: %v", err)
+	}
+	return db
+}
+
+func sqlOf(stmt *gorm.DB) string {
+	return stmt.Statement.SQL.String()
+}
+
+func TestScopedQueryFiltersBySociety(t *testing.T) {
+	db := dryRunDB(t)
+
+	var complaints []models.Complaint
+	stmt := Scoped(db, 7).Where("status = ?", "open").Find(&complaints)
+	if sql := sqlOf(stmt); !strings.Contains(sql, `"complaints"."society_id" = $`) {
+		t.Fatalf("complaints query not scoped: %s", sql)
+	}
+	if vars := stmt.Statement.Vars; len(vars) != 2 || vars[1] != uint(7) {
+		t.Fatalf("unexpected vars %v", vars)
+	}
+}
+
+func TestScopedQueryFiltersThroughParent(t *testing.T) {
+	db := dryRunDB(t)
+
+	var feedbacks []models.Feedback
+	sql := sqlOf(Scoped(db, 7).Find(&feedbacks))
+	if !strings.Contains(sql, `"feedbacks"."complaint_id" IN (SELECT id FROM complaints WHERE society_id = $1)`) {
+		t.Fatalf("feedbacks query not scoped through complaints: %s", sql)
+	}
+}
+
+func TestScopedCategoriesShareGlobalRowsOnlyForReads(t *testing.T) {
+	db := dryRunDB(t)
+
+	var categories []models.Category
+	read := sqlOf(Scoped(db, 7).Find(&categories))
+	if !strings.Contains(read, `"categories"."society_id" IS NULL`) {
+		t.Fatalf("category reads should include shared categories: %s", read)
+	}
+
+	write := sqlOf(Scoped(db, 7).Model(&models.Category{}).Where("id = ?", 1).Update("name", "x"))
+	if strings.Contains(write, "IS NULL") || !strings.Contains(write, `"categories"."society_id" = $`) {
+		t.Fatalf("category writes should only touch the society's own rows: %s", write)
+	}
+}
+
+func TestScopedScopesUpdatesAndDeletes(t *testing.T) {
+	db := dryRunDB(t)
+
+	update := sqlOf(Scoped(db, 7).Model(&models.User{}).Where("id = ?", 3).Update("name", "x"))
+	if !strings.Contains(update, `"users"."society_id" = $`) {
+		t.Fatalf("update not scoped: %s", update)
+	}
+
+	del := sqlOf(Scoped(db, 7).Where("id = ?", 3).Delete(&models.Location{}))
+	if !strings.Contains(del, `"locations"."society_id" = $`) {
+		t.Fatalf("delete not scoped: %s", del)
+	}
+}
+
+func TestUnscopedAndRawQueriesAreUntouched(t *testing.T) {
+	db := dryRunDB(t)
+
+	var complaints []models.Complaint
+	if sql := sqlOf(db.Find(&complaints)); strings.Contains(sql, "society_id") {
+		t.Fatalf("unscoped query gained a filter: %s", sql)
+	}
+
+	var count int64
+	if sql := sqlOf(Scoped(db, 7).Raw("SELECT count(*) FROM complaints").Scan(&count)); strings.Contains(sql, "society_id") {
+		t.Fatalf("raw query gained a filter: %s", sql)
+	}
+}
+
+func TestScopedCreateStampsSociety(t *testing.T) {
+	db := dryRunDB(t)
+
+	location := models.Location{Name: "Block A"}
+	if err := Scoped(db, 7).Create(&location).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if location.SocietyID != 7 {
+		t.Fatalf("society not stamped, got %d", location.SocietyID)
+	}
+
+	foreign := models.Location{Name: "Block B", SocietyID: 8}
+	if err := Scoped(db, 7).Create(&foreign).Error; !errors.Is(err, ErrCrossTenantWrite) {
+		t.Fatalf("expected ErrCrossTenantWrite, got %v", err)
+	}
+}